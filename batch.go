@@ -0,0 +1,40 @@
+package cryptomus
+
+import (
+	"context"
+	"sync"
+)
+
+// runBatch calls send for each of n items, with up to concurrency calls running at a time, and returns the error of each item in order.
+//
+// Once ctx is done, the items not sent yet are skipped with the error of ctx, which is then also returned as err. An item sent before keeps the error of send.
+func runBatch(ctx context.Context, n, concurrency int, send func(i int) error) (errs []error, err error) {
+	errs = make([]error, n)
+	semaphore := make(chan struct{}, max(concurrency, 1))
+	var wg sync.WaitGroup
+
+	for i := range n {
+		// Checked first since select picks at random between a done ctx and a free slot.
+		if err = ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			errs[i] = err
+			continue
+		case semaphore <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			errs[i] = send(i)
+		}()
+	}
+	wg.Wait()
+
+	return errs, err
+}
@@ -1,10 +1,8 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strings"
 )
 
 // You need to pass one of the required parameters, if you pass both, the account will be identified by order_id
@@ -43,6 +41,10 @@ type BlockStaticWalletResponse struct {
 	//  - blocked
 	//  - active
 	//  - in_active
+	//
+	// Cryptomus has no endpoint to read the status of a static wallet without changing it: BlockStaticWallet is the only method that returns it.
+	// CreateStaticWallet with the order_id of an existing wallet returns that wallet, but without its status.
+	// Keep the status in your system, e.g. record "blocked" when BlockStaticWallet succeeds.
 	Status string `json:"status"`
 }
 
@@ -88,7 +90,16 @@ type BlockStaticWalletResponse struct {
 //		"error": null
 //	}
 func (m *Merchant) BlockStaticWallet(request BlockStaticWalletRequest) (*BlockStaticWalletResponse, error) {
-	httpResponse, err := m.sendPaymentRequest("POST", urlBlockStaticWallet, request)
+	return m.BlockStaticWalletContext(context.Background(), request)
+}
+
+// BlockStaticWalletContext is like BlockStaticWallet but sends the request with the provided context.
+func (m *Merchant) BlockStaticWalletContext(ctx context.Context, request BlockStaticWalletRequest) (*BlockStaticWalletResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating request: %w", err)
+	}
+
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlBlockStaticWallet, request)
 	if err != nil {
 		return nil, err
 	}
@@ -98,27 +109,16 @@ func (m *Merchant) BlockStaticWallet(request BlockStaticWalletRequest) (*BlockSt
 		State   int                       `json:"state"`
 		Result  BlockStaticWalletResponse `json:"result"`
 		Message string                    `json:"message"`
-		Errors  struct {
-			IsForceRefund []string `json:"is_force_refund"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		Errors  responseErrors            `json:"errors"`
+		Code    int                       `json:"code"`
+		Error   string                    `json:"error"`
 	}{}
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.IsForceRefund...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
@@ -1,10 +1,8 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strings"
 )
 
 // See "Calculate convert" https://doc.cryptomus.com/personal/converts/calculate
@@ -73,7 +71,12 @@ type CalculateConvertResponse struct {
 //		}
 //	}
 func (u *User) CalculateConvert(request Convert) (*CalculateConvertResponse, error) {
-	httpResponse, err := u.sendPaymentRequest("POST", urlCalculateConvert, request)
+	return u.CalculateConvertContext(context.Background(), request)
+}
+
+// CalculateConvertContext is like CalculateConvert but sends the request with the provided context.
+func (u *User) CalculateConvertContext(ctx context.Context, request Convert) (*CalculateConvertResponse, error) {
+	httpResponse, err := u.sendPaymentRequest(ctx, "POST", urlCalculateConvert, request)
 	if err != nil {
 		return nil, err
 	}
@@ -83,34 +86,17 @@ func (u *User) CalculateConvert(request Convert) (*CalculateConvertResponse, err
 		State   int                      `json:"state"`
 		Result  CalculateConvertResponse `json:"result"`
 		Message string                   `json:"message"`
-		Errors  struct {
-			From       []string `json:"from"`
-			To         []string `json:"to"`
-			FromAmount []string `json:"from_amount"`
-			ToAmount   []string `json:"to_amount"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		Errors  responseErrors           `json:"errors"`
+		Code    int                      `json:"code"`
+		Error   string                   `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := u.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.From...)
-	errs = append(errs, response.Errors.To...)
-	errs = append(errs, response.Errors.FromAmount...)
-	errs = append(errs, response.Errors.ToAmount...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
@@ -1,9 +1,8 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
 	"strings"
 )
 
@@ -29,37 +28,66 @@ import (
 //		}
 //	}
 func (u *User) CancelLimitOrder(orderUuid string) (*MarketOrder, error) {
+	return u.CancelLimitOrderContext(context.Background(), orderUuid)
+}
+
+// CancelLimitOrderContext is like CancelLimitOrder but sends the request with the provided context.
+func (u *User) CancelLimitOrderContext(ctx context.Context, orderUuid string) (*MarketOrder, error) {
 	url := fmt.Sprintf(urlCancelLimitOrder, orderUuid)
 
-	httpResponse, err := u.sendPaymentRequest("DELETE", url, nil)
+	httpResponse, err := u.sendPaymentRequest(ctx, "DELETE", url, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer httpResponse.Body.Close()
 
 	var response = struct {
-		State   int         `json:"state"`
-		Result  MarketOrder `json:"result"`
-		Message string      `json:"message"`
-		Code    int         `json:"code"`
-		Error   string      `json:"error"`
+		State   int            `json:"state"`
+		Result  MarketOrder    `json:"result"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := u.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
+
+	return &response.Result, nil
+}
+
+// CancelLimitOrders cancels every active limit order converting from one currency to another, e.g. USDT to BTC, and returns the cancelled orders.
+//
+// It lists the orders with ListOrderHistory and cancels them one by one with CancelLimitOrder, going on past orders that fail to cancel.
+// errs has an error for every such order, naming it, or a single error if the orders could not be listed. Currency codes are matched case-insensitively.
+func (u *User) CancelLimitOrders(from, to string) (cancelled []MarketOrder, errs []error) {
+	return u.CancelLimitOrdersContext(context.Background(), from, to)
+}
+
+// CancelLimitOrdersContext is like CancelLimitOrders but sends the requests with the provided context.
+func (u *User) CancelLimitOrdersContext(ctx context.Context, from, to string) (cancelled []MarketOrder, errs []error) {
+	orders, err := u.ListOrderHistoryContext(ctx, "limit", "active")
+	if err != nil {
+		return nil, []error{fmt.Errorf("error listing active limit orders: %w", err)}
 	}
 
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	for _, order := range orders {
+		if !strings.EqualFold(order.ConvertCurrencyFrom, from) || !strings.EqualFold(order.ConvertCurrencyTo, to) {
+			continue
+		}
+
+		result, err := u.CancelLimitOrderContext(ctx, order.OrderID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error cancelling limit order %s: %w", order.OrderID, err))
+			continue
+		}
+		cancelled = append(cancelled, *result)
 	}
 
-	return &response.Result, nil
+	return cancelled, errs
 }
@@ -0,0 +1,49 @@
+package cryptomus_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestCancelLimitOrders(t *testing.T) {
+	var cancelRequests []string
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			query := r.URL.Query()
+			if query.Get("type") != "limit" || query.Get("status") != "active" {
+				t.Errorf("unexpected order list query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"state": 0, "result": {"items": [
+				{"order_id": "1", "convert_currency_from": "USDT", "convert_currency_to": "BTC", "type": "limit", "status": "active"},
+				{"order_id": "2", "convert_currency_from": "USDT", "convert_currency_to": "ETH", "type": "limit", "status": "active"},
+				{"order_id": "3", "convert_currency_from": "usdt", "convert_currency_to": "btc", "type": "limit", "status": "active"},
+				{"order_id": "4", "convert_currency_from": "USDT", "convert_currency_to": "BTC", "type": "limit", "status": "active"}
+			], "paginate": {"nextCursor": null}}}`))
+			return
+		}
+
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		cancelRequests = append(cancelRequests, id)
+		if id == "3" {
+			w.Write([]byte(`{"state": 1, "message": "Order already completed"}`))
+			return
+		}
+		w.Write([]byte(`{"state": 0, "result": {"order_id": "` + id + `", "type": "limit", "status": "cancelled"}}`))
+	})
+
+	user := cryptomus.NewUser("user", "payment-key", "payout-key")
+	cancelled, errs := user.CancelLimitOrders("USDT", "BTC")
+
+	if strings.Join(cancelRequests, ",") != "1,3,4" {
+		t.Errorf("cancelled orders %v, want 1, 3 and 4", cancelRequests)
+	}
+	if len(cancelled) != 2 || cancelled[0].OrderID != "1" || cancelled[1].OrderID != "4" {
+		t.Errorf("got cancelled orders %+v", cancelled)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "3") || !strings.Contains(errs[0].Error(), "Order already completed") {
+		t.Errorf("got errors %v, want one for order 3", errs)
+	}
+}
@@ -1,10 +1,8 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strings"
 )
 
 // See "Cancel recurring payment" https://doc.cryptomus.com/business/recurring/cancel
@@ -33,7 +31,16 @@ import (
 //		}
 //	}
 func (m *Merchant) CancelRecurringPayment(request RecordID) (*RecurringPayment, error) {
-	httpResponse, err := m.sendPaymentRequest("POST", urlCancelRecurringPayment, request)
+	return m.CancelRecurringPaymentContext(context.Background(), request)
+}
+
+// CancelRecurringPaymentContext is like CancelRecurringPayment but sends the request with the provided context.
+func (m *Merchant) CancelRecurringPaymentContext(ctx context.Context, request RecordID) (*RecurringPayment, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating request: %w", err)
+	}
+
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlCancelRecurringPayment, request)
 	if err != nil {
 		return nil, err
 	}
@@ -43,30 +50,17 @@ func (m *Merchant) CancelRecurringPayment(request RecordID) (*RecurringPayment,
 		State   int              `json:"state"`
 		Result  RecurringPayment `json:"result"`
 		Message string           `json:"message"`
-		Errors  struct {
-			UUID    []string `json:"uuid"`
-			OrderID []string `json:"order_id"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		Errors  responseErrors   `json:"errors"`
+		Code    int              `json:"code"`
+		Error   string           `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.UUID...)
-	errs = append(errs, response.Errors.OrderID...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
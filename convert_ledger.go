@@ -0,0 +1,88 @@
+package cryptomus
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ConvertEntry is a normalized convert order, ready to be exported as a ledger line.
+type ConvertEntry struct {
+	// Id of convert
+	OrderID string
+	// Order type (market or limit)
+	Type string
+	// Order status
+	Status string
+	// Currency converted from
+	FromCurrency string
+	// Amount converted from. The executed amount is used when available, otherwise the requested amount.
+	FromAmount string
+	// Currency converted to
+	ToCurrency string
+	// Amount converted to. The executed amount is used when available, otherwise the requested amount.
+	ToAmount string
+	// Rate of the convert
+	Rate string
+	// Date time the order was created
+	CreatedAt time.Time
+	// Date time the order was completed, zero if the order is not completed
+	CompletedAt time.Time
+}
+
+// ConvertLedger fetches every page of the convert order history and returns the orders created between from and to (inclusive) as ledger entries, in the order returned by Cryptomus.
+//
+// A zero from or to leaves that side of the range open.
+//
+// See "Get orders list" https://doc.cryptomus.com/personal/converts/orders-list
+func (u *User) ConvertLedger(from, to time.Time) ([]ConvertEntry, error) {
+	return u.ConvertLedgerContext(context.Background(), from, to)
+}
+
+// ConvertLedgerContext is like ConvertLedger but sends every page request with the provided context.
+func (u *User) ConvertLedgerContext(ctx context.Context, from, to time.Time) ([]ConvertEntry, error) {
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return nil, fmt.Errorf("invalid date range: from %s is after to %s", from, to)
+	}
+
+	orders, err := u.ListOrderHistoryContext(ctx, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("error listing order history: %w", err)
+	}
+
+	var entries []ConvertEntry
+	for _, order := range orders {
+		if !from.IsZero() && order.CreatedAt.Before(from) {
+			continue
+		}
+		if !to.IsZero() && order.CreatedAt.After(to) {
+			continue
+		}
+		entries = append(entries, newConvertEntry(order))
+	}
+
+	return entries, nil
+}
+
+func newConvertEntry(order MarketOrder) ConvertEntry {
+	entry := ConvertEntry{
+		OrderID:      order.OrderID,
+		Type:         order.Type,
+		Status:       order.Status,
+		FromCurrency: order.ConvertCurrencyFrom,
+		FromAmount:   order.ConvertAmountFrom,
+		ToCurrency:   order.ConvertCurrencyTo,
+		ToAmount:     order.ConvertAmountTo,
+		Rate:         order.CurrentRate,
+		CreatedAt:    order.CreatedAt,
+		CompletedAt:  order.CompletedAt,
+	}
+	if order.ExecutedAmountFrom != nil && *order.ExecutedAmountFrom != "" {
+		entry.FromAmount = *order.ExecutedAmountFrom
+	}
+	if order.ExecutedAmountTo != nil && *order.ExecutedAmountTo != "" {
+		entry.ToAmount = *order.ExecutedAmountTo
+	}
+
+	return entry
+}
@@ -0,0 +1,81 @@
+package cryptomus_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestConvertLedger(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/user-api/convert/order-list/" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Write([]byte(`{"state": 0, "result": {"items": [{
+				"order_id": 49347,
+				"convert_amount_from": "0.03700249",
+				"convert_amount_to": "2476.39230892",
+				"executed_amount_from": "0.03700249",
+				"executed_amount_to": "2476.39230892",
+				"convert_currency_from": "BTC",
+				"convert_currency_to": "USDT",
+				"type": "market",
+				"status": "completed",
+				"created_at": "2024-03-25 , 11:24:55",
+				"current_rate": "66925.01798999",
+				"completed_at": "2024-03-25 , 11:25:03"
+			}], "paginate": {"count": 1, "hasPages": true, "nextCursor": "page2", "previousCursor": null, "perPage": 1}}}`))
+		case "page2":
+			w.Write([]byte(`{"state": 0, "result": {"items": [{
+				"order_id": 49001,
+				"convert_amount_from": "10.000",
+				"convert_amount_to": "3.000",
+				"executed_amount_from": null,
+				"executed_amount_to": null,
+				"convert_currency_from": "USDT",
+				"convert_currency_to": "XMR",
+				"type": "limit",
+				"status": "active",
+				"created_at": "2024-01-11 , 18:06:04",
+				"current_rate": "100",
+				"completed_at": null
+			}], "paginate": {"count": 1, "hasPages": true, "nextCursor": null, "previousCursor": "page1", "perPage": 1}}}`))
+		default:
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("cursor"))
+		}
+	})
+
+	user := cryptomus.NewUser("user", "payment-key", "payout-key")
+	utc3 := time.FixedZone("UTC+3", 3*60*60)
+
+	entries, err := user.ConvertLedger(time.Date(2024, 3, 1, 0, 0, 0, 0, utc3), time.Date(2024, 4, 1, 0, 0, 0, 0, utc3))
+	if err != nil {
+		t.Fatalf("error building convert ledger: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry in range, got %d: %v", len(entries), entries)
+	}
+
+	entry := entries[0]
+	if entry.OrderID != "49347" || entry.FromCurrency != "BTC" || entry.ToAmount != "2476.39230892" || entry.Rate != "66925.01798999" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+	if want := time.Date(2024, 3, 25, 11, 24, 55, 0, utc3); !entry.CreatedAt.Equal(want) {
+		t.Errorf("expected created at %s, got %s", want, entry.CreatedAt)
+	}
+
+	entries, err = user.ConvertLedger(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("error building convert ledger: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries across both pages, got %d", len(entries))
+	}
+	if entries[1].FromAmount != "10.000" || !entries[1].CompletedAt.IsZero() {
+		t.Errorf("expected requested amount and zero completion for active order, got %+v", entries[1])
+	}
+}
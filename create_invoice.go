@@ -1,9 +1,9 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"net/http"
 	"strings"
 )
 
@@ -154,7 +154,26 @@ import (
 //		"error": null
 //	}
 func (m *Merchant) CreateInvoice(request Invoice) (*Payment, error) {
-	httpResponse, err := m.sendPaymentRequest("POST", urlCreateInvoice, request)
+	return m.CreateInvoiceContext(context.Background(), request)
+}
+
+// CreateInvoiceContext is like CreateInvoice but sends the request with the provided context.
+func (m *Merchant) CreateInvoiceContext(ctx context.Context, request Invoice) (*Payment, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating invoice: %w", err)
+	}
+	request.Amount, _ = NormalizeAmount(request.Amount)
+	request.Network = m.networkPtr(request.Network)
+	request.Currencies = m.currencies(request.Currencies)
+	request.ExceptCurrencies = m.currencies(request.ExceptCurrencies)
+	if err := m.checkInvoicePairs(request); err != nil {
+		return nil, fmt.Errorf("error validating invoice: %w", err)
+	}
+	if err := m.checkInvoiceCurrencies(ctx, request); err != nil {
+		return nil, err
+	}
+
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlCreateInvoice, request)
 	if err != nil {
 		return nil, err
 	}
@@ -165,33 +184,98 @@ func (m *Merchant) CreateInvoice(request Invoice) (*Payment, error) {
 		Result  Payment `json:"result"`
 		Message string  `json:"message"`
 		// If some parameter is required and not passed
-		Errors struct {
-			Amount   []string `json:"amount"`
-			Currency []string `json:"currency"`
-			OrderID  []string `json:"order_id"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		Errors responseErrors `json:"errors"`
+		Code   int            `json:"code"`
+		Error  string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
-	errs = append(errs, response.Errors.Amount...)
-	errs = append(errs, response.Errors.Currency...)
-	errs = append(errs, response.Errors.OrderID...)
 
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := m.checkInvoiceMismatch(request, response.Result); err != nil {
+		return &response.Result, err
 	}
 
 	return &response.Result, nil
 }
+
+// checkInvoiceCurrencies checks the currencies of request against the payment services with ValidateCurrencies if WithInvoiceMismatchCheck is set and the request lists currencies.
+func (m *Merchant) checkInvoiceCurrencies(ctx context.Context, request Invoice) error {
+	if !m.checkInvoice || len(request.Currencies) == 0 {
+		return nil
+	}
+	services, err := m.ListPaymentServicesContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing payment services: %w", err)
+	}
+	if err := request.ValidateCurrencies(services); err != nil {
+		return fmt.Errorf("error validating invoice: %w", err)
+	}
+	return nil
+}
+
+// checkInvoiceMismatch returns ErrInvoiceMismatch if WithInvoiceMismatchCheck is set and payment, as returned for request, has another amount or currency.
+func (m *Merchant) checkInvoiceMismatch(request Invoice, payment Payment) error {
+	if !m.checkInvoice {
+		return nil
+	}
+	if !sameDecimal(request.Amount, payment.Amount) || !strings.EqualFold(request.Currency, payment.Currency) {
+		return fmt.Errorf("%w: order %s is %s %s, requested %s %s", ErrInvoiceMismatch, request.OrderID, payment.Amount, payment.Currency, request.Amount, request.Currency)
+	}
+	return nil
+}
+
+// Limits of the lifetime of an invoice in seconds.
+const (
+	MinInvoiceLifetime = 300
+	MaxInvoiceLifetime = 43200
+)
+
+// ErrInvoicePaid is returned by RefreshInvoice for an invoice that is already paid.
+var ErrInvoicePaid = errors.New("invoice is already paid")
+
+// RefreshInvoice gives the invoice with orderID a new address and a new lifetime in seconds, e.g. after it expired unpaid.
+//
+// Cryptomus refreshes an invoice when it is created again with is_refresh and all required parameters, so RefreshInvoice first gets the amount and currency of the invoice with GetPaymentInformation.
+// Only the address, payment_status and expired_at of the invoice change; the returned Payment has the new ones.
+//
+// lifetime must be between MinInvoiceLifetime and MaxInvoiceLifetime. RefreshInvoice returns an error wrapping ErrInvoicePaid without refreshing if the invoice is paid.
+//
+// # Expiring an invoice
+//
+// Cryptomus has no endpoint to cancel or expire an invoice: it stops accepting payment only once its lifetime is over.
+// To shorten the time an abandoned invoice accepts payment, create it with a short lifetime, or refresh it with MinInvoiceLifetime so it expires five minutes later at the latest. Payments already sent to the old address may still be credited to the invoice.
+//
+// See "Creating an invoice" https://doc.cryptomus.com/business/payments/creating-invoice
+func (m *Merchant) RefreshInvoice(orderID string, lifetime int) (*Payment, error) {
+	return m.RefreshInvoiceContext(context.Background(), orderID, lifetime)
+}
+
+// RefreshInvoiceContext is like RefreshInvoice but sends the requests with the provided context.
+func (m *Merchant) RefreshInvoiceContext(ctx context.Context, orderID string, lifetime int) (*Payment, error) {
+	if lifetime < MinInvoiceLifetime || lifetime > MaxInvoiceLifetime {
+		return nil, fmt.Errorf("lifetime %d out of range [%d, %d]", lifetime, MinInvoiceLifetime, MaxInvoiceLifetime)
+	}
+
+	payment, err := m.GetPaymentInformationContext(ctx, RecordID{OrderID: &orderID})
+	if err != nil {
+		return nil, fmt.Errorf("error getting invoice %q: %w", orderID, err)
+	}
+	if payment.PaymentStatus.IsSuccess() {
+		return nil, fmt.Errorf("%w: order %s is %s", ErrInvoicePaid, orderID, payment.PaymentStatus)
+	}
+
+	isRefresh := true
+	return m.CreateInvoiceContext(ctx, Invoice{
+		Amount:    payment.Amount,
+		Currency:  payment.Currency,
+		OrderID:   orderID,
+		Lifetime:  &lifetime,
+		IsRefresh: &isRefresh,
+	})
+}
@@ -0,0 +1,178 @@
+package cryptomus_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestRefreshInvoice(t *testing.T) {
+	var payload map[string]any
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/payment/info":
+			w.Write([]byte(`{"state": 0, "result": {"uuid": "26109ba0-b05b-4ee0-93d1-fd62c822ce95", "order_id": "1", "amount": "15.00", "currency": "USDT", "address": "TOld", "payment_status": "cancel", "expired_at": 1689099958}}`))
+		case "/v1/payment":
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("error reading request body: %v", err)
+			}
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Fatalf("error decoding request body: %v", err)
+			}
+			w.Write([]byte(`{"state": 0, "result": {"uuid": "26109ba0-b05b-4ee0-93d1-fd62c822ce95", "order_id": "1", "amount": "15.00", "currency": "USDT", "address": "TNew", "payment_status": "check", "expired_at": 1689190000}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	payment, err := merchant.RefreshInvoice("1", 3600)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]any{"amount": "15.00", "currency": "USDT", "order_id": "1", "lifetime": float64(3600), "is_refresh": true}
+	if !reflect.DeepEqual(payload, want) {
+		t.Errorf("got payload %v, want %v", payload, want)
+	}
+	if payment.Address != "TNew" || payment.ExpiredAt != 1689190000 {
+		t.Errorf("got address %s expiring at %d, want the refreshed ones", payment.Address, payment.ExpiredAt)
+	}
+}
+
+func TestRefreshInvoiceLifetime(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL)
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	for _, lifetime := range []int{0, 299, 43201} {
+		if _, err := merchant.RefreshInvoice("1", lifetime); err == nil {
+			t.Errorf("lifetime %d: expected error", lifetime)
+		}
+	}
+}
+
+func TestWithInvoiceMismatchCheck(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"state": 0, "result": {"uuid": "26109ba0-b05b-4ee0-93d1-fd62c822ce95", "order_id": "1", "amount": "15.00", "currency": "USDT"}}`))
+	})
+
+	tests := []struct {
+		name, amount, currency string
+		check, wantErr         bool
+	}{
+		{"matching", "15", "usdt", true, false},
+		{"other amount", "20", "USDT", true, true},
+		{"other currency", "15", "USD", true, true},
+		{"unchecked", "20", "USD", false, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var opts []cryptomus.Option
+			if test.check {
+				opts = append(opts, cryptomus.WithInvoiceMismatchCheck())
+			}
+			merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", opts...)
+
+			payment, err := merchant.CreateInvoice(cryptomus.Invoice{Amount: test.amount, Currency: test.currency, OrderID: "1"})
+			if got := errors.Is(err, cryptomus.ErrInvoiceMismatch); got != test.wantErr {
+				t.Errorf("got error %v, want ErrInvoiceMismatch: %v", err, test.wantErr)
+			}
+			if err != nil && !errors.Is(err, cryptomus.ErrInvoiceMismatch) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if payment == nil || payment.Amount != "15.00" {
+				t.Errorf("got payment %+v, want the existing invoice", payment)
+			}
+		})
+	}
+}
+
+func TestCreateInvoiceChecksCurrencies(t *testing.T) {
+	var invoices int
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/payment/services":
+			w.Write([]byte(`{"state": 0, "result": [
+				{"network": "TRON", "currency": "USDT", "is_available": true, "limit": {"min_amount": "1", "max_amount": "1000000"}, "commission": {"fee_amount": "0", "percent": "0"}},
+				{"network": "BTC", "currency": "BTC", "is_available": true, "limit": {"min_amount": "0.0001", "max_amount": "100"}, "commission": {"fee_amount": "0", "percent": "0"}}
+			]}`))
+		case "/v1/payment":
+			invoices++
+			w.Write([]byte(`{"state": 0, "result": {"uuid": "26109ba0-b05b-4ee0-93d1-fd62c822ce95", "order_id": "1", "amount": "15.00", "currency": "USD"}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithInvoiceMismatchCheck())
+	tron, bsc := "tron", "bsc"
+	invoice := cryptomus.Invoice{Amount: "15", Currency: "USD", OrderID: "1", Currencies: []cryptomus.Currency{
+		{Currency: "USDT", Network: &tron},
+		{Currency: "USDT", Network: &bsc},
+	}}
+
+	var validationError *cryptomus.ValidationError
+	if _, err := merchant.CreateInvoice(invoice); !errors.As(err, &validationError) || validationError.Errors["currencies.1"] == nil {
+		t.Fatalf("got error %v, want a ValidationError for currencies.1", err)
+	}
+	if invoices != 0 {
+		t.Fatal("invoice was created despite an unsupported currency")
+	}
+
+	invoice.Currencies = invoice.Currencies[:1]
+	if _, err := merchant.CreateInvoice(invoice); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if invoices != 1 {
+		t.Errorf("got %d invoices, want 1", invoices)
+	}
+}
+
+func TestRefreshInvoiceMinLifetime(t *testing.T) {
+	var lifetime any
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/payment/info":
+			w.Write([]byte(`{"state": 0, "result": {"order_id": "1", "amount": "15.00", "currency": "USDT", "payment_status": "check", "expired_at": 1689099958}}`))
+		case "/v1/payment":
+			var payload map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Fatalf("error decoding request body: %v", err)
+			}
+			lifetime = payload["lifetime"]
+			w.Write([]byte(`{"state": 0, "result": {"order_id": "1", "amount": "15.00", "currency": "USDT", "payment_status": "check", "expired_at": 1689096658}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	if _, err := merchant.RefreshInvoice("1", cryptomus.MinInvoiceLifetime); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lifetime != float64(300) {
+		t.Errorf("got lifetime %v, want 300", lifetime)
+	}
+}
+
+func TestRefreshInvoicePaid(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/payment/info" {
+			t.Errorf("unexpected request to %s", r.URL)
+		}
+		w.Write([]byte(`{"state": 0, "result": {"order_id": "1", "amount": "15.00", "currency": "USDT", "payment_status": "paid", "is_final": true}}`))
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	if _, err := merchant.RefreshInvoice("1", cryptomus.MinInvoiceLifetime); !errors.Is(err, cryptomus.ErrInvoicePaid) {
+		t.Errorf("got error %v, want ErrInvoicePaid", err)
+	}
+}
@@ -1,10 +1,8 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strings"
 )
 
 // See "Create limit order" https://doc.cryptomus.com/personal/converts/limit-order
@@ -58,44 +56,32 @@ type LimitOrderRequest struct {
 //		}
 //	}
 func (u *User) CreateLimitOrder(request MarketOrderRequest) (*MarketOrder, error) {
-	httpResponse, err := u.sendPaymentRequest("POST", urlCreateLimitOrder, request)
+	return u.CreateLimitOrderContext(context.Background(), request)
+}
+
+// CreateLimitOrderContext is like CreateLimitOrder but sends the request with the provided context.
+func (u *User) CreateLimitOrderContext(ctx context.Context, request MarketOrderRequest) (*MarketOrder, error) {
+	httpResponse, err := u.sendPaymentRequest(ctx, "POST", urlCreateLimitOrder, request)
 	if err != nil {
 		return nil, err
 	}
 	defer httpResponse.Body.Close()
 
 	var response = struct {
-		State   int         `json:"state"`
-		Result  MarketOrder `json:"result"`
-		Message string      `json:"message"`
-		Errors  struct {
-			From   []string `json:"from"`
-			To     []string `json:"to"`
-			Amount []string `json:"amount"`
-			Price  []string `json:"price"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		State   int            `json:"state"`
+		Result  MarketOrder    `json:"result"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := u.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.From...)
-	errs = append(errs, response.Errors.To...)
-	errs = append(errs, response.Errors.Amount...)
-	errs = append(errs, response.Errors.Price...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
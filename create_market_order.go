@@ -1,10 +1,8 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strings"
 )
 
 // See "Create market order" https://doc.cryptomus.com/personal/converts/market-order
@@ -52,42 +50,32 @@ type MarketOrderRequest struct {
 //		}
 //	}
 func (u *User) CreateMarketOrder(request MarketOrderRequest) (*MarketOrder, error) {
-	httpResponse, err := u.sendPaymentRequest("POST", urlCreateMarketOrder, request)
+	return u.CreateMarketOrderContext(context.Background(), request)
+}
+
+// CreateMarketOrderContext is like CreateMarketOrder but sends the request with the provided context.
+func (u *User) CreateMarketOrderContext(ctx context.Context, request MarketOrderRequest) (*MarketOrder, error) {
+	httpResponse, err := u.sendPaymentRequest(ctx, "POST", urlCreateMarketOrder, request)
 	if err != nil {
 		return nil, err
 	}
 	defer httpResponse.Body.Close()
 
 	var response = struct {
-		State   int         `json:"state"`
-		Result  MarketOrder `json:"result"`
-		Message string      `json:"message"`
-		Errors  struct {
-			From   []string `json:"from"`
-			To     []string `json:"to"`
-			Amount []string `json:"amount"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		State   int            `json:"state"`
+		Result  MarketOrder    `json:"result"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := u.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.From...)
-	errs = append(errs, response.Errors.To...)
-	errs = append(errs, response.Errors.Amount...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
@@ -1,9 +1,10 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"net/http"
+	"math/big"
 	"strings"
 )
 
@@ -154,7 +155,21 @@ import (
 //		"error": null
 //	}
 func (m *Merchant) CreatePayout(request Withdrawal) (*Payout, error) {
-	httpResponse, err := m.sendPayoutRequest("POST", urlCreatePayout, request)
+	return m.CreatePayoutContext(context.Background(), request)
+}
+
+// CreatePayoutContext is like CreatePayout but sends the request with the provided context.
+func (m *Merchant) CreatePayoutContext(ctx context.Context, request Withdrawal) (*Payout, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating payout: %w", err)
+	}
+	request.Amount, _ = NormalizeAmount(request.Amount)
+	request.Network = m.networkPtr(request.Network)
+	if err := m.checkWithdrawalPair(request); err != nil {
+		return nil, fmt.Errorf("error validating payout: %w", err)
+	}
+
+	httpResponse, err := m.sendPayoutRequest(ctx, "POST", urlCreatePayout, request)
 	if err != nil {
 		return nil, err
 	}
@@ -165,39 +180,130 @@ func (m *Merchant) CreatePayout(request Withdrawal) (*Payout, error) {
 		Result  Payout `json:"result"`
 		Message string `json:"message"`
 		// If some parameter is required and not passed
-		Errors struct {
-			Amount     []string `json:"amount"`
-			Currency   []string `json:"currency"`
-			OrderID    []string `json:"order_id"`
-			Address    []string `json:"address"`
-			IsSubtract []string `json:"is_subtract"`
-			Network    []string `json:"network"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		Errors responseErrors `json:"errors"`
+		Code   int            `json:"code"`
+		Error  string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
+
+	return &response.Result, nil
+}
+
+// PayoutResult pairs a withdrawal of a CreatePayouts batch with the payout created for it, or the error that prevented it.
+type PayoutResult struct {
+	Withdrawal Withdrawal
+	// Payout is nil if Err is set.
+	Payout *Payout
+	Err    error
+}
+
+// CreatePayouts creates a payout for every request, sending up to the number set with WithBatchConcurrency, 4 by default, at a time, and returns a result for each of them in the order of requests.
+//
+// Each payout is validated, signed and sent on its own as by CreatePayoutContext, so a failed one is reported in its result and does not stop the others.
+// Check every result: a payout may have been created even if others failed. Since the order_id of a created payout returns its details instead of creating another one, the failed requests can be retried as they are.
+//
+// The error is that of ctx if it is done before every request was sent; the results of the requests that were not sent hold it too.
+func (m *Merchant) CreatePayouts(ctx context.Context, requests []Withdrawal) ([]PayoutResult, error) {
+	results := make([]PayoutResult, len(requests))
+	errs, err := runBatch(ctx, len(requests), m.batchConcurrency, func(i int) error {
+		var err error
+		results[i].Payout, err = m.CreatePayoutContext(ctx, requests[i])
+		return err
+	})
+	for i, request := range requests {
+		results[i].Withdrawal = request
+		results[i].Err = errs[i]
 	}
-	errs = append(errs, response.Errors.Amount...)
-	errs = append(errs, response.Errors.Currency...)
-	errs = append(errs, response.Errors.OrderID...)
-	errs = append(errs, response.Errors.Address...)
-	errs = append(errs, response.Errors.IsSubtract...)
-	errs = append(errs, response.Errors.Network...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+
+	return results, err
+}
+
+// ErrBelowMinReceive is returned by CreatePayoutWithMinReceive when the quoted payout amount is below the minimum.
+var ErrBelowMinReceive = errors.New("payout amount below minimum")
+
+// CreatePayoutWithMinReceive is like CreatePayout but first quotes the conversion of the payout and aborts, without creating it, if the recipient would get less than minReceive.
+//
+// The amount received is request.Amount converted from currency to to_currency with the current rate from GetExchangeRate. When no conversion is involved it is request.Amount itself.
+// minReceive is in to_currency, or in currency if to_currency is not set.
+// Unless is_subtract is true, the withdrawal fee is taken from the amount, so it is subtracted from the quote. The fee is that of the payout service of ListPayoutServices for the currency received and the network; without a network the highest fee of the services for the currency is assumed.
+//
+// The rate can still move between the quote and the payout; this check protects against an already unfavorable rate, not against a change in the meantime.
+//
+// The returned error wraps ErrBelowMinReceive if the payout was aborted.
+func (m *Merchant) CreatePayoutWithMinReceive(request Withdrawal, minReceive string) (*Payout, error) {
+	return m.CreatePayoutWithMinReceiveContext(context.Background(), request, minReceive)
+}
+
+// CreatePayoutWithMinReceiveContext is like CreatePayoutWithMinReceive but sends the requests with the provided context.
+func (m *Merchant) CreatePayoutWithMinReceiveContext(ctx context.Context, request Withdrawal, minReceive string) (*Payout, error) {
+	minimum, err := parseDecimal(minReceive)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing minimum amount: %w", err)
+	}
+	amount, err := parseDecimal(request.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing payout amount: %w", err)
 	}
 
-	return &response.Result, nil
+	receiveCurrency := request.Currency
+	if request.ToCurrency != nil && *request.ToCurrency != "" && !strings.EqualFold(*request.ToCurrency, request.Currency) {
+		receiveCurrency = *request.ToCurrency
+
+		exchangeRate, err := GetExchangeRateForContext(ctx, request.Currency, receiveCurrency)
+		if err != nil {
+			return nil, fmt.Errorf("error quoting %s to %s: %w", request.Currency, receiveCurrency, err)
+		}
+		rate, err := parseDecimal(exchangeRate.Course)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing rate of %s to %s: %w", request.Currency, receiveCurrency, err)
+		}
+		amount.Mul(amount, rate)
+	}
+
+	if request.IsSubtract == nil || !*request.IsSubtract {
+		fee, err := m.quotePayoutFee(ctx, receiveCurrency, request.Network, amount)
+		if err != nil {
+			return nil, err
+		}
+		amount.Sub(amount, fee)
+	}
+
+	if amount.Cmp(minimum) < 0 {
+		return nil, fmt.Errorf("%w: would receive %s %s, minimum is %s", ErrBelowMinReceive, formatDecimal(amount, decimalPlaces), receiveCurrency, minReceive)
+	}
+
+	return m.CreatePayoutContext(ctx, request)
+}
+
+// quotePayoutFee returns the fee of a payout of amount in currency on network, or the highest fee of the payout services for currency if network is not set.
+func (m *Merchant) quotePayoutFee(ctx context.Context, currency string, network *string, amount *big.Rat) (*big.Rat, error) {
+	services, err := m.ListPayoutServicesContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing payout services: %w", err)
+	}
+
+	var highest *big.Rat
+	for _, service := range services {
+		if !strings.EqualFold(service.Currency, currency) || (network != nil && *network != "" && !strings.EqualFold(service.Network, *network)) {
+			continue
+		}
+		fee, err := payoutFee(service, amount)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing commission of %s on %s: %w", service.Currency, service.Network, err)
+		}
+		if highest == nil || fee.Cmp(highest) > 0 {
+			highest = fee
+		}
+	}
+	if highest == nil {
+		return nil, fmt.Errorf("no payout service for %s", currency)
+	}
+	return highest, nil
 }
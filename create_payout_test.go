@@ -0,0 +1,169 @@
+package cryptomus_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestCreatePayoutWithMinReceive(t *testing.T) {
+	var payouts int
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v1/exchange-rate/USD/") {
+			w.Write([]byte(`{"state": 0, "result": [{"from": "USD", "to": "EUR", "course": "0.9"}, {"from": "USD", "to": "LTC", "course": "0.0125"}]}`))
+			return
+		}
+		payouts++
+		w.Write([]byte(`{"state": 0, "result": {"uuid": "a7c0caec-a594-4aaa-b1c4-77d511857594", "amount": "20", "currency": "LTC", "status": "process"}}`))
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	toCurrency, isSubtract := "LTC", true
+	request := cryptomus.Withdrawal{Amount: "20", Currency: "USD", ToCurrency: &toCurrency, OrderID: "1", Address: "ltc1qaddress", IsSubtract: &isSubtract}
+
+	// 20 USD * 0.0125 = 0.25 LTC
+	if _, err := merchant.CreatePayoutWithMinReceive(request, "0.25"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payouts != 1 {
+		t.Fatalf("got %d payouts, want 1", payouts)
+	}
+
+	_, err := merchant.CreatePayoutWithMinReceive(request, "0.26")
+	if !errors.Is(err, cryptomus.ErrBelowMinReceive) {
+		t.Fatalf("expected ErrBelowMinReceive, got %v", err)
+	}
+	if payouts != 1 {
+		t.Errorf("payout was created despite the minimum")
+	}
+
+	noConversion := cryptomus.Withdrawal{Amount: "0.3", Currency: "LTC", OrderID: "2", Address: "ltc1qaddress", IsSubtract: &isSubtract}
+	if _, err := merchant.CreatePayoutWithMinReceive(noConversion, "0.5"); !errors.Is(err, cryptomus.ErrBelowMinReceive) {
+		t.Errorf("expected ErrBelowMinReceive without conversion, got %v", err)
+	}
+}
+
+func TestCreatePayoutWithMinReceiveFee(t *testing.T) {
+	var payouts int
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v1/exchange-rate/USD/"):
+			w.Write([]byte(`{"state": 0, "result": [{"from": "USD", "to": "LTC", "course": "0.0125"}, {"from": "USD", "to": "DOGE", "course": "10"}]}`))
+		case r.URL.Path == "/v1/payout/services":
+			w.Write([]byte(`{"state": 0, "result": [
+				{"network": "LTC", "currency": "LTC", "is_available": true, "limit": {"min_amount": "0.001", "max_amount": "100"}, "commission": {"fee_amount": "0.001", "percent": "2"}},
+				{"network": "BSC", "currency": "USDT", "is_available": true, "limit": {"min_amount": "5", "max_amount": "1000000"}, "commission": {"fee_amount": "1", "percent": "0"}}
+			]}`))
+		default:
+			payouts++
+			w.Write([]byte(`{"state": 0, "result": {"uuid": "a7c0caec-a594-4aaa-b1c4-77d511857594", "amount": "20", "currency": "LTC", "status": "process"}}`))
+		}
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	toCurrency, network, isSubtract := "LTC", "ltc", false
+	request := cryptomus.Withdrawal{Amount: "20", Currency: "USD", ToCurrency: &toCurrency, Network: &network, OrderID: "1", Address: "ltc1qaddress", IsSubtract: &isSubtract}
+
+	// 20 USD * 0.0125 = 0.25 LTC, minus a fee of 0.001 + 2% of 0.25 = 0.006 LTC
+	if _, err := merchant.CreatePayoutWithMinReceive(request, "0.245"); !errors.Is(err, cryptomus.ErrBelowMinReceive) {
+		t.Fatalf("expected ErrBelowMinReceive, got %v", err)
+	}
+	if _, err := merchant.CreatePayoutWithMinReceive(request, "0.244"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payouts != 1 {
+		t.Errorf("got %d payouts, want 1", payouts)
+	}
+
+	toCurrency = "DOGE"
+	if _, err := merchant.CreatePayoutWithMinReceive(request, "0"); err == nil || !strings.Contains(err.Error(), "no payout service") {
+		t.Errorf("expected error without a payout service, got %v", err)
+	}
+}
+
+func TestCreatePayouts(t *testing.T) {
+	var merchant *cryptomus.Merchant
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		body, _ := io.ReadAll(r.Body)
+		if sign := r.Header.Get("sign"); sign != merchant.SignPayout(body) {
+			t.Errorf("got sign %s for body %s", sign, body)
+		}
+		var withdrawal cryptomus.Withdrawal
+		if err := json.Unmarshal(body, &withdrawal); err != nil {
+			t.Errorf("error decoding request: %v", err)
+		}
+		if withdrawal.OrderID == "2" {
+			w.Write([]byte(`{"state": 1, "message": "Not enough funds"}`))
+			return
+		}
+		w.Write([]byte(`{"state": 0, "result": {"uuid": "payout-` + withdrawal.OrderID + `", "amount": "` + withdrawal.Amount + `", "currency": "USDT", "status": "process"}}`))
+	}))
+	defer server.Close()
+
+	merchant = cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL), cryptomus.WithBatchConcurrency(2))
+	isSubtract := true
+	var requests []cryptomus.Withdrawal
+	for _, orderID := range []string{"1", "2", "3", "4", "invalid id"} {
+		requests = append(requests, cryptomus.Withdrawal{Amount: "5", Currency: "USDT", OrderID: orderID, Address: "TDD97yguPESTpcrJMqU6h2ozZbibv4Vaqm", IsSubtract: &isSubtract})
+	}
+
+	results, err := merchant.CreatePayouts(context.Background(), requests)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != len(requests) {
+		t.Fatalf("got %d results, want %d", len(results), len(requests))
+	}
+	for i, result := range results {
+		orderID := result.Withdrawal.OrderID
+		if orderID != requests[i].OrderID {
+			t.Errorf("result %d is for order %s, want %s", i, orderID, requests[i].OrderID)
+		}
+		switch orderID {
+		case "2":
+			if !cryptomus.IsNotEnoughFunds(result.Err) || result.Payout != nil {
+				t.Errorf("order 2: got %+v, %v, want a not enough funds error", result.Payout, result.Err)
+			}
+		case "invalid id":
+			if !cryptomus.IsValidationError(result.Err) {
+				t.Errorf("order %q: got error %v, want a validation error", orderID, result.Err)
+			}
+		default:
+			if result.Err != nil || result.Payout == nil || result.Payout.UUID != "payout-"+orderID {
+				t.Errorf("order %s: got %+v, %v", orderID, result.Payout, result.Err)
+			}
+		}
+	}
+	if maxInFlight > 2 {
+		t.Errorf("got %d payouts in flight, want at most 2", maxInFlight)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err = merchant.CreatePayouts(ctx, requests)
+	if !errors.Is(err, context.Canceled) || !errors.Is(results[0].Err, context.Canceled) {
+		t.Errorf("got error %v and first result %v, want context.Canceled", err, results[0].Err)
+	}
+}
@@ -1,10 +1,8 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strings"
 )
 
 // Discount:
@@ -39,7 +37,7 @@ type RecurringInvoice struct {
 	//  - weekly
 	//  - monthly
 	//  - three_month
-	Period string `json:"period"`
+	Period RecurringPeriod `json:"period"`
 	// (Optional) Currency code for accepting payments
 	//
 	// The parameter is used to specify the target currency for converting the recurrent payment amount.
@@ -58,19 +56,45 @@ type RecurringInvoice struct {
 	// (Optional) Url to which webhooks with payment status will be sent
 	//    default: null
 	URLCallback *string `json:"url_callback,omitempty"`
-	// (Optional) Url to which webhooks with payment status will be sent
-	//    default: null
-	DiscountDays *int `json:"discount_days,omitempty"`
 	// (Optional) Discount period days (required with 'discount_amount')
 	//    min: 1
 	//    max: 365
 	//    default: 0
-	DiscountAmount *string `json:"discount_amount,omitempty"`
+	DiscountDays *int `json:"discount_days,omitempty"`
 	// (Optional) Discount amount (required with 'discount_days').Here the amount in the currency of the parameter ‘currency’
 	//    default: null
-	AdditionalData *string `json:"additional_data,omitempty"`
+	DiscountAmount *string `json:"discount_amount,omitempty"`
 	// Additional recurring payment details
 	//    default: null
+	AdditionalData *string `json:"additional_data,omitempty"`
+}
+
+// Validate checks the recurring invoice against the constraints documented for its fields, so CreateRecurringInvoice can fail without a round trip to Cryptomus.
+//
+// It returns a *ValidationError listing every invalid field.
+//
+// See "Creating recurring payment" https://doc.cryptomus.com/business/recurring/creating
+func (r RecurringInvoice) Validate() error {
+	errs := fieldErrors{}
+	errs.amount("amount", r.Amount)
+	errs.required("currency", r.Currency)
+	if errs.required("name", r.Name) {
+		errs.length("name", r.Name, 3, 60)
+	}
+	if !r.Period.IsValid() {
+		errs.add("period", "must be one of weekly, monthly or three_month, got %q", r.Period)
+	}
+	switch {
+	case r.DiscountDays != nil && r.DiscountAmount == nil:
+		errs.add("discount_amount", "is required with discount_days")
+	case r.DiscountDays == nil && r.DiscountAmount != nil:
+		errs.add("discount_days", "is required with discount_amount")
+	}
+	errs.between("discount_days", r.DiscountDays, 1, 365)
+	if r.DiscountAmount != nil {
+		errs.amount("discount_amount", *r.DiscountAmount)
+	}
+	return errs.err()
 }
 
 // Recurring payments in cryptocurrency are a way to automate regular transactions using digital assets. They can be useful for subscription-based services, donations, memberships, and other recurring payments.
@@ -211,7 +235,16 @@ type RecurringInvoice struct {
 //		}
 //	}
 func (m *Merchant) CreateRecurringInvoice(request RecurringInvoice) (RecurringPayment, error) {
-	httpResponse, err := m.sendPaymentRequest("POST", urlCreateRecurringPayment, request)
+	return m.CreateRecurringInvoiceContext(context.Background(), request)
+}
+
+// CreateRecurringInvoiceContext is like CreateRecurringInvoice but sends the request with the provided context.
+func (m *Merchant) CreateRecurringInvoiceContext(ctx context.Context, request RecurringInvoice) (RecurringPayment, error) {
+	if err := request.Validate(); err != nil {
+		return RecurringPayment{}, fmt.Errorf("error validating recurring invoice: %w", err)
+	}
+
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlCreateRecurringPayment, request)
 	if err != nil {
 		return RecurringPayment{}, err
 	}
@@ -222,34 +255,17 @@ func (m *Merchant) CreateRecurringInvoice(request RecurringInvoice) (RecurringPa
 		Result  RecurringPayment `json:"result"`
 		Message string           `json:"message"`
 		// If some parameter is required and not passed
-		Errors struct {
-			Amount   []string `json:"amount"`
-			Currency []string `json:"currency"`
-			Name     []string `json:"name"`
-			Period   []string `json:"period"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		Errors responseErrors `json:"errors"`
+		Code   int            `json:"code"`
+		Error  string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return RecurringPayment{}, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.Amount...)
-	errs = append(errs, response.Errors.Currency...)
-	errs = append(errs, response.Errors.Name...)
-	errs = append(errs, response.Errors.Period...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return RecurringPayment{}, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return RecurringPayment{}, err
 	}
 
 	return response.Result, nil
@@ -0,0 +1,82 @@
+package cryptomus_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestRecurringInvoiceValidate(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	stringPtr := func(s string) *string { return &s }
+	valid := func() cryptomus.RecurringInvoice {
+		return cryptomus.RecurringInvoice{Amount: "15", Currency: "USDT", Name: "Recurring payment", Period: cryptomus.PeriodMonthly}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*cryptomus.RecurringInvoice)
+		fields []string
+	}{
+		{"valid", func(r *cryptomus.RecurringInvoice) {}, nil},
+		{"weekly", func(r *cryptomus.RecurringInvoice) { r.Period = cryptomus.PeriodWeekly }, nil},
+		{"three_month", func(r *cryptomus.RecurringInvoice) { r.Period = cryptomus.PeriodThreeMonth }, nil},
+		{"unknown period", func(r *cryptomus.RecurringInvoice) { r.Period = "montly" }, []string{"period"}},
+		{"missing period", func(r *cryptomus.RecurringInvoice) { r.Period = "" }, []string{"period"}},
+		{"name 2", func(r *cryptomus.RecurringInvoice) { r.Name = "ab" }, []string{"name"}},
+		{"name 3", func(r *cryptomus.RecurringInvoice) { r.Name = "abc" }, nil},
+		{"name 60", func(r *cryptomus.RecurringInvoice) { r.Name = strings.Repeat("n", 60) }, nil},
+		{"name 61", func(r *cryptomus.RecurringInvoice) { r.Name = strings.Repeat("n", 61) }, []string{"name"}},
+		{"missing name", func(r *cryptomus.RecurringInvoice) { r.Name = "" }, []string{"name"}},
+		{"discount", func(r *cryptomus.RecurringInvoice) { r.DiscountDays, r.DiscountAmount = intPtr(30), stringPtr("5") }, nil},
+		{"discount_days alone", func(r *cryptomus.RecurringInvoice) { r.DiscountDays = intPtr(30) }, []string{"discount_amount"}},
+		{"discount_amount alone", func(r *cryptomus.RecurringInvoice) { r.DiscountAmount = stringPtr("5") }, []string{"discount_days"}},
+		{"discount_days 0", func(r *cryptomus.RecurringInvoice) { r.DiscountDays, r.DiscountAmount = intPtr(0), stringPtr("5") }, []string{"discount_days"}},
+		{"discount_days 366", func(r *cryptomus.RecurringInvoice) { r.DiscountDays, r.DiscountAmount = intPtr(366), stringPtr("5") }, []string{"discount_days"}},
+		{"malformed discount_amount", func(r *cryptomus.RecurringInvoice) { r.DiscountDays, r.DiscountAmount = intPtr(30), stringPtr("5,5") }, []string{"discount_amount"}},
+		{"missing amount and currency", func(r *cryptomus.RecurringInvoice) { r.Amount, r.Currency = "", "" }, []string{"amount", "currency"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			invoice := valid()
+			test.modify(&invoice)
+
+			err := invoice.Validate()
+			if len(test.fields) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationError *cryptomus.ValidationError
+			if !errors.As(err, &validationError) {
+				t.Fatalf("got error %v, want a ValidationError", err)
+			}
+			if len(validationError.Errors) != len(test.fields) {
+				t.Errorf("got errors %v, want ones for %v", validationError.Errors, test.fields)
+			}
+			for _, field := range test.fields {
+				if validationError.Errors[field] == nil {
+					t.Errorf("no error for %s in %v", field, validationError.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateRecurringInvoiceInvalidPeriod(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL)
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	_, err := merchant.CreateRecurringInvoice(cryptomus.RecurringInvoice{Amount: "15", Currency: "USDT", Name: "Recurring payment", Period: "yearly"})
+	if !cryptomus.IsValidationError(err) || !strings.Contains(err.Error(), "period") {
+		t.Errorf("got error %v, want a validation error for period", err)
+	}
+}
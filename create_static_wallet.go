@@ -1,10 +1,8 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strings"
 )
 
 // Required fields:
@@ -91,6 +89,8 @@ type StaticWalletResponse struct {
 //
 // All transactions sent to this address will be credited regardless of the amount.
 //
+// Cryptomus has no endpoint listing static wallets. Store the returned uuid and address with your order_id to reconcile wallets later; creating a wallet again with an existing order_id returns the existing wallet instead of a new one.
+//
 // See "Creating a Static wallet" https://doc.cryptomus.com/business/payments/creating-static
 //
 // # Response example
@@ -153,7 +153,17 @@ type StaticWalletResponse struct {
 //	    "message": "Wallet not found"
 //	}
 func (m *Merchant) CreateStaticWallet(request StaticWalletRequest) (*StaticWalletResponse, error) {
-	httpResponse, err := m.sendPaymentRequest("POST", urlCreateStaticWallet, request)
+	return m.CreateStaticWalletContext(context.Background(), request)
+}
+
+// CreateStaticWalletContext is like CreateStaticWallet but sends the request with the provided context.
+func (m *Merchant) CreateStaticWalletContext(ctx context.Context, request StaticWalletRequest) (*StaticWalletResponse, error) {
+	request.Network = m.network(request.Network)
+	if err := m.checkStaticWalletPair(request); err != nil {
+		return nil, fmt.Errorf("error validating static wallet: %w", err)
+	}
+
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlCreateStaticWallet, request)
 	if err != nil {
 		return nil, err
 	}
@@ -164,31 +174,16 @@ func (m *Merchant) CreateStaticWallet(request StaticWalletRequest) (*StaticWalle
 		Result  StaticWalletResponse `json:"result"`
 		Message string               `json:"message"`
 		// If some parameter is required and not passed
-		Errors struct {
-			Currency []string `json:"currency"`
-			Network  []string `json:"network"`
-			OrderID  []string `json:"order_id"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		Errors responseErrors `json:"errors"`
+		Code   int            `json:"code"`
+		Error  string         `json:"error"`
 	}{}
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.Currency...)
-	errs = append(errs, response.Errors.Network...)
-	errs = append(errs, response.Errors.OrderID...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
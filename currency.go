@@ -0,0 +1,126 @@
+package cryptomus
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// supportedPairs holds the networks of every currency Cryptomus supports, by currency code.
+//
+// See "List of supported currencies and networks" https://doc.cryptomus.com/business/reference/currencies-and-networks
+var supportedPairs = map[string][]string{
+	"AVAX":  {"avalanche"},
+	"BCH":   {"bch"},
+	"BNB":   {"bsc"},
+	"BTC":   {"btc"},
+	"CGPT":  {"bsc"},
+	"CRMS":  {"polygon"},
+	"DAI":   {"bsc", "eth", "polygon"},
+	"DASH":  {"dash"},
+	"DOGE":  {"doge"},
+	"ETH":   {"arbitrum", "bsc", "eth"},
+	"LTC":   {"ltc"},
+	"MATIC": {"eth", "polygon"},
+	"SOL":   {"sol"},
+	"TON":   {"ton"},
+	"TRX":   {"tron"},
+	"USDC":  {"arbitrum", "avalanche", "bsc", "eth", "polygon", "tron"},
+	"USDT":  {"arbitrum", "avalanche", "bsc", "eth", "polygon", "sol", "ton", "tron"},
+	"VERSE": {"eth"},
+	"XMR":   {"xmr"},
+}
+
+// SupportedCurrencies returns the codes of the currencies Cryptomus supports, sorted, e.g. BTC and USDT.
+//
+// The list is maintained with this package and can lag behind Cryptomus; ListPaymentServices and ListPayoutServices return what is available to a merchant right now.
+//
+// See "List of supported currencies and networks" https://doc.cryptomus.com/business/reference/currencies-and-networks
+func SupportedCurrencies() []string {
+	currencies := make([]string, 0, len(supportedPairs))
+	for currency := range supportedPairs {
+		currencies = append(currencies, currency)
+	}
+	slices.Sort(currencies)
+	return currencies
+}
+
+// SupportedNetworks returns the codes of the networks Cryptomus supports, sorted, e.g. bsc and tron.
+//
+// Like SupportedCurrencies, the list is maintained with this package.
+func SupportedNetworks() []string {
+	var networks []string
+	for _, codes := range supportedPairs {
+		networks = append(networks, codes...)
+	}
+	slices.Sort(networks)
+	return slices.Compact(networks)
+}
+
+// IsSupportedPair reports whether Cryptomus supports currency on network, e.g. USDT on tron. Codes are matched case-insensitively.
+//
+// Like SupportedCurrencies, the list is maintained with this package.
+func IsSupportedPair(currency, network string) bool {
+	return slices.Contains(supportedPairs[strings.ToUpper(currency)], strings.ToLower(network))
+}
+
+// WithSupportedPairCheck makes CreateInvoice, CreatePayout and CreateStaticWallet fail with a *ValidationError, before sending, if a currency is given with a network that IsSupportedPair does not know, e.g. a typo like USDT on trx.
+//
+// Network aliases set with WithNetworkAliases are resolved first. Fiat currencies and currencies without a network are not checked.
+// Leave it unset to use a currency Cryptomus added after this package was released.
+func WithSupportedPairCheck() Option {
+	return func(o *options) {
+		o.checkPairs = true
+	}
+}
+
+// supportedPair checks that currency is supported on network, if network is set and currency is not fiat.
+func (e fieldErrors) supportedPair(field, currency string, network *string) {
+	if network == nil || currency == "" || fiatCurrencies[strings.ToUpper(currency)] {
+		return
+	}
+	if !IsSupportedPair(currency, *network) {
+		e.add(field, "%s is not supported on network %s", currency, *network)
+	}
+}
+
+// checkInvoicePairs checks the pairs of an invoice, its networks already resolved, if WithSupportedPairCheck is set.
+func (o *options) checkInvoicePairs(invoice Invoice) error {
+	if !o.checkPairs {
+		return nil
+	}
+	errs := fieldErrors{}
+	errs.supportedPair("network", payCurrency(invoice.Currency, invoice.ToCurrency), invoice.Network)
+	for n, currency := range invoice.Currencies {
+		errs.supportedPair(fmt.Sprintf("currencies.%d.network", n), currency.Currency, currency.Network)
+	}
+	return errs.err()
+}
+
+// checkWithdrawalPair checks the pair of a withdrawal, its network already resolved, if WithSupportedPairCheck is set.
+func (o *options) checkWithdrawalPair(withdrawal Withdrawal) error {
+	if !o.checkPairs {
+		return nil
+	}
+	errs := fieldErrors{}
+	errs.supportedPair("network", payCurrency(withdrawal.Currency, withdrawal.ToCurrency), withdrawal.Network)
+	return errs.err()
+}
+
+// checkStaticWalletPair checks the pair of a static wallet, its network already resolved, if WithSupportedPairCheck is set.
+func (o *options) checkStaticWalletPair(request StaticWalletRequest) error {
+	if !o.checkPairs {
+		return nil
+	}
+	errs := fieldErrors{}
+	errs.supportedPair("network", request.Currency, &request.Network)
+	return errs.err()
+}
+
+// payCurrency returns the currency sent on the network of a request: toCurrency if it is set, currency otherwise.
+func payCurrency(currency string, toCurrency *string) string {
+	if toCurrency != nil && *toCurrency != "" {
+		return *toCurrency
+	}
+	return currency
+}
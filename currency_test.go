@@ -0,0 +1,97 @@
+package cryptomus_test
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestIsSupportedPair(t *testing.T) {
+	for name, code := range codes {
+		if !cryptomus.IsSupportedPair(code.Currency, code.Network) {
+			t.Errorf("%s: %s on %s is unsupported", name, code.Currency, code.Network)
+		}
+	}
+	if !cryptomus.IsSupportedPair("usdt", "TRON") {
+		t.Error("codes are not matched case-insensitively")
+	}
+	for _, pair := range [][2]string{{"USDT", "trx"}, {"BTC", "tron"}, {"USD", "tron"}, {"USDC", "sol"}, {"", ""}} {
+		if cryptomus.IsSupportedPair(pair[0], pair[1]) {
+			t.Errorf("%s on %s: got supported", pair[0], pair[1])
+		}
+	}
+
+	want := slices.Sorted(slices.Values(currencies))
+	if got := cryptomus.SupportedCurrencies(); !slices.Equal(got, want) {
+		t.Errorf("got currencies %v, want %v", got, want)
+	}
+	want = slices.Sorted(slices.Values(networks))
+	if got := cryptomus.SupportedNetworks(); !slices.Equal(got, want) {
+		t.Errorf("got networks %v, want %v", got, want)
+	}
+
+	// Every pair is in codes, so the table has no pair the test data does not list.
+	pairs := 0
+	for _, currency := range cryptomus.SupportedCurrencies() {
+		for _, network := range cryptomus.SupportedNetworks() {
+			if cryptomus.IsSupportedPair(currency, network) {
+				pairs++
+			}
+		}
+	}
+	if pairs != len(codes) {
+		t.Errorf("got %d supported pairs, want the %d of codes", pairs, len(codes))
+	}
+}
+
+func TestWithSupportedPairCheck(t *testing.T) {
+	requests := 0
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"state": 0, "result": {}}`))
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithSupportedPairCheck(), cryptomus.WithNetworkAliases(map[string]string{"TRC20": "tron"}))
+	typo, alias, isSubtract := "trx", "TRC20", true
+	usdt := "USDT"
+
+	_, err := merchant.CreateInvoice(cryptomus.Invoice{Amount: "15", Currency: "USDT", OrderID: "1", Network: &typo})
+	assertFieldError(t, "invoice", err, "network")
+	_, err = merchant.CreateInvoice(cryptomus.Invoice{Amount: "15", Currency: "USD", OrderID: "1", Currencies: []cryptomus.Currency{{Currency: "BTC", Network: &typo}}})
+	assertFieldError(t, "invoice currencies", err, "currencies.0.network")
+	_, err = merchant.CreatePayout(cryptomus.Withdrawal{Amount: "5", Currency: "USD", ToCurrency: &usdt, Network: &typo, OrderID: "1", Address: "TDD97yguPESTpcrJMqU6h2ozZbibv4Vaqm", IsSubtract: &isSubtract})
+	assertFieldError(t, "payout", err, "network")
+	_, err = merchant.CreateStaticWallet(cryptomus.StaticWalletRequest{Currency: "USDT", Network: "trx", OrderID: "1"})
+	assertFieldError(t, "static wallet", err, "network")
+	if requests != 0 {
+		t.Errorf("got %d requests for unsupported pairs, want none", requests)
+	}
+
+	if _, err := merchant.CreateInvoice(cryptomus.Invoice{Amount: "15", Currency: "USDT", OrderID: "1", Network: &alias}); err != nil {
+		t.Errorf("aliased network: unexpected error: %v", err)
+	}
+	if _, err := merchant.CreateInvoice(cryptomus.Invoice{Amount: "15", Currency: "USD", OrderID: "1", Network: &typo}); err != nil {
+		t.Errorf("fiat currency: unexpected error: %v", err)
+	}
+	if _, err := cryptomus.NewMerchant("merchant", "payment-key", "payout-key").CreateStaticWallet(cryptomus.StaticWalletRequest{Currency: "USDT", Network: "trx", OrderID: "1"}); err != nil {
+		t.Errorf("without the check: unexpected error: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("got %d requests, want 3", requests)
+	}
+}
+
+func assertFieldError(t *testing.T, name string, err error, field string) {
+	t.Helper()
+	if !cryptomus.IsValidationError(err) {
+		t.Errorf("%s: got error %v, want a validation error", name, err)
+		return
+	}
+	var validationError *cryptomus.ValidationError
+	if errors.As(err, &validationError); len(validationError.Errors[field]) == 0 {
+		t.Errorf("%s: got errors %v, want one for %s", name, validationError.Errors, field)
+	}
+}
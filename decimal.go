@@ -0,0 +1,146 @@
+package cryptomus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// decimalPlaces is the number of decimal places Cryptomus uses for crypto amounts.
+const decimalPlaces = 8
+
+// decimalPattern matches a plain decimal number such as "15", "0.00064860" or "-0.75": digits with an optional fraction and an optional minus sign.
+//
+// big.Rat.SetString also accepts fractions, exponents, base prefixes such as "0x10", digit separators and a plus sign, none of which Cryptomus uses.
+var decimalPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// parseDecimal parses a decimal amount string such as "0.00064860" exactly, without going through float64.
+func parseDecimal(s string) (*big.Rat, error) {
+	value := strings.TrimSpace(s)
+	if !decimalPattern.MatchString(value) {
+		return nil, fmt.Errorf("invalid decimal amount %q", s)
+	}
+	r, _ := new(big.Rat).SetString(value)
+	return r, nil
+}
+
+// NormalizeAmount trims whitespace around an amount and checks it is a plain decimal number such as "15" or "0.00064860", so a malformed amount fails locally instead of being rejected by Cryptomus.
+//
+// Commas are rejected rather than converted, since "1,000" and "1,5" are ambiguous; Cryptomus only accepts "." as decimal separator.
+// Signs, exponents, base prefixes such as "0x10", digit separators such as "1_000" and a missing integer or fraction part such as ".5" or "5." are rejected as well.
+func NormalizeAmount(s string) (string, error) {
+	amount, err := normalizeDecimal(s)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(amount, "-") {
+		return "", fmt.Errorf("invalid amount %q: must not be negative", s)
+	}
+	return amount, nil
+}
+
+// normalizeDecimal is like NormalizeAmount but also accepts negative amounts, such as discounts.
+func normalizeDecimal(s string) (string, error) {
+	amount := strings.TrimSpace(s)
+	if amount == "" {
+		return "", errors.New("amount is empty")
+	}
+	if strings.Contains(amount, ",") {
+		return "", fmt.Errorf("invalid amount %q: use \".\" as decimal separator, not \",\"", s)
+	}
+	if !decimalPattern.MatchString(amount) {
+		return "", fmt.Errorf("invalid amount %q: not a decimal number", s)
+	}
+	return amount, nil
+}
+
+// SumAmounts adds up decimal amounts exactly, e.g. for revenue reports, and returns the total with as many decimal places as the most precise amount.
+//
+// Every amount must be a decimal number as accepted by NormalizeAmount, or a negative one such as a discount of "-0.75"; the error names the first one that is not. The sum of no amounts is "0".
+func SumAmounts(amounts ...string) (string, error) {
+	total := new(big.Rat)
+	places := 0
+	for i, amount := range amounts {
+		normalized, err := normalizeDecimal(amount)
+		if err != nil {
+			return "", fmt.Errorf("error summing amount %d: %w", i, err)
+		}
+		value, _ := parseDecimal(normalized)
+		total.Add(total, value)
+		if dot := strings.IndexByte(normalized, '.'); dot >= 0 {
+			places = max(places, len(normalized)-dot-1)
+		}
+	}
+	return total.FloatString(places), nil
+}
+
+// SumPaymentAmounts is like SumAmounts for the amount that field returns for each payment, e.g.
+//
+//	total, err := cryptomus.SumPaymentAmounts(payments, func(p cryptomus.Payment) string { return p.PaymentAmount })
+//
+// The error names the uuid of the first payment with an invalid amount.
+func SumPaymentAmounts(payments []Payment, field func(Payment) string) (string, error) {
+	amounts := make([]string, len(payments))
+	for i, payment := range payments {
+		amounts[i] = field(payment)
+		if _, err := normalizeDecimal(amounts[i]); err != nil {
+			return "", fmt.Errorf("error summing payment %s: %w", payment.UUID, err)
+		}
+	}
+	return SumAmounts(amounts...)
+}
+
+// parseOptionalDecimal is like parseDecimal but returns nil without error for an empty value, which is how null amounts decode.
+func parseOptionalDecimal(s string) (*big.Rat, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return parseDecimal(s)
+}
+
+// parseNullableDecimal is like parseOptionalDecimal for nullable fields.
+func parseNullableDecimal(s *string) (*big.Rat, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return parseOptionalDecimal(*s)
+}
+
+// sameDecimal reports whether a and b are the same decimal amount, e.g. "3" and "3.00000000". Malformed amounts are only the same if the strings are equal.
+func sameDecimal(a, b string) bool {
+	x, errX := parseDecimal(a)
+	y, errY := parseDecimal(b)
+	if errX != nil || errY != nil {
+		return a == b
+	}
+	return x.Cmp(y) == 0
+}
+
+// formatDecimal formats r with the given number of decimal places, truncating toward zero so an amount is never overstated.
+func formatDecimal(r *big.Rat, places int) string {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil)
+	scaled := new(big.Int).Quo(new(big.Int).Mul(r.Num(), scale), r.Denom())
+	return new(big.Rat).SetFrac(scaled, scale).FloatString(places)
+}
+
+// numberOrString decodes a JSON number or string into a string, keeping the digits of a number as they are, so no precision is lost. Null and a missing value decode to "".
+func numberOrString(raw json.RawMessage) (string, error) {
+	value := strings.TrimSpace(string(raw))
+	switch {
+	case value == "" || value == "null":
+		return "", nil
+	case strings.HasPrefix(value, `"`):
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return s, err
+	default:
+		var number json.Number
+		if err := json.Unmarshal(raw, &number); err != nil {
+			return "", err
+		}
+		return number.String(), nil
+	}
+}
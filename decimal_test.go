@@ -0,0 +1,120 @@
+package cryptomus_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestNormalizeAmount(t *testing.T) {
+	tests := []struct {
+		amount string
+		want   string
+		err    string
+	}{
+		{"15", "15", ""},
+		{" 0.00064860\n", "0.00064860", ""},
+		{"10,28", "", `use "." as decimal separator`},
+		{"1,000.50", "", `use "." as decimal separator`},
+		{"", "", "empty"},
+		{"  ", "", "empty"},
+		{"1e3", "", "not a decimal number"},
+		{"1/2", "", "not a decimal number"},
+		{"ten", "", "not a decimal number"},
+		{"0x10", "", "not a decimal number"},
+		{"0b101", "", "not a decimal number"},
+		{"1_000", "", "not a decimal number"},
+		{"0x1p4", "", "not a decimal number"},
+		{"+3", "", "not a decimal number"},
+		{".5", "", "not a decimal number"},
+		{"5.", "", "not a decimal number"},
+		{"-5", "", "must not be negative"},
+		{"-0.75", "", "must not be negative"},
+	}
+
+	for _, test := range tests {
+		got, err := cryptomus.NormalizeAmount(test.amount)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("NormalizeAmount(%q): got error %v, want one containing %q", test.amount, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NormalizeAmount(%q): unexpected error: %v", test.amount, err)
+		} else if got != test.want {
+			t.Errorf("NormalizeAmount(%q) = %q, want %q", test.amount, got, test.want)
+		}
+	}
+}
+
+func TestCreateWithCommaAmount(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL)
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+
+	if _, err := merchant.CreateInvoice(cryptomus.Invoice{Amount: "10,28", Currency: "USD", OrderID: "1"}); err == nil || !strings.Contains(err.Error(), `"10,28"`) {
+		t.Errorf("CreateInvoice: got error %v, want one naming the amount", err)
+	}
+	if _, err := merchant.CreatePayout(cryptomus.Withdrawal{Amount: "5,5", Currency: "USDT", OrderID: "1"}); err == nil || !strings.Contains(err.Error(), `"5,5"`) {
+		t.Errorf("CreatePayout: got error %v, want one naming the amount", err)
+	}
+}
+
+func TestSumAmounts(t *testing.T) {
+	tests := []struct {
+		amounts []string
+		want    string
+		err     string
+	}{
+		{nil, "0", ""},
+		{[]string{"0.1", "0.2"}, "0.3", ""},
+		{[]string{"15", "0.00064860", "-0.75"}, "14.25064860", ""},
+		{[]string{"0.00000001", "0.00000001", "0.00000001"}, "0.00000003", ""},
+		{[]string{"1", ""}, "", "amount 1"},
+		{[]string{"1,5"}, "", "amount 0"},
+		{[]string{"1", "0x10"}, "", "amount 1"},
+		{[]string{"0b101"}, "", "amount 0"},
+		{[]string{"1_000"}, "", "amount 0"},
+		{[]string{"0x1p4"}, "", "amount 0"},
+		{[]string{"+3"}, "", "amount 0"},
+		{[]string{".5"}, "", "amount 0"},
+		{[]string{"5."}, "", "amount 0"},
+		{[]string{"--5"}, "", "amount 0"},
+	}
+
+	for _, test := range tests {
+		got, err := cryptomus.SumAmounts(test.amounts...)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("SumAmounts(%q): got error %v, want one containing %q", test.amounts, err, test.err)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("SumAmounts(%q) = %q, %v, want %q", test.amounts, got, err, test.want)
+		}
+	}
+}
+
+func TestSumPaymentAmounts(t *testing.T) {
+	payments := []cryptomus.Payment{
+		{UUID: "a", PaymentAmount: "0.10000000"},
+		{UUID: "b", PaymentAmount: "0.20000000"},
+	}
+	paymentAmount := func(p cryptomus.Payment) string { return p.PaymentAmount }
+
+	total, err := cryptomus.SumPaymentAmounts(payments, paymentAmount)
+	if err != nil || total != "0.30000000" {
+		t.Errorf("got %q, %v, want 0.30000000", total, err)
+	}
+
+	payments = append(payments, cryptomus.Payment{UUID: "c"})
+	if _, err := cryptomus.SumPaymentAmounts(payments, paymentAmount); err == nil || !strings.Contains(err.Error(), "payment c") {
+		t.Errorf("got error %v, want one naming payment c", err)
+	}
+}
@@ -0,0 +1,433 @@
+package cryptomus_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+// The fixtures below are the response examples in the doc comments of the endpoints, so a type that no longer matches its documented response fails here.
+
+const docPaymentResult = `{
+	"uuid": "70b8db5c-b952-406d-af26-4e1c34c27f15",
+	"order_id": "65bbe87b4098c17a31cff3e71e515243",
+	"amount": "15.00",
+	"payment_amount": "0.00",
+	"payer_amount": "15.75",
+	"discount_percent": -5,
+	"discount": "-0.75",
+	"payer_currency": "USDT",
+	"currency": "USDT",
+	"comments": null,
+	"merchant_amount": "15.43500000",
+	"network": "tron",
+	"address": "TXhfYSWt2oKRrHAJVJeYRuit6ZzKuoEKXj",
+	"from": null,
+	"txid": null,
+	"payment_status": "cancel",
+	"url": "https://pay.cryptomus.com/pay/70b8db5c-b952-406d-af26-4e1c34c27f15",
+	"expired_at": 1689099831,
+	"status": "cancel",
+	"is_final": true,
+	"additional_data": null,
+	"created_at": "2023-07-11T20:23:52+03:00",
+	"updated_at": "2023-07-11T21:24:17+03:00"
+}`
+
+const docPayoutResult = `{
+	"uuid": "a7c0caec-a594-4aaa-b1c4-77d511857594",
+	"amount": "3",
+	"currency": "USDT",
+	"network": "TRON",
+	"address": "TJ...",
+	"txid": null,
+	"status": "process",
+	"is_final": false,
+	"balance": 129,
+	"payer_currency": "USD",
+	"payer_amount": 3
+}`
+
+const docMarketOrderResult = `{
+	"order_id": "2d9bf426-98ef-448b-84c2-03cc1ec78feb",
+	"convert_amount_from": "10.000",
+	"convert_amount_to": "3.000",
+	"executed_amount_from": null,
+	"executed_amount_to": null,
+	"convert_currency_from": "USDT",
+	"convert_currency_to": "XMR",
+	"type": "market",
+	"status": "completed",
+	"created_at": "2024-07-11 , 18:06:04",
+	"current_rate": "100",
+	"completed_at": "2024-07-11 , 18:06:04"
+}`
+
+const docRecurringPaymentResult = `{
+	"uuid": "bbe5ce96-1126-4843-a0d2-b432e77669c2",
+	"name": "Access to personal account",
+	"order_id": "1487555",
+	"amount": "5",
+	"currency": "USD",
+	"payer_currency": "USDT",
+	"payer_amount_usd": "5.00",
+	"payer_amount": "5.00",
+	"url_callback": null,
+	"discount_days": "30",
+	"discount_amount": "50.00",
+	"end_of_discount": null,
+	"period": "weekly",
+	"status": "wait_accept",
+	"url": "https://pay.cryptomus.com/pay/bbe5ce96-1126-4843-a0d2-b432e77669c2",
+	"last_pay_off": null
+}`
+
+// decodeDocResult unmarshals the result of a documented response into v, failing the test on unknown shapes.
+func decodeDocResult(t *testing.T, result string, v any) {
+	t.Helper()
+
+	response := `{"state": 0, "result": ` + result + `}`
+	wrapper := struct {
+		State  int `json:"state"`
+		Result any `json:"result"`
+	}{Result: v}
+	if err := json.Unmarshal([]byte(response), &wrapper); err != nil {
+		t.Fatalf("error decoding documented response: %v", err)
+	}
+}
+
+func TestDocExamplePayment(t *testing.T) {
+	var payment cryptomus.Payment
+	decodeDocResult(t, docPaymentResult, &payment)
+
+	if payment.UUID != "70b8db5c-b952-406d-af26-4e1c34c27f15" || payment.OrderID != "65bbe87b4098c17a31cff3e71e515243" {
+		t.Errorf("got uuid %q, order_id %q", payment.UUID, payment.OrderID)
+	}
+	if payment.PayerAmount != "15.75" || payment.DiscountPercent != -5 || payment.Discount != "-0.75" {
+		t.Errorf("got payer_amount %q, discount_percent %d, discount %q", payment.PayerAmount, payment.DiscountPercent, payment.Discount)
+	}
+	if payment.MerchantAmount == nil || *payment.MerchantAmount != "15.43500000" {
+		t.Errorf("got merchant_amount %v, want 15.43500000", payment.MerchantAmount)
+	}
+	if payment.TxID != nil || payment.From != nil {
+		t.Errorf("got txid %v, from %v, want null", payment.TxID, payment.From)
+	}
+	if payment.PaymentStatus != cryptomus.StatusCancel || !payment.IsFinal || payment.ExpiredAt != 1689099831 {
+		t.Errorf("got payment_status %q, is_final %v, expired_at %d", payment.PaymentStatus, payment.IsFinal, payment.ExpiredAt)
+	}
+	if want := time.Date(2023, 7, 11, 17, 23, 52, 0, time.UTC); !payment.CreatedAt.Equal(want) {
+		t.Errorf("got created_at %v, want %v", payment.CreatedAt, want)
+	}
+}
+
+func TestDocExamplePaymentHistory(t *testing.T) {
+	var history struct {
+		Items    []cryptomus.Payment `json:"items"`
+		Paginate cryptomus.Paginate  `json:"paginate"`
+	}
+	decodeDocResult(t, `{
+		"items": [`+docPaymentResult+`],
+		"paginate": {
+			"count": 15,
+			"hasPages": true,
+			"nextCursor": "eyJpZCI6MjkxNTU0MywiX3BvaW50c1RvTmV4dEl0ZW1zIjp0cnVlfQ",
+			"previousCursor": null,
+			"perPage": 15
+		}
+	}`, &history)
+
+	if len(history.Items) != 1 || history.Items[0].UUID != "70b8db5c-b952-406d-af26-4e1c34c27f15" {
+		t.Errorf("got items %+v", history.Items)
+	}
+	if !history.Paginate.HasPages || history.Paginate.NextCursor == "" || history.Paginate.PerPage != 15 {
+		t.Errorf("got paginate %+v", history.Paginate)
+	}
+}
+
+func TestDocExamplePayout(t *testing.T) {
+	var payout cryptomus.Payout
+	decodeDocResult(t, docPayoutResult, &payout)
+
+	if payout.UUID != "a7c0caec-a594-4aaa-b1c4-77d511857594" || payout.Amount != "3" || payout.Network != "TRON" {
+		t.Errorf("got uuid %q, amount %q, network %q", payout.UUID, payout.Amount, payout.Network)
+	}
+	if payout.Status != cryptomus.PayoutStatusProcess || payout.IsFinal || payout.TxID != nil {
+		t.Errorf("got status %q, is_final %v, txid %v", payout.Status, payout.IsFinal, payout.TxID)
+	}
+	if payout.Balance != "129" || payout.PayerCurrency != "USD" || payout.PayerAmount != "3" {
+		t.Errorf("got balance %q, payer_currency %q, payer_amount %q", payout.Balance, payout.PayerCurrency, payout.PayerAmount)
+	}
+}
+
+func TestDocExamplePayoutHistory(t *testing.T) {
+	var history struct {
+		Items    []cryptomus.Payout `json:"items"`
+		Paginate cryptomus.Paginate `json:"paginate"`
+	}
+	decodeDocResult(t, `{
+		"items": [{
+			"uuid": "a7c0caec-a594-4aaa-b1c4-77d511857594",
+			"amount": "3",
+			"currency": "USDT",
+			"network": "TRON",
+			"address": "TJ...",
+			"txid": null,
+			"status": "process",
+			"is_final": false,
+			"balance": "129.00000000",
+			"created_at": "2023-06-21T17:25:55+03:00",
+			"updated_at": "2023-06-21T17:34:38+03:00"
+		}, {
+			"uuid": "92c39264-d180-4503-9c16-ee16f083bbb8",
+			"amount": "5.40000000",
+			"currency": "DOGE",
+			"network": "doge",
+			"address": "DEw8CJLfxg9fhumeXP1zvVNjZicsqtDv7V",
+			"txid": "5e5810946152ea569d2a2aa9aa32a45c0e4223a4f9aad8e31d2fc660d2cdedb8",
+			"order_id": null,
+			"payment_status": null,
+			"status": "paid",
+			"is_final": true,
+			"balance": "26.77966652",
+			"created_at": "2023-07-21T17:25:55+03:00",
+			"updated_at": "2023-07-21T17:34:38+03:00"
+		}],
+		"paginate": {
+			"count": 15,
+			"hasPages": true,
+			"nextCursor": "eyJpZCI6MjkxNTU0MywiX3BvaW50c1RvTmV4dEl0ZW1zIjp0cnVlfQ",
+			"previousCursor": null,
+			"perPage": 15
+		}
+	}`, &history)
+
+	if len(history.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(history.Items))
+	}
+	if history.Items[0].Balance != "129.00000000" || history.Items[1].Balance != "26.77966652" {
+		t.Errorf("got balances %q and %q", history.Items[0].Balance, history.Items[1].Balance)
+	}
+	if balance, err := history.Items[0].BalanceDecimal(); err != nil || balance.Cmp(big.NewRat(129, 1)) != 0 {
+		t.Errorf("got balance %v, error %v, want 129", balance, err)
+	}
+	if history.Items[1].Status != cryptomus.PayoutStatusPaid || history.Items[1].Created().IsZero() {
+		t.Errorf("got status %q created at %v", history.Items[1].Status, history.Items[1].Created())
+	}
+}
+
+func TestPayoutJSONRoundTrip(t *testing.T) {
+	var created, listed cryptomus.Payout
+	decodeDocResult(t, docPayoutResult, &created)
+	decodeDocResult(t, `{"uuid": "a7c0caec-a594-4aaa-b1c4-77d511857594", "balance": "129.00000000"}`, &listed)
+
+	for _, payout := range []cryptomus.Payout{created, listed} {
+		if got := roundTrip(t, payout); got != payout {
+			t.Errorf("got %+v after a round trip, want %+v", got, payout)
+		}
+	}
+	if payerAmount, err := created.PayerAmountDecimal(); err != nil || payerAmount.Cmp(big.NewRat(3, 1)) != 0 {
+		t.Errorf("got payer_amount %v, error %v, want 3", payerAmount, err)
+	}
+
+	var invalid cryptomus.Payout
+	if err := json.Unmarshal([]byte(`{"balance": true}`), &invalid); err == nil {
+		t.Error("expected an error for a boolean balance")
+	}
+}
+
+// roundTrip encodes v to JSON and decodes it back, failing the test on any error, so a type that does not decode what it encodes fails.
+func roundTrip[T any](t *testing.T, v T) T {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("error encoding %T: %v", v, err)
+	}
+	var decoded T
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("error decoding %s: %v", data, err)
+	}
+	return decoded
+}
+
+func TestDocExampleMarketOrder(t *testing.T) {
+	var order cryptomus.MarketOrder
+	decodeDocResult(t, docMarketOrderResult, &order)
+
+	if order.OrderID != "2d9bf426-98ef-448b-84c2-03cc1ec78feb" || order.ConvertCurrencyFrom != "USDT" || order.ConvertCurrencyTo != "XMR" {
+		t.Errorf("got order_id %q, from %q, to %q", order.OrderID, order.ConvertCurrencyFrom, order.ConvertCurrencyTo)
+	}
+	if order.ConvertAmountFrom != "10.000" || order.ExecutedAmountFrom != nil || order.Status != "completed" {
+		t.Errorf("got convert_amount_from %q, executed_amount_from %v, status %q", order.ConvertAmountFrom, order.ExecutedAmountFrom, order.Status)
+	}
+	if order.CreatedAt.IsZero() || order.CompletedAt.IsZero() {
+		t.Errorf("got created_at %v, completed_at %v, want both set", order.CreatedAt, order.CompletedAt)
+	}
+}
+
+func TestDocExampleRecurringPayment(t *testing.T) {
+	var payment cryptomus.RecurringPayment
+	decodeDocResult(t, docRecurringPaymentResult, &payment)
+
+	if payment.UUID != "bbe5ce96-1126-4843-a0d2-b432e77669c2" || payment.Name != "Access to personal account" {
+		t.Errorf("got uuid %q, name %q", payment.UUID, payment.Name)
+	}
+	if payment.OrderID == nil || *payment.OrderID != "1487555" {
+		t.Errorf("got order_id %v, want 1487555", payment.OrderID)
+	}
+	if payment.Amount != "5" || payment.PayerAmountUSD != "5.00" || payment.Period != cryptomus.PeriodWeekly || payment.Status != "wait_accept" {
+		t.Errorf("got amount %q, payer_amount_usd %q, period %q, status %q", payment.Amount, payment.PayerAmountUSD, payment.Period, payment.Status)
+	}
+	if payment.URLCallback != nil || payment.LastPayOff != nil {
+		t.Errorf("got url_callback %v, last_pay_off %v, want null", payment.URLCallback, payment.LastPayOff)
+	}
+}
+
+func TestDocExampleStaticWallet(t *testing.T) {
+	var wallet cryptomus.StaticWalletResponse
+	decodeDocResult(t, `{
+		"wallet_uuid": "de15b0f6-883f-4585-b27b-73a648044a92",
+		"uuid": "87961ae5-80c5-413a-a4fe-d38199894940",
+		"address": "TTEtddVZyNtLD9wbq4PzomjBhtxenSMXbb",
+		"network": "tron",
+		"currency": "USDT",
+		"url": "https://pay.cryptomus.com/wallet/3901446a-4b74-4796-b50a-14e14dafe3ed"
+	}`, &wallet)
+
+	if wallet.WalletUUID != "de15b0f6-883f-4585-b27b-73a648044a92" || wallet.UUID != "87961ae5-80c5-413a-a4fe-d38199894940" {
+		t.Errorf("got wallet_uuid %q, uuid %q", wallet.WalletUUID, wallet.UUID)
+	}
+	if wallet.Address != "TTEtddVZyNtLD9wbq4PzomjBhtxenSMXbb" || wallet.Network != "tron" || wallet.Currency != "USDT" || wallet.URL == "" {
+		t.Errorf("got %+v", wallet)
+	}
+
+	var blocked cryptomus.BlockStaticWalletResponse
+	decodeDocResult(t, `{"uuid": "fcc40793-39f9-4fa9-85b2-93148039a72b", "status": "blocked"}`, &blocked)
+
+	if blocked.UUID != "fcc40793-39f9-4fa9-85b2-93148039a72b" || blocked.Status != "blocked" {
+		t.Errorf("got %+v", blocked)
+	}
+
+	var qr cryptomus.QRCodeResponse
+	decodeDocResult(t, `{"image": "data:image/png;base64,iVBORw0KGgoAAA..."}`, &qr)
+
+	if qr.Image != "data:image/png;base64,iVBORw0KGgoAAA..." {
+		t.Errorf("got image %q", qr.Image)
+	}
+
+	var refund cryptomus.RefundBlockedAddressResponse
+	decodeDocResult(t, `{"commission": "0.50", "amount": "9.50"}`, &refund)
+
+	if refund.Commission != "0.50" || refund.Amount != "9.50" {
+		t.Errorf("got %+v", refund)
+	}
+}
+
+func TestDocExampleDiscount(t *testing.T) {
+	var discount cryptomus.Discount
+	decodeDocResult(t, `{"currency": "BUSD", "network": "bsc", "discount": "-10"}`, &discount)
+
+	if discount.Currency != "BUSD" || discount.Network != "bsc" || discount.Discount != "-10" {
+		t.Errorf("got %+v", discount)
+	}
+}
+
+func TestDocExampleUserEndpoints(t *testing.T) {
+	var wallets struct {
+		Balances []cryptomus.UserWallet `json:"balances"`
+	}
+	decodeDocResult(t, `{
+		"balances": [
+			{
+				"walletUuid": "4ba23a47-a182-4d87-8c68-247c974be566",
+				"currency_code": "BCH",
+				"balance": "0.00000000",
+				"balanceUsd": "0.00"
+			}
+		]
+	}`, &wallets)
+
+	if len(wallets.Balances) != 1 || wallets.Balances[0].WalletUUID != "4ba23a47-a182-4d87-8c68-247c974be566" || wallets.Balances[0].CurrencyCode != "BCH" || wallets.Balances[0].BalanceUSD != "0.00" {
+		t.Errorf("got balances %+v", wallets.Balances)
+	}
+
+	var directions struct {
+		Items []cryptomus.Direction `json:"items"`
+	}
+	decodeDocResult(t, `{
+		"items": [
+			{
+				"from": "TRX",
+				"to": "ETH",
+				"min_from": "100.00000000",
+				"min_to": "0.00100000",
+				"max_from": "100000.00000000",
+				"max_to": "1000000.00000000",
+				"rate": "0.00003451"
+			}
+		]
+	}`, &directions)
+
+	if len(directions.Items) != 1 || directions.Items[0].From != "TRX" || directions.Items[0].MinTo != "0.00100000" || directions.Items[0].Rate != "0.00003451" {
+		t.Errorf("got directions %+v", directions.Items)
+	}
+
+	var convert cryptomus.CalculateConvertResponse
+	decodeDocResult(t, `{
+		"from": "0.001",
+		"approximate_rate": "57853.000",
+		"commission": "3",
+		"total_amount": "60.000",
+		"to": "60.000"
+	}`, &convert)
+
+	if convert.From != "0.001" || convert.Approximate_rate != "57853.000" || convert.TotalAmount != "60.000" || convert.To != "60.000" {
+		t.Errorf("got %+v", convert)
+	}
+
+	var transfer cryptomus.TransferResponse
+	decodeDocResult(t, `{
+		"user_wallet_transaction_uuid": "26109ba0-b05b-4ee0-93d1-fd62c822ce95",
+		"user_wallet_balance": "15.00000000",
+		"merchant_transaction_uuid": "95bfcabb-a0ab-48f1-80b3-ce3bc2dbb653",
+		"merchant_balance": "20.00000000"
+	}`, &transfer)
+
+	if transfer.UserWalletTransactionUUID != "26109ba0-b05b-4ee0-93d1-fd62c822ce95" || transfer.UserWalletBalance != "15.00000000" || transfer.MerchantBalance != "20.00000000" {
+		t.Errorf("got %+v", transfer)
+	}
+}
+
+func TestDocExamplePublicEndpoints(t *testing.T) {
+	var rates []cryptomus.ExchangeRate
+	decodeDocResult(t, `[{"from": "ETH", "to": "USD", "course": "1228.45000000"}]`, &rates)
+
+	if len(rates) != 1 || rates[0].From != "ETH" || rates[0].To != "USD" || rates[0].Course != "1228.45000000" {
+		t.Errorf("got rates %+v", rates)
+	}
+
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"data": {
+				"timestamp": "1724069797.1308",
+				"bids": [{"price": "0.04548320", "quantity": "12462000"}, {"price": "3.00000000", "quantity": "12457000"}],
+				"asks": [{"price": "2.73042000", "quantity": "12506000"}, {"price": "0.33660000", "quantity": "12508000"}]
+			}
+		}`))
+	})
+
+	book, err := cryptomus.GetOrderBookSnapshot("TRX_USDT", 0)
+	if err != nil {
+		t.Fatalf("error decoding documented order book: %v", err)
+	}
+	if len(book.Bids) != 2 || len(book.Asks) != 2 || book.Bids[0].Price != "0.04548320" || book.Asks[1].Quantity != "12508000" {
+		t.Errorf("got bids %+v, asks %+v", book.Bids, book.Asks)
+	}
+	if book.Timestamp.Unix() != 1724069797 {
+		t.Errorf("got timestamp %v, want unix 1724069797", book.Timestamp)
+	}
+}
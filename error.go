@@ -0,0 +1,264 @@
+package cryptomus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// APIError is returned when Cryptomus answers a request with a non-200 status, a non-zero state or error details.
+//
+// Use errors.As to inspect it, or the helpers IsNotEnoughFunds, IsForbidden, IsNotFound, IsValidationError and IsServerError.
+//
+// # Response examples
+//
+//	{
+//		"message": "Not enough funds",
+//		"state": 1
+//	}
+//
+//	{
+//		"state": 1,
+//		"errors": {
+//			"amount": ["validation.required"],
+//			"currency": ["validation.required"]
+//		}
+//	}
+//
+//	{
+//		"message": "Server error, #1",
+//		"code": 500
+//	}
+type APIError struct {
+	// HTTP status code of the response
+	HTTPStatus int
+	// State of the response, 0 on success
+	State int
+	// Code sent in the response body, e.g. 500 for "Server error, #1". Zero when the server sent none.
+	Code int
+	// Message sent by Cryptomus, e.g. "Not enough funds". Empty when the server sent none.
+	Message string
+	// Validation errors by request field, e.g. {"amount": ["validation.required"]}. Nil when the server sent none.
+	Errors map[string][]string
+}
+
+func (e *APIError) Error() string {
+	status := fmt.Sprintf("%d %s", e.HTTPStatus, http.StatusText(e.HTTPStatus))
+
+	var details []string
+	if e.Message != "" {
+		details = append(details, e.Message)
+	}
+	fields := make([]string, 0, len(e.Errors))
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		details = append(details, fmt.Sprintf("%s: %s", field, strings.Join(e.Errors[field], ", ")))
+	}
+
+	if len(details) == 0 {
+		return fmt.Sprintf("request failed with status %s (state=%d) with no details from server", status, e.State)
+	}
+	return fmt.Sprintf("error with status %s: %s", status, strings.Join(details, "; "))
+}
+
+// checkResponse returns an *APIError if the response failed, and nil otherwise.
+//
+// Cryptomus reports a failure with a non-200 status, a non-zero state, or a message, error or errors field in the body.
+// The error field is only used by some endpoints instead of message; both end up in APIError.Message.
+func checkResponse(httpResponse *http.Response, state, code int, message, errorMessage string, errs map[string][]string) error {
+	if httpResponse.StatusCode == http.StatusOK && state == 0 && message == "" && errorMessage == "" && len(errs) == 0 {
+		return nil
+	}
+
+	switch {
+	case message == "":
+		message = errorMessage
+	case errorMessage != "" && errorMessage != message:
+		message += "; " + errorMessage
+	}
+
+	apiError := APIError{
+		HTTPStatus: httpResponse.StatusCode,
+		State:      state,
+		Code:       code,
+		Message:    message,
+		Errors:     errs,
+	}
+	if httpResponse.StatusCode == http.StatusTooManyRequests {
+		retryAfter, _ := parseRetryAfter(httpResponse.Header.Get("Retry-After"))
+		return &RateLimitError{APIError: apiError, RetryAfter: retryAfter}
+	}
+	if match := amountLimitMessage.FindStringSubmatch(message); match != nil {
+		rangeError := &AmountOutOfRangeError{APIError: apiError, Currency: match[3]}
+		if match[1] == "Minimum" {
+			rangeError.Min = match[2]
+		} else {
+			rangeError.Max = match[2]
+		}
+		return rangeError
+	}
+	return &apiError
+}
+
+// amountLimitMessage matches the messages of an amount out of range, e.g. "Minimum amount 0.5 USDT" or "Maximum amount 10000000 USDT".
+var amountLimitMessage = regexp.MustCompile(`^(Minimum|Maximum) amount ([0-9]+(?:\.[0-9]+)?) ([A-Za-z0-9_]+)$`)
+
+// AmountOutOfRangeError is returned when Cryptomus rejects an amount below its minimum or above its maximum, e.g. "Minimum amount 0.5 USDT" for a transfer.
+//
+// Only the limit Cryptomus sent is set. errors.As also finds the *APIError it wraps.
+type AmountOutOfRangeError struct {
+	APIError
+	// Minimum amount, e.g. "0.5". Empty if the amount was above the maximum.
+	Min string
+	// Maximum amount, e.g. "10000000". Empty if the amount was below the minimum.
+	Max string
+	// Currency code of the limit, e.g. "USDT"
+	Currency string
+}
+
+// Unwrap returns the APIError of e, so the APIError helpers such as IsValidationError work on it.
+func (e *AmountOutOfRangeError) Unwrap() error {
+	return &e.APIError
+}
+
+// responseErrors decodes the errors member of a response into validation errors by field, whatever fields Cryptomus reports.
+//
+// Besides the documented {"field": ["message"]}, it accepts a single message per field, the empty list [] sent when there are no errors,
+// and the [{"property": "field", "value": "...", "message": "..."}] list of the market endpoints.
+type responseErrors map[string][]string
+
+func (e *responseErrors) UnmarshalJSON(data []byte) error {
+	var byField map[string]json.RawMessage
+	if err := json.Unmarshal(data, &byField); err == nil {
+		if len(byField) == 0 {
+			*e = nil
+			return nil
+		}
+		errs := make(responseErrors, len(byField))
+		for field, raw := range byField {
+			var messages []string
+			if err := json.Unmarshal(raw, &messages); err != nil {
+				var message string
+				if err := json.Unmarshal(raw, &message); err != nil {
+					return fmt.Errorf("invalid errors of field %q: %s", field, raw)
+				}
+				messages = []string{message}
+			}
+			errs[field] = messages
+		}
+		*e = errs
+		return nil
+	}
+
+	var list []struct {
+		Property string `json:"property"`
+		Value    any    `json:"value"`
+		Message  string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("invalid errors: %s", data)
+	}
+	*e = nil
+	for _, item := range list {
+		if *e == nil {
+			*e = make(responseErrors)
+		}
+		(*e)[item.Property] = append((*e)[item.Property], fmt.Sprintf("%s (value: %v)", item.Message, item.Value))
+	}
+	return nil
+}
+
+// RateLimitError is returned when Cryptomus answers with 429 Too Many Requests, after any retries configured with WithRetry.
+//
+// The request was not processed; send it again once RetryAfter has passed. errors.As also finds the *APIError it wraps.
+type RateLimitError struct {
+	APIError
+	// Time to wait before sending the request again, from the Retry-After header. Zero if the response had none.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s (retry after %s)", e.APIError.Error(), e.RetryAfter)
+	}
+	return e.APIError.Error()
+}
+
+// Unwrap returns the APIError of e, so the APIError helpers such as IsServerError work on it.
+func (e *RateLimitError) Unwrap() error {
+	return &e.APIError
+}
+
+// Errors that an APIError matches with errors.Is, by the message Cryptomus sent.
+var (
+	// ErrRefundInProgress is matched by the "Refund is in process" error of RefundBlockedAddress. The refund was accepted before and is still running: wait and check the wallet, e.g. with a webhook, instead of sending the refund again right away.
+	ErrRefundInProgress = errors.New("refund is in process")
+	// ErrWithdrawOnlyOnce is matched by the "You can withdraw only once" error of RefundBlockedAddress. The funds of the blocked wallet were already refunded, so no retry will succeed.
+	ErrWithdrawOnlyOnce = errors.New("withdrawal was already made")
+	// ErrNothingToWithdraw is matched by the "Nothing to withdraw" error of RefundBlockedAddress. No payments were made to the blocked wallet, so there is nothing to refund until one is.
+	ErrNothingToWithdraw = errors.New("nothing to withdraw")
+)
+
+var messageErrors = map[string]error{
+	"Refund is in process":       ErrRefundInProgress,
+	"You can withdraw only once": ErrWithdrawOnlyOnce,
+	"Nothing to withdraw":        ErrNothingToWithdraw,
+}
+
+// Is reports whether the message of e is the one of target, e.g. ErrRefundInProgress, so errors.Is can be used on the errors of any method.
+func (e *APIError) Is(target error) bool {
+	return target != nil && messageErrors[e.Message] == target
+}
+
+func asAPIError(err error) (*APIError, bool) {
+	var apiError *APIError
+	ok := errors.As(err, &apiError)
+	return apiError, ok
+}
+
+func isAPIError(err error) bool {
+	_, ok := asAPIError(err)
+	return ok
+}
+
+// IsNotEnoughFunds reports whether err is an APIError for a balance too low for the operation, e.g. a payout, transfer or convert.
+func IsNotEnoughFunds(err error) bool {
+	apiError, ok := asAPIError(err)
+	return ok && (apiError.Message == "Not enough funds" || strings.HasPrefix(apiError.Message, "Not enough balance"))
+}
+
+// IsForbidden reports whether err is an APIError for a request Cryptomus refused, e.g. because of an invalid signature or a disabled API key.
+func IsForbidden(err error) bool {
+	apiError, ok := asAPIError(err)
+	return ok && (apiError.HTTPStatus == http.StatusForbidden || apiError.HTTPStatus == http.StatusUnauthorized || apiError.Message == "You are forbidden")
+}
+
+// IsNotFound reports whether err is an APIError for a payment, payout, wallet, service or other entity that does not exist.
+func IsNotFound(err error) bool {
+	apiError, ok := asAPIError(err)
+	return ok && (apiError.HTTPStatus == http.StatusNotFound || strings.Contains(strings.ToLower(apiError.Message), "not found"))
+}
+
+// IsValidationError reports whether err is an APIError for request parameters Cryptomus rejected, e.g. a missing amount, or a ValidationError for parameters rejected before sending.
+func IsValidationError(err error) bool {
+	var validationError *ValidationError
+	if errors.As(err, &validationError) {
+		return true
+	}
+	apiError, ok := asAPIError(err)
+	return ok && (apiError.HTTPStatus == http.StatusUnprocessableEntity || len(apiError.Errors) > 0)
+}
+
+// IsServerError reports whether err is an APIError for a Cryptomus server failure, e.g. "Server error, #1". Such requests may succeed when sent again.
+func IsServerError(err error) bool {
+	apiError, ok := asAPIError(err)
+	return ok && (apiError.HTTPStatus >= http.StatusInternalServerError || apiError.Code >= http.StatusInternalServerError)
+}
@@ -0,0 +1,209 @@
+package cryptomus_test
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestAPIErrorWithoutDetails(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"state":1}`))
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	orderID := "1"
+	_, err := merchant.BlockStaticWallet(cryptomus.BlockStaticWalletRequest{RecordID: cryptomus.RecordID{OrderID: &orderID}})
+
+	var apiError *cryptomus.APIError
+	if !errors.As(err, &apiError) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiError.HTTPStatus != http.StatusUnprocessableEntity || apiError.State != 1 || apiError.Message != "" {
+		t.Errorf("unexpected APIError %+v", apiError)
+	}
+	if want := "request failed with status 422 Unprocessable Entity (state=1) with no details from server"; err.Error() != want {
+		t.Errorf("expected error %q, got %q", want, err.Error())
+	}
+	if strings.HasSuffix(err.Error(), ": ") {
+		t.Errorf("error %q has an empty details list", err)
+	}
+}
+
+func TestAPIErrorFromResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		want       cryptomus.APIError
+		wantString string
+		is         func(error) bool
+	}{
+		{
+			name:       "not enough funds",
+			status:     http.StatusBadRequest,
+			body:       `{"state": 1, "message": "Not enough funds"}`,
+			want:       cryptomus.APIError{HTTPStatus: 400, State: 1, Message: "Not enough funds"},
+			wantString: "error with status 400 Bad Request: Not enough funds",
+			is:         cryptomus.IsNotEnoughFunds,
+		},
+		{
+			name:       "forbidden",
+			status:     http.StatusUnauthorized,
+			body:       `{"state": 1, "message": "You are forbidden"}`,
+			want:       cryptomus.APIError{HTTPStatus: 401, State: 1, Message: "You are forbidden"},
+			wantString: "error with status 401 Unauthorized: You are forbidden",
+			is:         cryptomus.IsForbidden,
+		},
+		{
+			name:       "not found",
+			status:     http.StatusBadRequest,
+			body:       `{"state": 1, "message": "Payment not found"}`,
+			want:       cryptomus.APIError{HTTPStatus: 400, State: 1, Message: "Payment not found"},
+			wantString: "error with status 400 Bad Request: Payment not found",
+			is:         cryptomus.IsNotFound,
+		},
+		{
+			name:       "validation",
+			status:     http.StatusUnprocessableEntity,
+			body:       `{"state": 1, "errors": {"uuid": ["validation.required_without"], "order_id": ["validation.required_without"]}}`,
+			want:       cryptomus.APIError{HTTPStatus: 422, State: 1},
+			wantString: "error with status 422 Unprocessable Entity: order_id: validation.required_without; uuid: validation.required_without",
+			is:         cryptomus.IsValidationError,
+		},
+		{
+			name:       "server error",
+			status:     http.StatusInternalServerError,
+			body:       `{"message": "Server error, #1", "code": 500}`,
+			want:       cryptomus.APIError{HTTPStatus: 500, Code: 500, Message: "Server error, #1"},
+			wantString: "error with status 500 Internal Server Error: Server error, #1",
+			is:         cryptomus.IsServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				w.Write([]byte(test.body))
+			})
+
+			merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+			orderID := "1"
+			_, err := merchant.GetPaymentInformation(cryptomus.RecordID{OrderID: &orderID})
+
+			var apiError *cryptomus.APIError
+			if !errors.As(err, &apiError) {
+				t.Fatalf("expected APIError, got %v", err)
+			}
+			if apiError.HTTPStatus != test.want.HTTPStatus || apiError.State != test.want.State || apiError.Code != test.want.Code || apiError.Message != test.want.Message {
+				t.Errorf("got %+v, want %+v", apiError, test.want)
+			}
+			if err.Error() != test.wantString {
+				t.Errorf("got error %q, want %q", err.Error(), test.wantString)
+			}
+			if !test.is(err) {
+				t.Errorf("helper did not match %v", err)
+			}
+			if test.name != "not enough funds" && cryptomus.IsNotEnoughFunds(err) {
+				t.Errorf("IsNotEnoughFunds matched %v", err)
+			}
+		})
+	}
+
+	if cryptomus.IsNotFound(errors.New("not found")) {
+		t.Error("helpers should only match APIError")
+	}
+}
+
+func TestAPIErrorFieldErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want map[string][]string
+	}{
+		{
+			name: "unexpected fields",
+			body: `{"state": 1, "errors": {"lifetime": ["validation.between.numeric"], "memo": ["validation.max.string"]}}`,
+			want: map[string][]string{"lifetime": {"validation.between.numeric"}, "memo": {"validation.max.string"}},
+		},
+		{
+			name: "single message",
+			body: `{"state": 1, "errors": {"priority": "validation.in"}}`,
+			want: map[string][]string{"priority": {"validation.in"}},
+		},
+		{
+			name: "empty list",
+			body: `{"state": 1, "message": "Payment not found", "errors": []}`,
+		},
+		{
+			name: "market list",
+			body: `{"code": 422, "errors": [{"property": "level", "value": "7", "message": "The selected level is invalid."}]}`,
+			want: map[string][]string{"level": {"The selected level is invalid. (value: 7)"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				w.Write([]byte(test.body))
+			})
+
+			merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+			_, err := merchant.GetPaymentInformation(cryptomus.ByOrderID("1"))
+
+			var apiError *cryptomus.APIError
+			if !errors.As(err, &apiError) {
+				t.Fatalf("expected APIError, got %v", err)
+			}
+			if !reflect.DeepEqual(apiError.Errors, test.want) {
+				t.Errorf("got errors %v, want %v", apiError.Errors, test.want)
+			}
+		})
+	}
+}
+
+func TestRefundBlockedAddressSentinels(t *testing.T) {
+	tests := []struct {
+		message string
+		want    error
+		notWant error
+	}{
+		{"Refund is in process", cryptomus.ErrRefundInProgress, cryptomus.ErrWithdrawOnlyOnce},
+		{"You can withdraw only once", cryptomus.ErrWithdrawOnlyOnce, cryptomus.ErrRefundInProgress},
+		{"Nothing to withdraw", cryptomus.ErrNothingToWithdraw, cryptomus.ErrWithdrawOnlyOnce},
+	}
+
+	for _, test := range tests {
+		t.Run(test.message, func(t *testing.T) {
+			newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"state": 1, "message": "` + test.message + `"}`))
+			})
+
+			merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+			orderID := "1"
+			_, err := merchant.RefundBlockedAddress(cryptomus.RefundBlockedAddressRequest{RecordID: cryptomus.RecordID{OrderID: &orderID}, Address: "TDD97yguPESTpcrJMqU6h2ozZbibv4Vaqm"})
+
+			if !errors.Is(err, test.want) {
+				t.Errorf("errors.Is(%v, %v) = false", err, test.want)
+			}
+			if errors.Is(err, test.notWant) {
+				t.Errorf("errors.Is(%v, %v) = true", err, test.notWant)
+			}
+			var apiError *cryptomus.APIError
+			if !errors.As(err, &apiError) || apiError.Message != test.message {
+				t.Errorf("got %v, want an APIError with message %q", err, test.message)
+			}
+		})
+	}
+
+	if errors.Is(&cryptomus.APIError{Message: "Not enough funds"}, cryptomus.ErrRefundInProgress) {
+		t.Error("unrelated APIError matches ErrRefundInProgress")
+	}
+}
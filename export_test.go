@@ -0,0 +1,4 @@
+package cryptomus
+
+// Backoff exposes backoff to the tests of package cryptomus_test.
+var Backoff = backoff
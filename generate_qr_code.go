@@ -1,10 +1,8 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strings"
 )
 
 // QRCodeForStaticWalletRequest represents the request to generate a QR code for a static wallet.
@@ -63,7 +61,12 @@ type QRCodeResponse struct {
 //		}
 //	}
 func (m *Merchant) GenerateQRCodeForStaticWallet(request QRCodeForStaticWalletRequest) (*QRCodeResponse, error) {
-	httpResponse, err := m.sendPaymentRequest("POST", urlGenerateQRCodeForStaticWallet, request)
+	return m.GenerateQRCodeForStaticWalletContext(context.Background(), request)
+}
+
+// GenerateQRCodeForStaticWalletContext is like GenerateQRCodeForStaticWallet but sends the request with the provided context.
+func (m *Merchant) GenerateQRCodeForStaticWalletContext(ctx context.Context, request QRCodeForStaticWalletRequest) (*QRCodeResponse, error) {
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlGenerateQRCodeForStaticWallet, request)
 	if err != nil {
 		return nil, err
 	}
@@ -73,27 +76,16 @@ func (m *Merchant) GenerateQRCodeForStaticWallet(request QRCodeForStaticWalletRe
 		State   int            `json:"state"`
 		Result  QRCodeResponse `json:"result"`
 		Message string         `json:"message"`
-		Errors  struct {
-			WalletAddressUUID []string `json:"wallet_address_uuid"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	errs = append(errs, response.Errors.WalletAddressUUID...)
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
@@ -112,7 +104,12 @@ func (m *Merchant) GenerateQRCodeForStaticWallet(request QRCodeForStaticWalletRe
 //		}
 //	}
 func (m *Merchant) GenerateQRCodeForInvoice(request QRCodeForInvoiceRequest) (*QRCodeResponse, error) {
-	httpResponse, err := m.sendPaymentRequest("POST", urlGenerateQRCodeForInvoice, request)
+	return m.GenerateQRCodeForInvoiceContext(context.Background(), request)
+}
+
+// GenerateQRCodeForInvoiceContext is like GenerateQRCodeForInvoice but sends the request with the provided context.
+func (m *Merchant) GenerateQRCodeForInvoiceContext(ctx context.Context, request QRCodeForInvoiceRequest) (*QRCodeResponse, error) {
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlGenerateQRCodeForInvoice, request)
 	if err != nil {
 		return nil, err
 	}
@@ -122,28 +119,17 @@ func (m *Merchant) GenerateQRCodeForInvoice(request QRCodeForInvoiceRequest) (*Q
 		State   int            `json:"state"`
 		Result  QRCodeResponse `json:"result"`
 		Message string         `json:"message"`
-		Errors  struct {
-			MerchantPaymentUUID []string `json:"merchant_payment_uuid"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	errs = append(errs, response.Errors.MerchantPaymentUUID...)
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
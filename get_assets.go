@@ -1,9 +1,12 @@
 package cryptomus
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
+	"math/big"
+	"strings"
 )
 
 // See "Get assets" https://doc.cryptomus.com/personal/market-cap/assets
@@ -20,10 +23,78 @@ type Asset struct {
 	MinWithdraw string `json:"min_withdraw"`
 	// Maximum withdraw value
 	MaxWithdraw string `json:"max_withdraw"`
-	// Maximum deposit value
-	MaxDeposit string `json:"max_deposit"`
-	// Minimum deposit value
-	MinDeposit string `json:"min_deposit"`
+	// Maximum deposit value, or nil if the asset cannot be deposited, e.g. a withdraw-only coin like CRMS
+	MaxDeposit *string `json:"max_deposit"`
+	// Minimum deposit value, or nil if the asset cannot be deposited
+	MinDeposit *string `json:"min_deposit"`
+}
+
+// DepositEnabled reports whether the asset can be deposited. The deposit limits of an asset that cannot be deposited are null.
+func (a Asset) DepositEnabled() bool {
+	return a.CanDeposit
+}
+
+// WithdrawRange returns the minimum and maximum withdraw amounts. ok is false unless both are set and are decimal numbers.
+func (a Asset) WithdrawRange() (min, max string, ok bool) {
+	return assetRange(a.MinWithdraw, a.MaxWithdraw)
+}
+
+// DepositRange returns the minimum and maximum deposit amounts. ok is false unless both are set and are decimal numbers.
+func (a Asset) DepositRange() (min, max string, ok bool) {
+	return assetRange(deref(a.MinDeposit), deref(a.MaxDeposit))
+}
+
+// MinWithdrawDecimal returns min_withdraw as an exact decimal, or nil if it is null.
+func (a Asset) MinWithdrawDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(a.MinWithdraw)
+}
+
+// MaxWithdrawDecimal returns max_withdraw as an exact decimal, or nil if it is null.
+func (a Asset) MaxWithdrawDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(a.MaxWithdraw)
+}
+
+// MinDepositDecimal returns min_deposit as an exact decimal, or nil if it is null.
+func (a Asset) MinDepositDecimal() (*big.Rat, error) {
+	return parseNullableDecimal(a.MinDeposit)
+}
+
+// MaxDepositDecimal returns max_deposit as an exact decimal, or nil if it is null.
+func (a Asset) MaxDepositDecimal() (*big.Rat, error) {
+	return parseNullableDecimal(a.MaxDeposit)
+}
+
+// CanWithdrawAmount reports whether amount can be withdrawn: the asset can be withdrawn and amount is within the withdraw limits, limits included.
+//
+// A null limit does not restrict the amount; a malformed amount or limit is never within the limits.
+func (a Asset) CanWithdrawAmount(amount string) bool {
+	if !a.CanWithdraw {
+		return false
+	}
+	value, err := parseDecimal(amount)
+	if err != nil {
+		return false
+	}
+	minimum, err := parseOptionalDecimal(a.MinWithdraw)
+	if err != nil || (minimum != nil && value.Cmp(minimum) < 0) {
+		return false
+	}
+	maximum, err := parseOptionalDecimal(a.MaxWithdraw)
+	if err != nil || (maximum != nil && value.Cmp(maximum) > 0) {
+		return false
+	}
+	return true
+}
+
+// assetRange returns min and max if both are decimal numbers.
+func assetRange(min, max string) (string, string, bool) {
+	if _, err := parseDecimal(min); err != nil {
+		return "", "", false
+	}
+	if _, err := parseDecimal(max); err != nil {
+		return "", "", false
+	}
+	return min, max, true
 }
 
 // See "Get assets" https://doc.cryptomus.com/personal/market-cap/assets
@@ -54,24 +125,60 @@ type Asset struct {
 //		  ]
 //	}
 func GetAssets() ([]Asset, error) {
-	response, err := http.Get(urlGetAssets)
+	return GetAssetsContext(context.Background())
+}
+
+// GetAssetsContext is like GetAssets but sends the request with the provided context.
+func GetAssetsContext(ctx context.Context) ([]Asset, error) {
+	response, err := sendPublicRequest(ctx, urlGetAssets)
 	if err != nil {
 		return nil, fmt.Errorf("error sending GET request: %w", err)
 	}
 	defer response.Body.Close()
 
 	var responseStruct struct {
-		State  int     `json:"state"`
-		Result []Asset `json:"result"`
+		State   int            `json:"state"`
+		Result  []Asset        `json:"result"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}
 
 	if err := json.NewDecoder(response.Body).Decode(&responseStruct); err != nil {
 		return nil, fmt.Errorf("error decoding response payload: %w", err)
 	}
 
-	if response.StatusCode != http.StatusOK || responseStruct.State != 0 {
-		return nil, fmt.Errorf("error fetching assets with status %s", response.Status)
+	if err := checkResponse(response, responseStruct.State, responseStruct.Code, responseStruct.Message, responseStruct.Error, responseStruct.Errors); err != nil {
+		return nil, err
 	}
 
 	return responseStruct.Result, nil
 }
+
+// ErrAssetNotFound is returned by GetAsset when there is no asset for the currency and network.
+var ErrAssetNotFound = errors.New("asset not found")
+
+// GetAsset is like GetAssets but returns only the asset of one currency on one network, e.g. USDT on tron. Codes are matched case-insensitively.
+//
+// The returned error wraps ErrAssetNotFound if the currency and network are not in the list.
+//
+// See "Get assets" https://doc.cryptomus.com/personal/market-cap/assets
+func GetAsset(currency, network string) (*Asset, error) {
+	return GetAssetContext(context.Background(), currency, network)
+}
+
+// GetAssetContext is like GetAsset but sends the request with the provided context.
+func GetAssetContext(ctx context.Context, currency, network string) (*Asset, error) {
+	assets, err := GetAssetsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, asset := range assets {
+		if strings.EqualFold(asset.CurrencyCode, currency) && strings.EqualFold(asset.NetworkCode, network) {
+			return &asset, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s on %s", ErrAssetNotFound, currency, network)
+}
@@ -0,0 +1,134 @@
+package cryptomus_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestGetAssetsContextCancellation(t *testing.T) {
+	newMockServer(t, blockUntilCancelled(t))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := cryptomus.GetAssetsContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context deadline error, got %v", err)
+	}
+}
+
+func TestAssetRanges(t *testing.T) {
+	crms := cryptomus.Asset{CurrencyCode: "CRMS", NetworkCode: "polygon", CanWithdraw: true, MinWithdraw: "1.00000000", MaxWithdraw: "10000000.00000000"}
+	maxDeposit, minDeposit := "1000000.00000000", "0.02000000"
+	dash := cryptomus.Asset{CurrencyCode: "DASH", NetworkCode: "dash", CanWithdraw: true, CanDeposit: true, MinWithdraw: "0.01000000", MaxWithdraw: "1000000.00000000", MaxDeposit: &maxDeposit, MinDeposit: &minDeposit}
+
+	if min, max, ok := crms.WithdrawRange(); !ok || min != "1.00000000" || max != "10000000.00000000" {
+		t.Errorf("got withdraw range %s-%s, %t", min, max, ok)
+	}
+	if _, _, ok := crms.DepositRange(); ok {
+		t.Error("expected no deposit range with null limits")
+	}
+	if min, max, ok := dash.DepositRange(); !ok || min != "0.02000000" || max != "1000000.00000000" {
+		t.Errorf("got deposit range %s-%s, %t", min, max, ok)
+	}
+
+	tests := []struct {
+		asset  cryptomus.Asset
+		amount string
+		want   bool
+	}{
+		{crms, "1", true},
+		{crms, "0.99999999", false},
+		{crms, "10000000.00000001", false},
+		{crms, "1,5", false},
+		{dash, "0.01", true},
+		{cryptomus.Asset{CanWithdraw: true, MinWithdraw: "1"}, "1000000000", true},
+		{cryptomus.Asset{CanWithdraw: false}, "1", false},
+	}
+	for _, test := range tests {
+		if got := test.asset.CanWithdrawAmount(test.amount); got != test.want {
+			t.Errorf("%s %s: got %t, want %t", test.asset.CurrencyCode, test.amount, got, test.want)
+		}
+	}
+}
+
+const assetsFixture = `{
+	"state": 0,
+	"result": [
+		{
+			"currency_code": "CRMS",
+			"network_code": "polygon",
+			"can_withdraw": true,
+			"can_deposit": false,
+			"min_withdraw": "1.00000000",
+			"max_withdraw": "10000000.00000000",
+			"max_deposit": null,
+			"min_deposit": null
+		},
+		{
+			"currency_code": "DASH",
+			"network_code": "dash",
+			"can_withdraw": true,
+			"can_deposit": true,
+			"min_withdraw": "0.01000000",
+			"max_withdraw": "1000000.00000000",
+			"max_deposit": "1000000.00000000",
+			"min_deposit": "0.02000000"
+		}
+	]
+}`
+
+func TestGetAsset(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/exchange/market/assets" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(assetsFixture))
+	})
+
+	asset, err := cryptomus.GetAsset("crms", "Polygon")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if asset.CurrencyCode != "CRMS" || !asset.CanWithdraw || asset.DepositEnabled() {
+		t.Errorf("got asset %+v", asset)
+	}
+	if asset.MinDeposit != nil || asset.MaxDeposit != nil {
+		t.Errorf("got deposit limits %v-%v for null, want nil", asset.MinDeposit, asset.MaxDeposit)
+	}
+	minWithdraw, err := asset.MinWithdrawDecimal()
+	if err != nil || minWithdraw.Cmp(big.NewRat(1, 1)) != 0 {
+		t.Errorf("got min_withdraw %v, error %v, want 1", minWithdraw, err)
+	}
+	maxWithdraw, err := asset.MaxWithdrawDecimal()
+	if err != nil || maxWithdraw.Cmp(big.NewRat(10000000, 1)) != 0 {
+		t.Errorf("got max_withdraw %v, error %v, want 10000000", maxWithdraw, err)
+	}
+	if minDeposit, err := asset.MinDepositDecimal(); minDeposit != nil || err != nil {
+		t.Errorf("got min_deposit %v, error %v for null, want nil", minDeposit, err)
+	}
+	if maxDeposit, err := asset.MaxDepositDecimal(); maxDeposit != nil || err != nil {
+		t.Errorf("got max_deposit %v, error %v for null, want nil", maxDeposit, err)
+	}
+
+	dash, err := cryptomus.GetAsset("DASH", "dash")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !dash.DepositEnabled() || dash.MinDeposit == nil || *dash.MinDeposit != "0.02000000" {
+		t.Errorf("got deposit enabled %v with min_deposit %v", dash.DepositEnabled(), dash.MinDeposit)
+	}
+	if minDeposit, err := dash.MinDepositDecimal(); err != nil || minDeposit.Cmp(big.NewRat(2, 100)) != 0 {
+		t.Errorf("got min_deposit %v, error %v, want 0.02", minDeposit, err)
+	}
+
+	if _, err := cryptomus.GetAsset("USDT", "tron"); !errors.Is(err, cryptomus.ErrAssetNotFound) {
+		t.Errorf("got error %v, want ErrAssetNotFound", err)
+	}
+}
@@ -1,9 +1,8 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
 	"strings"
 )
 
@@ -105,7 +104,12 @@ type UserWallet struct {
 //	    ]
 //	}
 func (m *Merchant) GetBalance() (merchantBalances, userBalances []MerchantWallet, err error) {
-	httpResponse, err := m.sendPaymentRequest("POST", urlGetBalanceForMerchant, nil)
+	return m.GetBalanceContext(context.Background())
+}
+
+// GetBalanceContext is like GetBalance but sends the request with the provided context.
+func (m *Merchant) GetBalanceContext(ctx context.Context) (merchantBalances, userBalances []MerchantWallet, err error) {
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlGetBalanceForMerchant, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -119,28 +123,66 @@ func (m *Merchant) GetBalance() (merchantBalances, userBalances []MerchantWallet
 				User     []MerchantWallet `json:"user"`
 			} `json:"balance"`
 		} `json:"result"`
-		Message string `json:"message"`
-		Code    int    `json:"code"`
-		Error   string `json:"error"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, nil, err
 	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
+
+	if len(response.Result) == 0 {
+		return nil, nil, nil
 	}
+	return response.Result[0].Balance.Merchant, response.Result[0].Balance.User, nil
+}
+
+// BalanceByCurrency is like GetBalance but returns the business and personal wallets keyed by their upper-case currency code, e.g. "USDT".
+func (m *Merchant) BalanceByCurrency() (merchant, user map[string]MerchantWallet, err error) {
+	return m.BalanceByCurrencyContext(context.Background())
+}
 
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+// BalanceByCurrencyContext is like BalanceByCurrency but sends the request with the provided context.
+func (m *Merchant) BalanceByCurrencyContext(ctx context.Context) (merchant, user map[string]MerchantWallet, err error) {
+	merchantBalances, userBalances, err := m.GetBalanceContext(ctx)
+	if err != nil {
+		return nil, nil, err
 	}
+	return walletsByCurrency(merchantBalances), walletsByCurrency(userBalances), nil
+}
 
-	return response.Result[0].Balance.Merchant, response.Result[0].Balance.User, nil
+func walletsByCurrency(wallets []MerchantWallet) map[string]MerchantWallet {
+	byCurrency := make(map[string]MerchantWallet, len(wallets))
+	for _, wallet := range wallets {
+		byCurrency[strings.ToUpper(wallet.CurrencyCode)] = wallet
+	}
+	return byCurrency
+}
+
+// AvailableBalance returns the balance of the business wallet in currency, matched case-insensitively, which is the balance payouts are made from.
+//
+// It returns an error if the merchant has no business wallet in currency.
+func (m *Merchant) AvailableBalance(currency string) (string, error) {
+	return m.AvailableBalanceContext(context.Background(), currency)
+}
+
+// AvailableBalanceContext is like AvailableBalance but sends the request with the provided context.
+func (m *Merchant) AvailableBalanceContext(ctx context.Context, currency string) (string, error) {
+	merchant, _, err := m.BalanceByCurrencyContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	wallet, ok := merchant[strings.ToUpper(currency)]
+	if !ok {
+		return "", fmt.Errorf("no business wallet in %s", currency)
+	}
+	return wallet.Balance, nil
 }
 
 // See "Get balance" https://doc.cryptomus.com/personal/converts/balance
@@ -168,34 +210,32 @@ func (m *Merchant) GetBalance() (merchantBalances, userBalances []MerchantWallet
 //		}
 //	  }
 func (u *User) GetBalance() ([]UserWallet, error) {
-	httpResponse, err := u.sendPaymentRequest("GET", urlGetBalanceForUser, nil)
+	return u.GetBalanceContext(context.Background())
+}
+
+// GetBalanceContext is like GetBalance but sends the request with the provided context.
+func (u *User) GetBalanceContext(ctx context.Context) ([]UserWallet, error) {
+	httpResponse, err := u.sendPaymentRequest(ctx, "GET", urlGetBalanceForUser, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer httpResponse.Body.Close()
 
 	var response = struct {
-		State   int          `json:"state"`
-		Result  []UserWallet `json:"result"`
-		Message string       `json:"message"`
-		Code    int          `json:"code"`
-		Error   string       `json:"error"`
+		State   int            `json:"state"`
+		Result  []UserWallet   `json:"result"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := u.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return response.Result, nil
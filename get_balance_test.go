@@ -0,0 +1,112 @@
+package cryptomus_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+const balanceFixture = `
+{
+    "state": 0,
+    "result": [
+        {
+            "balance": {
+                "merchant": [
+                    {
+                        "uuid": "abcdabcd-abcd-1234-1234-abcdabcd",
+                        "balance": "0.00000000",
+                        "currency_code": "ETH"
+                    },
+                    {
+                        "uuid": "abcdabcd-abcd-1234-1234-abcdabcd",
+                        "balance": "0.57000000",
+                        "currency_code": "BTC"
+                    },
+                    {
+                        "uuid": "abcdabcd-abcd-1234-1234-abcdabcd",
+                        "balance": "23.57327446",
+                        "currency_code": "TRX"
+                    },
+                    {
+                        "uuid": "abcdabcd-abcd-1234-1234-abcdabcd",
+                        "balance": "5.00000000",
+                        "currency_code": "USDT"
+                    },
+                    {
+                        "uuid": "abcdabcd-abcd-1234-1234-abcdabcd",
+                        "balance": "10.00120000",
+                        "currency_code": "DASH"
+                    },
+                    {
+                        "uuid": "abcdabcd-abcd-1234-1234-abcdabcd",
+                        "balance": "0.18500000",
+                        "currency_code": "LTC"
+                    }
+                ],
+                "user": [
+                    {
+                        "uuid": "abcdabcd-abcd-1234-1234-abcdabcd",
+                        "balance": "0.40000000",
+                        "currency_code": "BTC"
+                    },
+                    {
+                        "uuid": "abcdabcd-abcd-1234-1234-abcdabcd",
+                        "balance": "52.00000000",
+                        "currency_code": "USDT"
+                    },
+                    {
+                        "uuid": "abcdabcd-abcd-1234-1234-abcdabcd",
+                        "balance": "0.00000000",
+                        "currency_code": "DASH"
+                    },
+                    {
+                        "uuid": "abcdabcd-abcd-1234-1234-abcdabcd",
+                        "balance": "0.30000000",
+                        "currency_code": "LTC"
+                    },
+                    {
+                        "uuid": "abcdabcd-abcd-1234-1234-abcdabcd",
+                        "balance": "27.00000000",
+                        "currency_code": "TRX"
+                    },
+                    {
+                        "uuid": "abcdabcd-abcd-1234-1234-abcdabcd",
+                        "balance": "0.19000000",
+                        "currency_code": "ETH"
+                    }
+                ]
+            }
+        }
+    ]
+}`
+
+func TestBalanceByCurrency(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/balance" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(balanceFixture))
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	business, personal, err := merchant.BalanceByCurrency()
+	if err != nil {
+		t.Fatalf("error getting balance: %v", err)
+	}
+	if len(business) != 6 || len(personal) != 6 {
+		t.Fatalf("got %d business and %d personal wallets, want 6 and 6", len(business), len(personal))
+	}
+	if business["USDT"].Balance != "5.00000000" || personal["USDT"].Balance != "52.00000000" {
+		t.Errorf("got USDT balances %s and %s", business["USDT"].Balance, personal["USDT"].Balance)
+	}
+
+	balance, err := merchant.AvailableBalance("dash")
+	if err != nil || balance != "10.00120000" {
+		t.Errorf("got DASH balance %s, %v, want 10.00120000", balance, err)
+	}
+	if _, err := merchant.AvailableBalance("XMR"); err == nil {
+		t.Error("expected error for a currency without wallet")
+	}
+}
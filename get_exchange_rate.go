@@ -1,9 +1,12 @@
 package cryptomus
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
+	"net/url"
+	"strings"
 )
 
 // See "List" https://doc.cryptomus.com/business/exchange-rates/list
@@ -30,21 +33,116 @@ type ExchangeRate struct {
 //		  }
 //		]
 //	  }
+//
+// # Possible errors
+//
+// # Validation errors
+//
+// code : 422
+//
+//	{
+//		"state": 1,
+//		"message": "Currency not found"
+//	}
+//
+// # Internal server error
+//
+// code : 500
+//
+//	{
+//		"message": "Server error, #1",
+//		"code": 500,
+//		"error": null
+//	}
 func GetExchangeRate(currency string) ([]ExchangeRate, error) {
-	url := fmt.Sprintf(urlGetExchangeRate, currency)
-	resp, err := http.Get(url)
+	return GetExchangeRateContext(context.Background(), currency)
+}
+
+// GetExchangeRateContext is like GetExchangeRate but sends the request with the provided context.
+func GetExchangeRateContext(ctx context.Context, currency string) ([]ExchangeRate, error) {
+	requestURL := fmt.Sprintf(urlGetExchangeRate, url.PathEscape(currency))
+	response, err := sendPublicRequest(ctx, requestURL)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error sending GET request: %w", err)
 	}
-	defer resp.Body.Close()
+	defer response.Body.Close()
 
 	var responseStruct struct {
-		State  int            `json:"state"`
-		Result []ExchangeRate `json:"result"`
+		State   int            `json:"state"`
+		Result  []ExchangeRate `json:"result"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&responseStruct); err != nil {
+		return nil, fmt.Errorf("error decoding response payload: %w", err)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&responseStruct); err != nil {
+
+	if err := checkResponse(response, responseStruct.State, responseStruct.Code, responseStruct.Message, responseStruct.Error, responseStruct.Errors); err != nil {
 		return nil, err
 	}
 
 	return responseStruct.Result, nil
 }
+
+// ErrRateNotFound is returned by GetExchangeRateFor when Cryptomus has no rate for the pair.
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// GetExchangeRateFor is like GetExchangeRate but returns only the rate from one currency to another, e.g. BTC to USD. Currency codes are matched case-insensitively.
+//
+// The returned error wraps ErrRateNotFound if the pair is not in the list.
+//
+// See "List" https://doc.cryptomus.com/business/exchange-rates/list
+func GetExchangeRateFor(from, to string) (ExchangeRate, error) {
+	return GetExchangeRateForContext(context.Background(), from, to)
+}
+
+// GetExchangeRateForContext is like GetExchangeRateFor but sends the request with the provided context.
+func GetExchangeRateForContext(ctx context.Context, from, to string) (ExchangeRate, error) {
+	rates, err := GetExchangeRateContext(ctx, from)
+	if err != nil {
+		return ExchangeRate{}, err
+	}
+
+	for _, rate := range rates {
+		if strings.EqualFold(rate.To, to) {
+			return rate, nil
+		}
+	}
+
+	return ExchangeRate{}, fmt.Errorf("%w: %s to %s", ErrRateNotFound, from, to)
+}
+
+// exchangeRateConcurrency is the number of requests GetExchangeRates sends at a time.
+const exchangeRateConcurrency = 4
+
+// GetExchangeRates is like GetExchangeRate for every currency of currencies, e.g. a pricing table, sending up to 4 requests at a time. The rates are keyed by currency as given.
+//
+// A failed currency does not stop the others: GetExchangeRates returns the rates of every currency that succeeded along with an error joining the error of each currency that failed.
+//
+// See "List" https://doc.cryptomus.com/business/exchange-rates/list
+func GetExchangeRates(currencies []string) (map[string][]ExchangeRate, error) {
+	return GetExchangeRatesContext(context.Background(), currencies)
+}
+
+// GetExchangeRatesContext is like GetExchangeRates but sends the requests with the provided context.
+func GetExchangeRatesContext(ctx context.Context, currencies []string) (map[string][]ExchangeRate, error) {
+	results := make([][]ExchangeRate, len(currencies))
+	errs, _ := runBatch(ctx, len(currencies), exchangeRateConcurrency, func(i int) error {
+		var err error
+		results[i], err = GetExchangeRateContext(ctx, currencies[i])
+		return err
+	})
+
+	rates := make(map[string][]ExchangeRate, len(currencies))
+	var failed []error
+	for i, currency := range currencies {
+		if errs[i] != nil {
+			failed = append(failed, fmt.Errorf("error getting exchange rate of %s: %w", currency, errs[i]))
+			continue
+		}
+		rates[currency] = results[i]
+	}
+	return rates, errors.Join(failed...)
+}
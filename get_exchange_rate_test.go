@@ -0,0 +1,133 @@
+package cryptomus_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestGetExchangeRateValidationError(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"state": 1, "message": "Currency not found"}`))
+	})
+
+	rates, err := cryptomus.GetExchangeRate("XYZ")
+	if err == nil {
+		t.Fatalf("expected error, got rates %v", rates)
+	}
+	if !strings.Contains(err.Error(), "Currency not found") {
+		t.Errorf("error %q does not contain the server message", err)
+	}
+	if !strings.Contains(err.Error(), "422") {
+		t.Errorf("error %q does not contain the HTTP status", err)
+	}
+}
+
+func TestGetExchangeRateSuccess(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/exchange-rate/ETH/list" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"state": 0, "result": [{"from": "ETH", "to": "USD", "course": "1228.45000000"}]}`))
+	})
+
+	rates, err := cryptomus.GetExchangeRate("ETH")
+	if err != nil {
+		t.Fatalf("error getting exchange rate: %v", err)
+	}
+	if len(rates) != 1 || rates[0].Course != "1228.45000000" {
+		t.Errorf("unexpected rates %v", rates)
+	}
+}
+
+func TestGetExchangeRateEscapesCurrency(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.EscapedPath(), "/v1/exchange-rate/ETH%2F..%3Fx=1/list"; got != want {
+			t.Errorf("got path %s, want %s", got, want)
+		}
+		w.Write([]byte(`{"state": 0, "result": []}`))
+	})
+
+	if _, err := cryptomus.GetExchangeRate("ETH/..?x=1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetExchangeRateFor(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/exchange-rate/BTC/list" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"state": 0, "result": [{"from": "BTC", "to": "EUR", "course": "56241.20000000"}, {"from": "BTC", "to": "USD", "course": "61002.80000000"}]}`))
+	})
+
+	rate, err := cryptomus.GetExchangeRateFor("BTC", "usd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rate.To != "USD" || rate.Course != "61002.80000000" {
+		t.Errorf("got rate %+v, want BTC to USD", rate)
+	}
+
+	_, err = cryptomus.GetExchangeRateFor("BTC", "JPY")
+	if !errors.Is(err, cryptomus.ErrRateNotFound) {
+		t.Errorf("got error %v, want ErrRateNotFound", err)
+	}
+}
+
+func TestGetExchangeRates(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		currency := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/exchange-rate/"), "/list")
+		if currency == "XYZ" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"state": 1, "message": "Currency not found"}`))
+			return
+		}
+		fmt.Fprintf(w, `{"state": 0, "result": [{"from": %q, "to": "USD", "course": "1.5"}]}`, currency)
+	})
+
+	currencies := []string{"BTC", "ETH", "XYZ", "TRX", "LTC", "DOGE"}
+	rates, err := cryptomus.GetExchangeRates(currencies)
+
+	if err == nil || !strings.Contains(err.Error(), "XYZ") || !strings.Contains(err.Error(), "Currency not found") {
+		t.Errorf("got error %v, want the error of XYZ", err)
+	}
+	var apiError *cryptomus.APIError
+	if !errors.As(err, &apiError) {
+		t.Errorf("got error %v, want it to wrap the APIError", err)
+	}
+	if len(rates) != len(currencies)-1 {
+		t.Errorf("got rates of %d currencies, want %d", len(rates), len(currencies)-1)
+	}
+	for _, currency := range currencies {
+		if currency == "XYZ" {
+			continue
+		}
+		if len(rates[currency]) != 1 || rates[currency][0].From != currency {
+			t.Errorf("%s: got rates %+v", currency, rates[currency])
+		}
+	}
+	if maxInFlight > 4 {
+		t.Errorf("got %d requests in flight, want at most 4", maxInFlight)
+	}
+}
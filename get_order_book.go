@@ -1,9 +1,12 @@
 package cryptomus
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"math/big"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,15 +18,91 @@ type Order struct {
 	Quantity string `json:"quantity"`
 }
 
-// Available options for level of volume: 0, 1, 2, 3, 4, 5
+// OrderBook holds the bids and asks of a currency pair at a point in time.
+//
+// See "Get order book" https://doc.cryptomus.com/personal/market-cap/orderbook
+type OrderBook struct {
+	Timestamp time.Time
+	Bids      []Order
+	Asks      []Order
+}
+
+// LevelCumulative is a price level of an order book with the running totals of all levels up to and including it, as plotted on a depth chart.
+type LevelCumulative struct {
+	Price    string
+	Quantity string
+	// Sum of the quantities from the best price to this level
+	CumulativeQuantity *big.Rat
+	// Sum of price * quantity from the best price to this level, in the quote currency
+	CumulativeVolume *big.Rat
+}
+
+// CumulativeBids returns the bids from the highest price down with their running totals, computed exactly with decimal math.
+//
+// Levels with a malformed price or quantity are left out.
+func (b OrderBook) CumulativeBids() []LevelCumulative {
+	return cumulate(b.Bids, func(x, y *big.Rat) bool { return x.Cmp(y) > 0 })
+}
+
+// CumulativeAsks returns the asks from the lowest price up with their running totals, computed exactly with decimal math.
+//
+// Levels with a malformed price or quantity are left out.
+func (b OrderBook) CumulativeAsks() []LevelCumulative {
+	return cumulate(b.Asks, func(x, y *big.Rat) bool { return x.Cmp(y) < 0 })
+}
+
+// cumulate sorts orders so that better comes first and sums them up level by level.
+func cumulate(orders []Order, better func(x, y *big.Rat) bool) []LevelCumulative {
+	type level struct {
+		order           Order
+		price, quantity *big.Rat
+	}
+
+	levels := make([]level, 0, len(orders))
+	for _, order := range orders {
+		price, err := parseDecimal(order.Price)
+		if err != nil {
+			continue
+		}
+		quantity, err := parseDecimal(order.Quantity)
+		if err != nil {
+			continue
+		}
+		levels = append(levels, level{order, price, quantity})
+	}
+	sort.SliceStable(levels, func(i, j int) bool { return better(levels[i].price, levels[j].price) })
+
+	cumulative := make([]LevelCumulative, len(levels))
+	totalQuantity, totalVolume := new(big.Rat), new(big.Rat)
+	for i, l := range levels {
+		totalQuantity.Add(totalQuantity, l.quantity)
+		totalVolume.Add(totalVolume, new(big.Rat).Mul(l.price, l.quantity))
+		cumulative[i] = LevelCumulative{
+			Price:              l.order.Price,
+			Quantity:           l.order.Quantity,
+			CumulativeQuantity: new(big.Rat).Set(totalQuantity),
+			CumulativeVolume:   new(big.Rat).Set(totalVolume),
+		}
+	}
+	return cumulative
+}
+
+// minOrderBookLevel and maxOrderBookLevel bound the level of volume of an order book.
+const (
+	minOrderBookLevel = 0
+	maxOrderBookLevel = 5
+)
+
+// GetOrderBookSnapshot returns the order book of a currency pair, e.g. "BTC_USDT", at the given level of volume.
+//
+// Available options for level of volume: 0, 1, 2, 3, 4, 5. Other levels are rejected without sending a request.
 //
 // See "Get order book" https://doc.cryptomus.com/personal/market-cap/orderbook
 //
 // # Response example
 //
 //	{
-//		"state": 0,
-//		"result": {
+//		"data": {
 //		  "timestamp": "1724069797.1308",
 //		  "bids": [
 //			{
@@ -47,12 +126,23 @@ type Order struct {
 //		  ]
 //		}
 //	  }
-func GetOrderBook(currencyPair string, level int) (timestamp time.Time, bids, asks []Order, err error) {
-	url := fmt.Sprintf(urlGetOrderBook+"?level=%d", currencyPair, level)
+func GetOrderBookSnapshot(currencyPair string, level int) (*OrderBook, error) {
+	return GetOrderBookSnapshotContext(context.Background(), currencyPair, level)
+}
 
-	response, err := http.Get(url)
+// GetOrderBookSnapshotContext is like GetOrderBookSnapshot but sends the request with the provided context.
+func GetOrderBookSnapshotContext(ctx context.Context, currencyPair string, level int) (*OrderBook, error) {
+	if level < minOrderBookLevel || level > maxOrderBookLevel {
+		return nil, fmt.Errorf("invalid order book level %d: must be between %d and %d", level, minOrderBookLevel, maxOrderBookLevel)
+	}
+
+	query := url.Values{}
+	query.Set("level", strconv.Itoa(level))
+	requestURL := fmt.Sprintf(urlGetOrderBook, url.PathEscape(currencyPair)) + "?" + query.Encode()
+
+	response, err := sendPublicRequest(ctx, requestURL)
 	if err != nil {
-		return time.Time{}, nil, nil, fmt.Errorf("error sending GET request: %w", err)
+		return nil, fmt.Errorf("error sending GET request: %w", err)
 	}
 	defer response.Body.Close()
 
@@ -62,43 +152,47 @@ func GetOrderBook(currencyPair string, level int) (timestamp time.Time, bids, as
 			Bids      []Order `json:"bids"`
 			Asks      []Order `json:"asks"`
 		} `json:"data"`
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-		Errors  []struct {
-			Property string `json:"property"`
-			Value    string `json:"value"`
-			Message  string `json:"message"`
-		} `json:"errors"`
-		Error string `json:"error"`
+		Code    int            `json:"code"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Error   string         `json:"error"`
 	}
 	if err := json.NewDecoder(response.Body).Decode(&responseStruct); err != nil {
-		return time.Time{}, nil, nil, fmt.Errorf("error decoding response payload: %w", err)
+		return nil, fmt.Errorf("error decoding response payload: %w", err)
 	}
 
-	var errs []string
-	if responseStruct.Message != "" {
-		errs = append(errs, responseStruct.Message)
-	}
-	if len(responseStruct.Errors) > 0 {
-		for _, err := range responseStruct.Errors {
-			errString := fmt.Sprintf("property: %s, value: %s, message: %s", err.Property, err.Value, err.Message)
-			errs = append(errs, errString)
-		}
-	}
-	if responseStruct.Error != "" {
-		errs = append(errs, responseStruct.Error)
+	if err := checkResponse(response, 0, responseStruct.Code, responseStruct.Message, responseStruct.Error, responseStruct.Errors); err != nil {
+		return nil, err
 	}
 
-	if response.StatusCode != http.StatusOK || len(errs) > 0 {
-		return time.Time{}, nil, nil, fmt.Errorf("error with status %s: %s", response.Status, strings.Join(errs, "; "))
+	timestamp, err := parseUnixTimeString(responseStruct.Data.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("error converting timestamp: %w", err)
 	}
 
-	timestamp, err = parseUnixTimeString(responseStruct.Data.Timestamp)
+	return &OrderBook{
+		Timestamp: timestamp,
+		Bids:      responseStruct.Data.Bids,
+		Asks:      responseStruct.Data.Asks,
+	}, nil
+}
+
+// GetOrderBook is like GetOrderBookSnapshot but returns the fields of the order book separately.
+//
+// Deprecated: Use GetOrderBookSnapshot.
+func GetOrderBook(currencyPair string, level int) (timestamp time.Time, bids, asks []Order, err error) {
+	return GetOrderBookContext(context.Background(), currencyPair, level)
+}
+
+// GetOrderBookContext is like GetOrderBook but sends the request with the provided context.
+//
+// Deprecated: Use GetOrderBookSnapshotContext.
+func GetOrderBookContext(ctx context.Context, currencyPair string, level int) (timestamp time.Time, bids, asks []Order, err error) {
+	book, err := GetOrderBookSnapshotContext(ctx, currencyPair, level)
 	if err != nil {
-		return time.Time{}, nil, nil, fmt.Errorf("error converting timestamp: %w", err)
+		return time.Time{}, nil, nil, err
 	}
-
-	return timestamp, responseStruct.Data.Bids, responseStruct.Data.Asks, nil
+	return book.Timestamp, book.Bids, book.Asks, nil
 }
 
 func parseUnixTimeString(unixDecimal string) (time.Time, error) {
@@ -111,7 +205,11 @@ func parseUnixTimeString(unixDecimal string) (time.Time, error) {
 
 	var nanoseconds int64
 	if len(parts) == 2 {
-		fractionalPart := parts[1] + strings.Repeat("0", 9-len(parts[1]))
+		fractionalPart := parts[1]
+		if len(fractionalPart) > 9 {
+			fractionalPart = fractionalPart[:9]
+		}
+		fractionalPart += strings.Repeat("0", 9-len(fractionalPart))
 		nanoseconds, err = strconv.ParseInt(fractionalPart, 10, 64)
 		if err != nil {
 			return time.Time{}, fmt.Errorf("error parsing nanoseconds: %w", err)
@@ -0,0 +1,97 @@
+package cryptomus_test
+
+import (
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestGetOrderBookURL(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/exchange/market/order-book/BTC_USDT" {
+			t.Errorf("expected currency pair in path, got %s", r.URL.Path)
+		}
+		if level := r.URL.Query().Get("level"); level != "3" {
+			t.Errorf("expected level 3 in query, got %q", level)
+		}
+		w.Write([]byte(`{"data": {"timestamp": "1724069797.1308", "bids": [], "asks": []}}`))
+	})
+
+	if _, _, _, err := cryptomus.GetOrderBook("BTC_USDT", 3); err != nil {
+		t.Fatalf("error getting order book: %v", err)
+	}
+}
+
+func TestGetOrderBookSnapshot(t *testing.T) {
+	requests := 0
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"state": 0, "data": {"timestamp": "1724069797.1308", "bids": [{"price": "0.04548320", "quantity": "12462000"}, {"price": "3.00000000", "quantity": "12457000"}], "asks": [{"price": "2.73042000", "quantity": "12506000"}]}}`))
+	})
+
+	for _, level := range []int{-1, 6} {
+		if _, err := cryptomus.GetOrderBookSnapshot("BTC_USDT", level); err == nil || !strings.Contains(err.Error(), "level") {
+			t.Errorf("level %d: got error %v, want an invalid level error", level, err)
+		}
+	}
+	if requests != 0 {
+		t.Fatalf("got %d requests for invalid levels, want 0", requests)
+	}
+
+	book, err := cryptomus.GetOrderBookSnapshot("BTC_USDT", 0)
+	if err != nil {
+		t.Fatalf("error getting order book: %v", err)
+	}
+	if want := time.Unix(1724069797, 130800000); !book.Timestamp.Equal(want) {
+		t.Errorf("got timestamp %v, want %v", book.Timestamp, want)
+	}
+	if len(book.Bids) != 2 || book.Bids[1].Price != "3.00000000" || len(book.Asks) != 1 || book.Asks[0].Quantity != "12506000" {
+		t.Errorf("unexpected order book %+v", book)
+	}
+}
+
+func TestOrderBookCumulative(t *testing.T) {
+	book := cryptomus.OrderBook{
+		Bids: []cryptomus.Order{
+			{Price: "0.04548320", Quantity: "12462000"},
+			{Price: "3.00000000", Quantity: "12457000"},
+			{Price: "bad", Quantity: "1"},
+		},
+		Asks: []cryptomus.Order{
+			{Price: "2.73042000", Quantity: "12506000"},
+			{Price: "0.33660000", Quantity: "0.00000001"},
+		},
+	}
+
+	type level struct {
+		price, quantity, volume string
+	}
+	check := func(name string, got []cryptomus.LevelCumulative, want []level) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s: got %d levels, want %d", name, len(got), len(want))
+		}
+		for i, w := range want {
+			quantity, _ := new(big.Rat).SetString(w.quantity)
+			volume, _ := new(big.Rat).SetString(w.volume)
+			if got[i].Price != w.price || got[i].CumulativeQuantity.Cmp(quantity) != 0 || got[i].CumulativeVolume.Cmp(volume) != 0 {
+				t.Errorf("%s level %d: got %s %s %s, want %s %s %s", name, i, got[i].Price, got[i].CumulativeQuantity.FloatString(8), got[i].CumulativeVolume.FloatString(8), w.price, w.quantity, w.volume)
+			}
+		}
+	}
+
+	// Bids from the highest price: 3 * 12457000 = 37371000, then + 0.0454832 * 12462000 = 566811.6384
+	check("bids", book.CumulativeBids(), []level{
+		{"3.00000000", "12457000", "37371000"},
+		{"0.04548320", "24919000", "37937811.6384"},
+	})
+	// Asks from the lowest price: 0.3366 * 0.00000001 = 0.000000003366 is kept exactly
+	check("asks", book.CumulativeAsks(), []level{
+		{"0.33660000", "0.00000001", "0.000000003366"},
+		{"2.73042000", "12506000.00000001", "34146632.520000003366"},
+	})
+}
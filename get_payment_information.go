@@ -1,10 +1,9 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strings"
+	"time"
 )
 
 // PaymentInformation retrieves payment information based on either UUID or Order ID.
@@ -56,41 +55,105 @@ import (
 //		}
 //	}
 func (m *Merchant) GetPaymentInformation(request RecordID) (*Payment, error) {
-	httpResponse, err := m.sendPaymentRequest("POST", urlGetPaymentInformation, request)
+	return m.GetPaymentInformationContext(context.Background(), request)
+}
+
+// GetPaymentInformationContext is like GetPaymentInformation but sends the request with the provided context.
+func (m *Merchant) GetPaymentInformationContext(ctx context.Context, request RecordID) (*Payment, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating request: %w", err)
+	}
+
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlGetPaymentInformation, request)
 	if err != nil {
 		return nil, err
 	}
 	defer httpResponse.Body.Close()
 
 	var response = struct {
-		State   int     `json:"state"`
-		Result  Payment `json:"result"`
-		Message string  `json:"message"`
-		Errors  struct {
-			UUID    []string `json:"uuid"`
-			OrderID []string `json:"order_id"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		State   int            `json:"state"`
+		Result  Payment        `json:"result"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
+
+	return &response.Result, nil
+}
+
+// maxWaitFailures is the number of consecutive failed polls after which WaitForPayment, WaitForPayout and WaitForRate give up.
+const maxWaitFailures = 3
+
+// WaitForPayment polls GetPaymentInformation every pollInterval until the payment is final, then returns it.
+//
+// It returns early with the error of ctx once ctx is done, so set a deadline, e.g. the lifetime of the invoice.
+// Server and network errors are retried at the next poll; other API errors, e.g. a payment that is not found, and three failures in a row end the wait.
+//
+// Prefer webhooks where you can receive them; polling is meant for scripts and services that cannot.
+func (m *Merchant) WaitForPayment(ctx context.Context, id RecordID, pollInterval time.Duration) (*Payment, error) {
+	if pollInterval <= 0 {
+		return nil, fmt.Errorf("poll interval must be positive, got %s", pollInterval)
 	}
-	errs = append(errs, response.Errors.UUID...)
-	errs = append(errs, response.Errors.OrderID...)
+	if err := id.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating request: %w", err)
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		payment, err := m.GetPaymentInformationContext(ctx, id)
+		switch {
+		case err == nil:
+			failures = 0
+			if payment.IsFinal {
+				return payment, nil
+			}
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case isAPIError(err) && !IsServerError(err):
+			return nil, fmt.Errorf("error waiting for payment: %w", err)
+		default:
+			failures++
+			if failures == maxWaitFailures {
+				return nil, fmt.Errorf("error waiting for payment, %d polls failed in a row: %w", failures, err)
+			}
+		}
 
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
 	}
+}
 
-	return &response.Result, nil
+// GetPaymentInformationByUUID is like GetPaymentInformation for the invoice with the given uuid.
+func (m *Merchant) GetPaymentInformationByUUID(uuid string) (*Payment, error) {
+	return m.GetPaymentInformationContext(context.Background(), ByUUID(uuid))
+}
+
+// GetPaymentInformationByUUIDContext is like GetPaymentInformationByUUID but sends the request with the provided context.
+func (m *Merchant) GetPaymentInformationByUUIDContext(ctx context.Context, uuid string) (*Payment, error) {
+	return m.GetPaymentInformationContext(ctx, ByUUID(uuid))
+}
+
+// GetPaymentInformationByOrderID is like GetPaymentInformation for the invoice with the given order_id.
+func (m *Merchant) GetPaymentInformationByOrderID(orderID string) (*Payment, error) {
+	return m.GetPaymentInformationContext(context.Background(), ByOrderID(orderID))
+}
+
+// GetPaymentInformationByOrderIDContext is like GetPaymentInformationByOrderID but sends the request with the provided context.
+func (m *Merchant) GetPaymentInformationByOrderIDContext(ctx context.Context, orderID string) (*Payment, error) {
+	return m.GetPaymentInformationContext(ctx, ByOrderID(orderID))
 }
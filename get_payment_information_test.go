@@ -0,0 +1,137 @@
+package cryptomus_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestWaitForPayment(t *testing.T) {
+	polls := 0
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		switch polls {
+		case 1:
+			w.Write([]byte(`{"state": 0, "result": {"uuid": "70b8db5c", "payment_status": "check", "is_final": false}}`))
+		case 2:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message": "Server error, #1", "code": 500}`))
+		default:
+			w.Write([]byte(`{"state": 0, "result": {"uuid": "70b8db5c", "payment_status": "paid", "is_final": true}}`))
+		}
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	uuid := "70b8db5c"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	payment, err := merchant.WaitForPayment(ctx, cryptomus.RecordID{UUID: &uuid}, time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payment.PaymentStatus != cryptomus.StatusPaid || polls != 3 {
+		t.Errorf("got status %s after %d polls, want paid after 3", payment.PaymentStatus, polls)
+	}
+}
+
+func TestWaitForPaymentErrors(t *testing.T) {
+	uuid := "70b8db5c"
+
+	t.Run("cancelled", func(t *testing.T) {
+		newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"state": 0, "result": {"uuid": "70b8db5c", "payment_status": "check", "is_final": false}}`))
+		})
+		merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		if _, err := merchant.WaitForPayment(ctx, cryptomus.RecordID{UUID: &uuid}, time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got error %v, want context.DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("repeated server errors", func(t *testing.T) {
+		polls := 0
+		newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			polls++
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(`{"message": "Server error, #1", "code": 500}`))
+		})
+		merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+
+		_, err := merchant.WaitForPayment(context.Background(), cryptomus.RecordID{UUID: &uuid}, time.Millisecond)
+		if !cryptomus.IsServerError(err) || polls != 3 {
+			t.Errorf("got error %v after %d polls, want a server error after 3", err, polls)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		polls := 0
+		newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+			polls++
+			w.Write([]byte(`{"state": 1, "message": "Payment not found"}`))
+		})
+		merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+
+		_, err := merchant.WaitForPayment(context.Background(), cryptomus.RecordID{UUID: &uuid}, time.Millisecond)
+		if !cryptomus.IsNotFound(err) || polls != 1 {
+			t.Errorf("got error %v after %d polls, want not found after 1", err, polls)
+		}
+	})
+}
+
+func TestGetInformationByUUIDAndOrderID(t *testing.T) {
+	bodies := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies[r.URL.Path] = string(body)
+		w.Write([]byte(`{"state": 0, "result": {"uuid": "26109ba0-b05b-4ee0-93d1-fd62c822ce95"}}`))
+	}))
+	defer server.Close()
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+
+	tests := []struct {
+		name, path, want string
+		get              func() error
+	}{
+		{"payment by uuid", "/v1/payment/info", `{"uuid":"26109ba0"}`, func() error {
+			_, err := merchant.GetPaymentInformationByUUID("26109ba0")
+			return err
+		}},
+		{"payment by order id", "/v1/payment/info", `{"order_id":"1"}`, func() error {
+			_, err := merchant.GetPaymentInformationByOrderID("1")
+			return err
+		}},
+		{"payout by uuid", "/v1/payout/info", `{"uuid":"26109ba0"}`, func() error {
+			_, err := merchant.GetPayoutInformationByUUID("26109ba0")
+			return err
+		}},
+		{"payout by order id", "/v1/payout/info", `{"order_id":"1"}`, func() error {
+			_, err := merchant.GetPayoutInformationByOrderID("1")
+			return err
+		}},
+		{"recurring payment by uuid", "/v1/recurrence/info", `{"uuid":"26109ba0"}`, func() error {
+			_, err := merchant.GetRecurringPaymentInformationByUUID("26109ba0")
+			return err
+		}},
+		{"recurring payment by order id", "/v1/recurrence/info", `{"order_id":"1"}`, func() error {
+			_, err := merchant.GetRecurringPaymentInformationByOrderID("1")
+			return err
+		}},
+	}
+	for _, test := range tests {
+		if err := test.get(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if got := bodies[test.path]; got != test.want {
+			t.Errorf("%s: sent %s, want %s", test.name, got, test.want)
+		}
+	}
+}
@@ -1,10 +1,10 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"net/http"
-	"strings"
+	"time"
 )
 
 // See "Payout information" https://doc.cryptomus.com/business/payouts/payout-information
@@ -45,42 +45,114 @@ import (
 //			"order_id": ["validation.required_without"]
 //		}
 //	}
-func (m *Merchant) GetPayoutInformation(request RecordID) (*Payment, error) {
-	httpResponse, err := m.sendPayoutRequest("POST", urlGetPayoutInformation, request)
+func (m *Merchant) GetPayoutInformation(request RecordID) (*Payout, error) {
+	return m.GetPayoutInformationContext(context.Background(), request)
+}
+
+// GetPayoutInformationContext is like GetPayoutInformation but sends the request with the provided context.
+func (m *Merchant) GetPayoutInformationContext(ctx context.Context, request RecordID) (*Payout, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating request: %w", err)
+	}
+
+	httpResponse, err := m.sendPayoutRequest(ctx, "POST", urlGetPayoutInformation, request)
 	if err != nil {
 		return nil, err
 	}
 	defer httpResponse.Body.Close()
 
 	var response = struct {
-		State   int     `json:"state"`
-		Result  Payment `json:"result"`
-		Message string  `json:"message"`
-		Errors  struct {
-			UUID    []string `json:"uuid"`
-			OrderID []string `json:"order_id"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		State   int            `json:"state"`
+		Result  Payout         `json:"result"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
+
+	return &response.Result, nil
+}
+
+// ErrPayoutFailed is returned by WaitForPayout, together with the payout, when the payout is final but not paid, e.g. it failed or was cancelled.
+var ErrPayoutFailed = errors.New("payout failed")
+
+// maxPayoutPollDoublings is the number of times WaitForPayout doubles its poll interval, so it polls at most every 8 poll intervals.
+const maxPayoutPollDoublings = 3
+
+// WaitForPayout polls GetPayoutInformation until the payout is final, then returns it.
+//
+// The first poll is immediate. The delay before the second is a random duration between half of pollInterval and pollInterval, so that many waiting payouts do not poll in step, and it doubles while the payout is not final, up to between 4 and 8 times pollInterval.
+//
+// The payout moves from process and check to its final status. If it is paid, WaitForPayout returns it with a nil error; otherwise, e.g. if it failed and the funds went back to the balance, it returns the payout and an error wrapping ErrPayoutFailed.
+//
+// It returns early with the error of ctx once ctx is done. Server and network errors are retried at the next poll; other API errors and three failures in a row end the wait.
+//
+// Prefer webhooks where you can receive them; polling is meant for scripts and services that cannot.
+func (m *Merchant) WaitForPayout(ctx context.Context, id RecordID, pollInterval time.Duration) (*Payout, error) {
+	if pollInterval <= 0 {
+		return nil, fmt.Errorf("poll interval must be positive, got %s", pollInterval)
+	}
+	if err := id.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating request: %w", err)
 	}
-	errs = append(errs, response.Errors.UUID...)
-	errs = append(errs, response.Errors.OrderID...)
 
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	failures := 0
+	for poll := 1; ; poll++ {
+		payout, err := m.GetPayoutInformationContext(ctx, id)
+		switch {
+		case err == nil:
+			failures = 0
+			if payout.IsFinal {
+				if !payout.Status.IsSuccess() {
+					return payout, fmt.Errorf("%w: payout %s is %s", ErrPayoutFailed, payout.UUID, payout.Status)
+				}
+				return payout, nil
+			}
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case isAPIError(err) && !IsServerError(err):
+			return nil, fmt.Errorf("error waiting for payout: %w", err)
+		default:
+			failures++
+			if failures == maxWaitFailures {
+				return nil, fmt.Errorf("error waiting for payout, %d polls failed in a row: %w", failures, err)
+			}
+		}
+
+		timer := time.NewTimer(backoff(pollInterval, min(poll, maxPayoutPollDoublings+1)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
 	}
+}
 
-	return &response.Result, nil
+// GetPayoutInformationByUUID is like GetPayoutInformation for the payout with the given uuid.
+func (m *Merchant) GetPayoutInformationByUUID(uuid string) (*Payout, error) {
+	return m.GetPayoutInformationContext(context.Background(), ByUUID(uuid))
+}
+
+// GetPayoutInformationByUUIDContext is like GetPayoutInformationByUUID but sends the request with the provided context.
+func (m *Merchant) GetPayoutInformationByUUIDContext(ctx context.Context, uuid string) (*Payout, error) {
+	return m.GetPayoutInformationContext(ctx, ByUUID(uuid))
+}
+
+// GetPayoutInformationByOrderID is like GetPayoutInformation for the payout with the given order_id.
+func (m *Merchant) GetPayoutInformationByOrderID(orderID string) (*Payout, error) {
+	return m.GetPayoutInformationContext(context.Background(), ByOrderID(orderID))
+}
+
+// GetPayoutInformationByOrderIDContext is like GetPayoutInformationByOrderID but sends the request with the provided context.
+func (m *Merchant) GetPayoutInformationByOrderIDContext(ctx context.Context, orderID string) (*Payout, error) {
+	return m.GetPayoutInformationContext(ctx, ByOrderID(orderID))
 }
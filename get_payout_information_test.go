@@ -0,0 +1,93 @@
+package cryptomus_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestGetPayoutInformation(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/payout/info" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"state": 0, "result": ` + docPayoutResult + `}`))
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	payout, err := merchant.GetPayoutInformationByUUID("a7c0caec-a594-4aaa-b1c4-77d511857594")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payout.UUID != "a7c0caec-a594-4aaa-b1c4-77d511857594" || payout.Amount != "3" || payout.Network != "TRON" || payout.Address != "TJ..." {
+		t.Errorf("got uuid %q, amount %q, network %q, address %q", payout.UUID, payout.Amount, payout.Network, payout.Address)
+	}
+	if payout.Status != cryptomus.PayoutStatusProcess || payout.IsFinal || payout.TxID != nil {
+		t.Errorf("got status %q, is_final %v, txid %v", payout.Status, payout.IsFinal, payout.TxID)
+	}
+	if payout.Balance != "129" || payout.PayerCurrency != "USD" || payout.PayerAmount != "3" {
+		t.Errorf("got balance %q, payer_currency %q, payer_amount %q", payout.Balance, payout.PayerCurrency, payout.PayerAmount)
+	}
+}
+
+func TestWaitForPayout(t *testing.T) {
+	uuid := "a7c0caec"
+
+	tests := []struct {
+		name    string
+		final   string
+		status  cryptomus.PayoutStatus
+		wantErr error
+	}{
+		{"paid", `{"state": 0, "result": {"uuid": "a7c0caec", "status": "paid", "is_final": true}}`, cryptomus.PayoutStatusPaid, nil},
+		{"fail", `{"state": 0, "result": {"uuid": "a7c0caec", "status": "fail", "is_final": true}}`, cryptomus.PayoutStatusFail, cryptomus.ErrPayoutFailed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			polls := 0
+			newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				polls++
+				switch polls {
+				case 1:
+					w.Write([]byte(`{"state": 0, "result": {"uuid": "a7c0caec", "status": "process", "is_final": false}}`))
+				case 2:
+					w.WriteHeader(http.StatusInternalServerError)
+					w.Write([]byte(`{"message": "Server error, #1", "code": 500}`))
+				case 3:
+					w.Write([]byte(`{"state": 0, "result": {"uuid": "a7c0caec", "status": "check", "is_final": false}}`))
+				default:
+					w.Write([]byte(test.final))
+				}
+			})
+
+			merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			payout, err := merchant.WaitForPayout(ctx, cryptomus.RecordID{UUID: &uuid}, time.Millisecond)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			if payout == nil || payout.Status != test.status || polls != 4 {
+				t.Errorf("got payout %+v after %d polls, want %s after 4", payout, polls, test.status)
+			}
+		})
+	}
+}
+
+func TestWaitForPayoutCancelled(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"state": 0, "result": {"uuid": "a7c0caec", "status": "process", "is_final": false}}`))
+	})
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := merchant.WaitForPayout(ctx, cryptomus.ByUUID("a7c0caec"), time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want context.DeadlineExceeded", err)
+	}
+}
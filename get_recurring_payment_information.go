@@ -1,10 +1,8 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strings"
 )
 
 // To get the recurring payment status you need to pass one of the required parameters, if you pass both, the account will be identified by order_id
@@ -50,7 +48,16 @@ import (
 //		}
 //	}
 func (m *Merchant) GetRecurringPaymentInformation(request RecordID) (*RecurringPayment, error) {
-	httpResponse, err := m.sendPaymentRequest("POST", urlGetRecurringPaymentInformation, request)
+	return m.GetRecurringPaymentInformationContext(context.Background(), request)
+}
+
+// GetRecurringPaymentInformationContext is like GetRecurringPaymentInformation but sends the request with the provided context.
+func (m *Merchant) GetRecurringPaymentInformationContext(ctx context.Context, request RecordID) (*RecurringPayment, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating request: %w", err)
+	}
+
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlGetRecurringPaymentInformation, request)
 	if err != nil {
 		return nil, err
 	}
@@ -60,31 +67,38 @@ func (m *Merchant) GetRecurringPaymentInformation(request RecordID) (*RecurringP
 		State   int              `json:"state"`
 		Result  RecurringPayment `json:"result"`
 		Message string           `json:"message"`
-		Errors  struct {
-			UUID    []string `json:"uuid"`
-			OrderID []string `json:"order_id"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		Errors  responseErrors   `json:"errors"`
+		Code    int              `json:"code"`
+		Error   string           `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.UUID...)
-	errs = append(errs, response.Errors.OrderID...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
 }
+
+// GetRecurringPaymentInformationByUUID is like GetRecurringPaymentInformation for the recurring payment with the given uuid.
+func (m *Merchant) GetRecurringPaymentInformationByUUID(uuid string) (*RecurringPayment, error) {
+	return m.GetRecurringPaymentInformationContext(context.Background(), ByUUID(uuid))
+}
+
+// GetRecurringPaymentInformationByUUIDContext is like GetRecurringPaymentInformationByUUID but sends the request with the provided context.
+func (m *Merchant) GetRecurringPaymentInformationByUUIDContext(ctx context.Context, uuid string) (*RecurringPayment, error) {
+	return m.GetRecurringPaymentInformationContext(ctx, ByUUID(uuid))
+}
+
+// GetRecurringPaymentInformationByOrderID is like GetRecurringPaymentInformation for the recurring payment with the given order_id.
+func (m *Merchant) GetRecurringPaymentInformationByOrderID(orderID string) (*RecurringPayment, error) {
+	return m.GetRecurringPaymentInformationContext(context.Background(), ByOrderID(orderID))
+}
+
+// GetRecurringPaymentInformationByOrderIDContext is like GetRecurringPaymentInformationByOrderID but sends the request with the provided context.
+func (m *Merchant) GetRecurringPaymentInformationByOrderIDContext(ctx context.Context, orderID string) (*RecurringPayment, error) {
+	return m.GetRecurringPaymentInformationContext(ctx, ByOrderID(orderID))
+}
@@ -1,29 +1,56 @@
 package cryptomus
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"math/big"
+	"time"
 )
 
 // See "Get trades" https://doc.cryptomus.com/personal/market-cap/trades
 type Trade struct {
 	// Trade id
 	TradeID string `json:"trade_id"`
-	// Trade price
-	Price string `json:"price"`
-	// Trade base volume
-	BaseVolume string `json:"base_volume"`
+	// Trade price, null for some trades
+	Price *string `json:"price"`
+	// Trade base volume, null for some trades
+	BaseVolume *string `json:"base_volume"`
 	// Trade quote volume
 	QuoteVolume string `json:"quote_volume"`
-	// Time
+	// Time in unix seconds
 	Timestamp int `json:"timestamp"`
 	// Direction type
 	//
 	// Available options:
 	//  - sell
 	//  - buy
-	Type string `json:"type"`
+	Type TradeType `json:"type"`
+}
+
+// TradeType is the direction of a trade.
+//
+// See "Get trades" https://doc.cryptomus.com/personal/market-cap/trades
+type TradeType string
+
+const (
+	TradeBuy  TradeType = "buy"
+	TradeSell TradeType = "sell"
+)
+
+// Time returns the timestamp of the trade as a time.Time.
+func (t Trade) Time() time.Time {
+	return time.Unix(int64(t.Timestamp), 0)
+}
+
+// PriceDecimal returns price as an exact decimal, or nil if it is null.
+func (t Trade) PriceDecimal() (*big.Rat, error) {
+	return parseNullableDecimal(t.Price)
+}
+
+// BaseVolumeDecimal returns base_volume as an exact decimal, or nil if it is null.
+func (t Trade) BaseVolumeDecimal() (*big.Rat, error) {
+	return parseNullableDecimal(t.BaseVolume)
 }
 
 // See "Get trades" https://doc.cryptomus.com/personal/market-cap/trades
@@ -52,26 +79,33 @@ type Trade struct {
 //	  ]
 //	}
 func GetTrades(currencyPair string) ([]Trade, error) {
+	return GetTradesContext(context.Background(), currencyPair)
+}
+
+// GetTradesContext is like GetTrades but sends the request with the provided context.
+func GetTradesContext(ctx context.Context, currencyPair string) ([]Trade, error) {
 	url := fmt.Sprintf(urlGetTrades, currencyPair)
 
-	response, err := http.Get(url)
+	response, err := sendPublicRequest(ctx, url)
 	if err != nil {
 		return nil, fmt.Errorf("error sending GET request: %w", err)
 	}
 	defer response.Body.Close()
 
 	var responseStruct struct {
-		Data    []Trade `json:"data"`
-		Code    int     `json:"code"`
-		Message string  `json:"message"`
+		Data    []Trade        `json:"data"`
+		Code    int            `json:"code"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Error   string         `json:"error"`
 	}
 
 	if err := json.NewDecoder(response.Body).Decode(&responseStruct); err != nil {
 		return nil, fmt.Errorf("error decoding response payload: %w", err)
 	}
 
-	if response.StatusCode != http.StatusOK || responseStruct.Message != "" {
-		return nil, fmt.Errorf("error with status %s: %s", response.Status, responseStruct.Message)
+	if err := checkResponse(response, 0, responseStruct.Code, responseStruct.Message, responseStruct.Error, responseStruct.Errors); err != nil {
+		return nil, err
 	}
 
 	return responseStruct.Data, nil
@@ -0,0 +1,64 @@
+package cryptomus_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestGetTradesNullValues(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/exchange/market/trades/BTC_USDT" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{
+			"data": [
+				{
+					"trade_id": "01JBP2KQ3VMKX8JSV3R0DCJ71Q",
+					"price": "68928.9500000000000000",
+					"base_volume": "64.9999998500000000",
+					"quote_volume": "0.0009430000000000",
+					"timestamp": 1730539019,
+					"type": "sell"
+				},
+				{
+					"trade_id": "01JBP00NCHWPH51YSS6TRK26H7",
+					"price": null,
+					"base_volume": null,
+					"quote_volume": "0.2909080000000000",
+					"timestamp": 1730536297,
+					"type": "sell"
+				}
+			]
+		}`))
+	})
+
+	trades, err := cryptomus.GetTrades("BTC_USDT")
+	if err != nil {
+		t.Fatalf("error getting trades: %v", err)
+	}
+	if len(trades) != 2 {
+		t.Fatalf("got %d trades, want 2", len(trades))
+	}
+
+	first := trades[0]
+	if first.Price == nil || *first.Price != "68928.9500000000000000" || first.Type != cryptomus.TradeSell {
+		t.Errorf("unexpected first trade %+v", first)
+	}
+	if price, err := first.PriceDecimal(); err != nil || price.FloatString(2) != "68928.95" {
+		t.Errorf("got price %v, %v", price, err)
+	}
+	if want := time.Date(2024, 11, 2, 9, 16, 59, 0, time.UTC); !first.Time().Equal(want) {
+		t.Errorf("got time %v, want %v", first.Time(), want)
+	}
+
+	second := trades[1]
+	if second.Price != nil || second.BaseVolume != nil || second.QuoteVolume != "0.2909080000000000" {
+		t.Errorf("unexpected second trade %+v", second)
+	}
+	if price, err := second.PriceDecimal(); price != nil || err != nil {
+		t.Errorf("got price %v, %v, want nil, nil", price, err)
+	}
+}
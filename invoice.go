@@ -1,5 +1,11 @@
 package cryptomus
 
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
 // Invoice defines the payload for creating an invoice
 //
 // The invoice will have a specific cryptocurrency and address at the time of creation only if currency or to_currency parameter is a cryptocurrency and the network parameter is passed (or a cryptocurrency has only one network, for example BTC).
@@ -218,5 +224,80 @@ type Currency struct {
 	// (Required) Currency code
 	Currency string `json:"currency"`
 	// (Optional) Blockchain network code
-	Network *string `json:"network"`
+	Network *string `json:"network,omitempty"`
+}
+
+// Validate checks the invoice against the constraints documented for its fields, so CreateInvoice can fail without a round trip to Cryptomus.
+//
+// It returns a *ValidationError listing every invalid field.
+// It does not check that the entries of Currencies are available payment services, which needs a request; see ValidateCurrencies.
+//
+// See "Creating an invoice" https://doc.cryptomus.com/business/payments/creating-invoice
+func (i Invoice) Validate() error {
+	errs := fieldErrors{}
+	errs.amount("amount", i.Amount)
+	errs.required("currency", i.Currency)
+	errs.alphaDash("order_id", i.OrderID, 1, 128)
+	errs.url("url_return", i.URLReturn)
+	errs.url("url_success", i.URLSuccess)
+	errs.url("url_callback", i.URLCallback)
+	errs.between("lifetime", i.Lifetime, MinInvoiceLifetime, MaxInvoiceLifetime)
+	errs.between("subtract", i.Subtract, 0, 100)
+	errs.between("accuracy_payment_percent", i.AccuracyPaymentPercent, 0, 5)
+	errs.between("discount_percent", i.DiscountPercent, -99, 100)
+	if i.AdditionalData != nil {
+		errs.length("additional_data", *i.AdditionalData, 0, 255)
+	}
+	if i.CourseSource != nil {
+		errs.length("course_source", *i.CourseSource, 4, 20)
+	}
+	for n, currency := range i.Currencies {
+		errs.required(fmt.Sprintf("currencies.%d.currency", n), currency.Currency)
+	}
+	return errs.err()
+}
+
+// ValidateCurrencies checks that every entry of Currencies is a currency, and network if given, of an available service in services, as returned by ListPaymentServices.
+//
+// It returns a *ValidationError naming every unsupported pair, e.g.
+//
+//	services, err := merchant.ListPaymentServices()
+//	if err != nil {
+//		return err
+//	}
+//	if err := invoice.ValidateCurrencies(services); err != nil {
+//		return err
+//	}
+//
+// Validate cannot do this check itself since it works offline. CreateInvoice does it before sending when WithInvoiceMismatchCheck is set.
+func (i Invoice) ValidateCurrencies(services []Service) error {
+	errs := fieldErrors{}
+	for n, currency := range i.Currencies {
+		if !supportsCurrency(services, currency) {
+			pair := currency.Currency
+			if currency.Network != nil {
+				pair += " on " + *currency.Network
+			}
+			errs.add(fmt.Sprintf("currencies.%d", n), "%s is not an available payment service", pair)
+		}
+	}
+	return errs.err()
+}
+
+// supportsCurrency reports whether an available service matches currency, on any network if it has none.
+func supportsCurrency(services []Service, currency Currency) bool {
+	for _, service := range services {
+		if !service.IsAvailable || !strings.EqualFold(service.Currency, currency.Currency) {
+			continue
+		}
+		if currency.Network == nil || strings.EqualFold(service.Network, *currency.Network) {
+			return true
+		}
+	}
+	return false
+}
+
+// AmountDecimal returns amount as an exact decimal, or nil if it is empty.
+func (i Invoice) AmountDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(i.Amount)
 }
@@ -0,0 +1,154 @@
+package cryptomus_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestInvoiceValidate(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	stringPtr := func(s string) *string { return &s }
+	valid := func() cryptomus.Invoice {
+		return cryptomus.Invoice{Amount: "15", Currency: "USD", OrderID: "order_1-a"}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*cryptomus.Invoice)
+		field  string // "" if the invoice is valid
+	}{
+		{"valid", func(i *cryptomus.Invoice) {}, ""},
+		{"missing amount", func(i *cryptomus.Invoice) { i.Amount = "" }, "amount"},
+		{"comma amount", func(i *cryptomus.Invoice) { i.Amount = "10,28" }, "amount"},
+		{"missing currency", func(i *cryptomus.Invoice) { i.Currency = "" }, "currency"},
+		{"missing order_id", func(i *cryptomus.Invoice) { i.OrderID = "" }, "order_id"},
+		{"order_id 128", func(i *cryptomus.Invoice) { i.OrderID = strings.Repeat("a", 128) }, ""},
+		{"order_id 129", func(i *cryptomus.Invoice) { i.OrderID = strings.Repeat("a", 129) }, "order_id"},
+		{"order_id space", func(i *cryptomus.Invoice) { i.OrderID = "order 1" }, "order_id"},
+		{"order_id special", func(i *cryptomus.Invoice) { i.OrderID = "order#1" }, "order_id"},
+		{"lifetime 300", func(i *cryptomus.Invoice) { i.Lifetime = intPtr(300) }, ""},
+		{"lifetime 299", func(i *cryptomus.Invoice) { i.Lifetime = intPtr(299) }, "lifetime"},
+		{"lifetime 43200", func(i *cryptomus.Invoice) { i.Lifetime = intPtr(43200) }, ""},
+		{"lifetime 43201", func(i *cryptomus.Invoice) { i.Lifetime = intPtr(43201) }, "lifetime"},
+		{"subtract 0", func(i *cryptomus.Invoice) { i.Subtract = intPtr(0) }, ""},
+		{"subtract -1", func(i *cryptomus.Invoice) { i.Subtract = intPtr(-1) }, "subtract"},
+		{"subtract 100", func(i *cryptomus.Invoice) { i.Subtract = intPtr(100) }, ""},
+		{"subtract 101", func(i *cryptomus.Invoice) { i.Subtract = intPtr(101) }, "subtract"},
+		{"accuracy 0", func(i *cryptomus.Invoice) { i.AccuracyPaymentPercent = intPtr(0) }, ""},
+		{"accuracy -1", func(i *cryptomus.Invoice) { i.AccuracyPaymentPercent = intPtr(-1) }, "accuracy_payment_percent"},
+		{"accuracy 5", func(i *cryptomus.Invoice) { i.AccuracyPaymentPercent = intPtr(5) }, ""},
+		{"accuracy 6", func(i *cryptomus.Invoice) { i.AccuracyPaymentPercent = intPtr(6) }, "accuracy_payment_percent"},
+		{"discount -99", func(i *cryptomus.Invoice) { i.DiscountPercent = intPtr(-99) }, ""},
+		{"discount -100", func(i *cryptomus.Invoice) { i.DiscountPercent = intPtr(-100) }, "discount_percent"},
+		{"discount 100", func(i *cryptomus.Invoice) { i.DiscountPercent = intPtr(100) }, ""},
+		{"discount 101", func(i *cryptomus.Invoice) { i.DiscountPercent = intPtr(101) }, "discount_percent"},
+		{"url_callback", func(i *cryptomus.Invoice) { i.URLCallback = stringPtr("https://your.site/callback") }, ""},
+		{"url_callback 5", func(i *cryptomus.Invoice) { i.URLCallback = stringPtr("http:") }, "url_callback"},
+		{"url_callback 256", func(i *cryptomus.Invoice) { i.URLCallback = stringPtr("https://a.io/" + strings.Repeat("a", 243)) }, "url_callback"},
+		{"url_return relative", func(i *cryptomus.Invoice) { i.URLReturn = stringPtr("/checkout/return") }, "url_return"},
+		{"url_success 255", func(i *cryptomus.Invoice) { i.URLSuccess = stringPtr("https://a.io/" + strings.Repeat("a", 242)) }, ""},
+		{"additional_data 256", func(i *cryptomus.Invoice) { i.AdditionalData = stringPtr(strings.Repeat("a", 256)) }, "additional_data"},
+		{"course_source 3", func(i *cryptomus.Invoice) { i.CourseSource = stringPtr("Bin") }, "course_source"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			invoice := valid()
+			test.modify(&invoice)
+
+			err := invoice.Validate()
+			if test.field == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationError *cryptomus.ValidationError
+			if !errors.As(err, &validationError) {
+				t.Fatalf("got error %v, want a ValidationError", err)
+			}
+			if len(validationError.Errors) != 1 || validationError.Errors[test.field] == nil {
+				t.Errorf("got errors %v, want one for %s", validationError.Errors, test.field)
+			}
+			if !cryptomus.IsValidationError(err) {
+				t.Error("IsValidationError is false")
+			}
+		})
+	}
+}
+
+func TestInvoiceValidateCurrencies(t *testing.T) {
+	stringPtr := func(s string) *string { return &s }
+	services := []cryptomus.Service{
+		{Network: "tron", Currency: "USDT", IsAvailable: true},
+		{Network: "bsc", Currency: "USDT", IsAvailable: false},
+		{Network: "btc", Currency: "BTC", IsAvailable: true},
+	}
+
+	invoice := cryptomus.Invoice{Amount: "15", Currency: "USD", OrderID: "1", Currencies: []cryptomus.Currency{
+		{Currency: "USDT", Network: stringPtr("TRON")},
+		{Currency: "USDT", Network: stringPtr("bsc")},
+		{Currency: "BTC"},
+		{Currency: "XMR"},
+	}}
+
+	var validationError *cryptomus.ValidationError
+	if err := invoice.ValidateCurrencies(services); !errors.As(err, &validationError) {
+		t.Fatalf("got error %v, want a ValidationError", err)
+	}
+	if len(validationError.Errors) != 2 || validationError.Errors["currencies.1"] == nil || validationError.Errors["currencies.3"] == nil {
+		t.Errorf("got errors %v, want ones for currencies.1 and currencies.3", validationError.Errors)
+	}
+	if !strings.Contains(validationError.Error(), "USDT on bsc") {
+		t.Errorf("error %q does not name the pair", validationError)
+	}
+
+	invoice.Currencies = invoice.Currencies[:1]
+	if err := invoice.ValidateCurrencies(services); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	invoice.Currencies = []cryptomus.Currency{{Network: stringPtr("tron")}}
+	if err := invoice.Validate(); !cryptomus.IsValidationError(err) {
+		t.Errorf("got error %v, want a validation error for a currency without code", err)
+	}
+}
+
+func TestInvoiceCurrenciesJSON(t *testing.T) {
+	tron := "tron"
+	tests := []struct {
+		name    string
+		invoice cryptomus.Invoice
+		want    string
+	}{
+		{
+			name:    "no currencies",
+			invoice: cryptomus.Invoice{Amount: "15", Currency: "USD", OrderID: "1"},
+			want:    `{"amount":"15","currency":"USD","order_id":"1"}`,
+		},
+		{
+			name: "with and without network",
+			invoice: cryptomus.Invoice{Amount: "15", Currency: "USD", OrderID: "1",
+				Currencies:       []cryptomus.Currency{{Currency: "USDT", Network: &tron}, {Currency: "BTC"}},
+				ExceptCurrencies: []cryptomus.Currency{{Currency: "ETH"}},
+			},
+			want: `{"amount":"15","currency":"USD","order_id":"1","currencies":[{"currency":"USDT","network":"tron"},{"currency":"BTC"}],"except_currencies":[{"currency":"ETH"}]}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body, err := json.Marshal(test.invoice)
+			if err != nil {
+				t.Fatalf("error marshalling invoice: %v", err)
+			}
+			if string(body) != test.want {
+				t.Errorf("got %s, want %s", body, test.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,11 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"net/http"
 	"strings"
+	"time"
 )
 
 // See "Get directions list" https://doc.cryptomus.com/personal/converts/directions-list
@@ -25,6 +26,25 @@ type Direction struct {
 	Rate string `json:"rate"`
 }
 
+// AmountInRange reports whether fromAmount, in the currency From, is within the min_from and max_from limits of the direction. An empty limit does not bound the amount.
+//
+// It returns an error if fromAmount or a limit is not a decimal number.
+func (d Direction) AmountInRange(fromAmount string) (bool, error) {
+	value, err := parseDecimal(fromAmount)
+	if err != nil {
+		return false, err
+	}
+	minFrom, err := parseOptionalDecimal(d.MinFrom)
+	if err != nil {
+		return false, fmt.Errorf("error parsing min_from of %s to %s: %w", d.From, d.To, err)
+	}
+	maxFrom, err := parseOptionalDecimal(d.MaxFrom)
+	if err != nil {
+		return false, fmt.Errorf("error parsing max_from of %s to %s: %w", d.From, d.To, err)
+	}
+	return (minFrom == nil || value.Cmp(minFrom) >= 0) && (maxFrom == nil || value.Cmp(maxFrom) <= 0), nil
+}
+
 // See "Get directions list" https://doc.cryptomus.com/personal/converts/directions-list
 //
 // # Response example
@@ -47,35 +67,210 @@ type Direction struct {
 //		}
 //	}
 func (u *User) ListDirections() ([]Direction, error) {
-	httpResponse, err := u.sendPaymentRequest("GET", urlListDirections, nil)
+	return u.ListDirectionsContext(context.Background())
+}
+
+// ListDirectionsContext is like ListDirections but sends the request with the provided context.
+func (u *User) ListDirectionsContext(ctx context.Context) ([]Direction, error) {
+	httpResponse, err := u.sendPaymentRequest(ctx, "GET", urlListDirections, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer httpResponse.Body.Close()
 
 	var response = struct {
-		State   int         `json:"state"`
-		Result  []Direction `json:"result"`
-		Message string      `json:"message"`
-		Code    int         `json:"code"`
-		Error   string      `json:"error"`
+		State   int            `json:"state"`
+		Result  []Direction    `json:"result"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := u.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
+
+	u.directionsMu.Lock()
+	u.directions = response.Result
+	u.directionsFetchedAt = time.Now()
+	u.directionsMu.Unlock()
+
+	return response.Result, nil
+}
+
+// ErrDirectionNotFound is returned when Cryptomus has no convert direction from one currency to another.
+var ErrDirectionNotFound = errors.New("convert direction not found")
+
+// GetDirection is like ListDirections but returns only the direction from one currency to another, e.g. BTC to USDT. Currency codes are matched case-insensitively.
+//
+// The returned error wraps ErrDirectionNotFound if the pair is not in the list.
+//
+// See "Get directions list" https://doc.cryptomus.com/personal/converts/directions-list
+func (u *User) GetDirection(from, to string) (*Direction, error) {
+	return u.GetDirectionContext(context.Background(), from, to)
+}
+
+// GetDirectionContext is like GetDirection but sends the request with the provided context.
+func (u *User) GetDirectionContext(ctx context.Context, from, to string) (*Direction, error) {
+	directions, err := u.ListDirectionsContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	direction, ok := findDirection(directions, from, to)
+	if !ok {
+		return nil, fmt.Errorf("%w: from %s to %s", ErrDirectionNotFound, from, to)
 	}
+	return &direction, nil
+}
 
-	return response.Result, nil
+// directionsCacheTTL is how long EstimateConvert reuses the rates of the last ListDirections call.
+const directionsCacheTTL = time.Minute
+
+// EstimateConvert estimates how much of currency to is received for amount of currency from, using the rate of the matching direction.
+//
+// The directions are fetched with ListDirections and cached for a minute, so most calls need no request. The result is an estimate for previews only: it ignores the commission and may use a rate up to a minute old. Use CalculateConvert for the live quote before creating an order.
+//
+// The estimate is truncated to 8 decimal places. It returns an error wrapping ErrDirectionNotFound if there is no direction from from to to, or an error if amount is outside the direction's min_from and max_from limits.
+func (u *User) EstimateConvert(from, to, amount string) (string, error) {
+	return u.EstimateConvertContext(context.Background(), from, to, amount)
+}
+
+// EstimateConvertContext is like EstimateConvert but sends the ListDirections request, if one is needed, with the provided context.
+func (u *User) EstimateConvertContext(ctx context.Context, from, to, amount string) (string, error) {
+	value, err := parseDecimal(amount)
+	if err != nil {
+		return "", err
+	}
+
+	u.directionsMu.Lock()
+	directions := u.directions
+	fresh := time.Since(u.directionsFetchedAt) < directionsCacheTTL
+	u.directionsMu.Unlock()
+
+	if directions == nil || !fresh {
+		directions, err = u.ListDirectionsContext(ctx)
+		if err != nil {
+			return "", fmt.Errorf("error listing directions: %w", err)
+		}
+	}
+
+	direction, ok := findDirection(directions, from, to)
+	if !ok {
+		return "", fmt.Errorf("%w: from %s to %s", ErrDirectionNotFound, from, to)
+	}
+
+	rate, err := parseDecimal(direction.Rate)
+	if err != nil {
+		return "", fmt.Errorf("error parsing rate of %s to %s: %w", direction.From, direction.To, err)
+	}
+	if minFrom, err := parseDecimal(direction.MinFrom); err == nil && value.Cmp(minFrom) < 0 {
+		return "", fmt.Errorf("amount %s is less than the minimum %s %s", amount, direction.MinFrom, direction.From)
+	}
+	if maxFrom, err := parseDecimal(direction.MaxFrom); err == nil && value.Cmp(maxFrom) > 0 {
+		return "", fmt.Errorf("amount %s is more than the maximum %s %s", amount, direction.MaxFrom, direction.From)
+	}
+
+	return formatDecimal(value.Mul(value, rate), decimalPlaces), nil
+}
+
+// RateCondition is the side of the target rate WaitForRateCondition waits for.
+type RateCondition int
+
+const (
+	// RateAtLeast is met once the rate rises to the target or above it.
+	RateAtLeast RateCondition = iota
+	// RateAtMost is met once the rate falls to the target or below it.
+	RateAtMost
+)
+
+// WaitForRate polls ListDirections every poll until the rate of the direction from one currency to another, e.g. BTC to USDT, reaches targetRate, then returns the direction.
+//
+// The rate reaches targetRate when it crosses it from the side of the first polled rate, so WaitForRate waits for a rise if the rate starts below targetRate and for a fall if it starts above it.
+// Use WaitForRateCondition to choose the side explicitly.
+//
+// It returns early with the error of ctx once ctx is done. Server and network errors are retried at the next poll; other API errors, a missing direction and three failures in a row end the wait.
+// The direction is only a quote: use CalculateConvert or create the order right after, as the rate keeps moving.
+func (u *User) WaitForRate(ctx context.Context, from, to, targetRate string, poll time.Duration) (*Direction, error) {
+	return u.waitForRate(ctx, from, to, targetRate, nil, poll)
+}
+
+// WaitForRateCondition is like WaitForRate but waits until the rate meets condition, whatever the side of the first polled rate.
+func (u *User) WaitForRateCondition(ctx context.Context, from, to, targetRate string, condition RateCondition, poll time.Duration) (*Direction, error) {
+	if condition != RateAtLeast && condition != RateAtMost {
+		return nil, fmt.Errorf("unknown rate condition %d", condition)
+	}
+	return u.waitForRate(ctx, from, to, targetRate, &condition, poll)
+}
+
+// waitForRate implements WaitForRate and WaitForRateCondition. A nil condition is set from the first polled rate.
+func (u *User) waitForRate(ctx context.Context, from, to, targetRate string, condition *RateCondition, poll time.Duration) (*Direction, error) {
+	if poll <= 0 {
+		return nil, fmt.Errorf("poll interval must be positive, got %s", poll)
+	}
+	target, err := parseDecimal(targetRate)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing target rate: %w", err)
+	}
+
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		directions, err := u.ListDirectionsContext(ctx)
+		switch {
+		case err == nil:
+			failures = 0
+			direction, ok := findDirection(directions, from, to)
+			if !ok {
+				return nil, fmt.Errorf("%w: from %s to %s", ErrDirectionNotFound, from, to)
+			}
+			rate, err := parseDecimal(direction.Rate)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing rate of %s to %s: %w", direction.From, direction.To, err)
+			}
+			cmp := rate.Cmp(target)
+			if condition == nil {
+				c := RateAtLeast
+				if cmp > 0 {
+					c = RateAtMost
+				}
+				condition = &c
+			}
+			if (*condition == RateAtLeast && cmp >= 0) || (*condition == RateAtMost && cmp <= 0) {
+				return &direction, nil
+			}
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case isAPIError(err) && !IsServerError(err):
+			return nil, fmt.Errorf("error waiting for rate: %w", err)
+		default:
+			failures++
+			if failures == maxWaitFailures {
+				return nil, fmt.Errorf("error waiting for rate, %d polls failed in a row: %w", failures, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// findDirection returns the direction from one currency to another, matching the codes case-insensitively.
+func findDirection(directions []Direction, from, to string) (Direction, bool) {
+	for _, direction := range directions {
+		if strings.EqualFold(direction.From, from) && strings.EqualFold(direction.To, to) {
+			return direction, true
+		}
+	}
+	return Direction{}, false
 }
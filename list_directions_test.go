@@ -0,0 +1,163 @@
+package cryptomus_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestEstimateConvert(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"state": 0, "result": [{"from": "TRX", "to": "ETH", "min_from": "100.00000000", "min_to": "0.00100000", "max_from": "100000.00000000", "max_to": "1000000.00000000", "rate": "0.00003451"}]}`))
+	}))
+	defer server.Close()
+
+	user := cryptomus.NewUser("user", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+
+	estimate, err := user.EstimateConvert("TRX", "ETH", "1234.5")
+	if err != nil {
+		t.Fatalf("error estimating convert: %v", err)
+	}
+	// 1234.5 * 0.00003451 = 0.042602595, truncated to 8 decimal places
+	if estimate != "0.04260259" {
+		t.Errorf("got %s, want 0.04260259", estimate)
+	}
+
+	if _, err := user.EstimateConvert("trx", "eth", "50"); err == nil {
+		t.Error("expected error for an amount below min_from")
+	}
+	if _, err := user.EstimateConvert("ETH", "TRX", "1"); err == nil {
+		t.Error("expected error for an unknown direction")
+	}
+
+	if requests != 1 {
+		t.Errorf("got %d directions requests, want 1", requests)
+	}
+}
+
+func TestGetDirection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"state": 0, "result": [{"from": "TRX", "to": "ETH", "min_from": "100.00000000", "min_to": "0.00100000", "max_from": "100000.00000000", "max_to": "1000000.00000000", "rate": "0.00003451"}]}`))
+	}))
+	defer server.Close()
+
+	user := cryptomus.NewUser("user", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+
+	direction, err := user.GetDirection("trx", "eth")
+	if err != nil {
+		t.Fatalf("error getting direction: %v", err)
+	}
+	if direction.From != "TRX" || direction.To != "ETH" || direction.Rate != "0.00003451" {
+		t.Errorf("got direction %+v", direction)
+	}
+
+	if _, err := user.GetDirection("ETH", "TRX"); !errors.Is(err, cryptomus.ErrDirectionNotFound) {
+		t.Errorf("got error %v, want ErrDirectionNotFound", err)
+	}
+	if _, err := user.EstimateConvert("ETH", "TRX", "1"); !errors.Is(err, cryptomus.ErrDirectionNotFound) {
+		t.Errorf("got error %v from EstimateConvert, want ErrDirectionNotFound", err)
+	}
+}
+
+func TestDirectionAmountInRange(t *testing.T) {
+	direction := cryptomus.Direction{From: "TRX", To: "ETH", MinFrom: "100.00000000", MaxFrom: "100000.00000000", Rate: "0.00003451"}
+
+	tests := []struct {
+		amount  string
+		want    bool
+		wantErr bool
+	}{
+		{"100", true, false},
+		{"1234.5", true, false},
+		{"100000.00000000", true, false},
+		{"99.99999999", false, false},
+		{"100000.00000001", false, false},
+		{"1,5", false, true},
+	}
+	for _, test := range tests {
+		got, err := direction.AmountInRange(test.amount)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error: %v", test.amount, err, test.wantErr)
+		}
+		if got != test.want {
+			t.Errorf("%s: got %v, want %v", test.amount, got, test.want)
+		}
+	}
+
+	unbounded := cryptomus.Direction{From: "TRX", To: "ETH"}
+	if ok, err := unbounded.AmountInRange("1000000000"); !ok || err != nil {
+		t.Errorf("got %v, %v without limits, want true", ok, err)
+	}
+}
+
+func TestWaitForRate(t *testing.T) {
+	rates := []string{"60000", "61000", "62500", "59000"}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rate := rates[min(requests, len(rates)-1)]
+		requests++
+		w.Write([]byte(`{"state": 0, "result": [{"from": "BTC", "to": "USDT", "rate": "` + rate + `"}]}`))
+	}))
+	defer server.Close()
+
+	user := cryptomus.NewUser("user", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	direction, err := user.WaitForRate(ctx, "btc", "usdt", "62000", time.Millisecond)
+	if err != nil {
+		t.Fatalf("error waiting for rate: %v", err)
+	}
+	if direction.Rate != "62500" || requests != 3 {
+		t.Errorf("got rate %s after %d requests, want 62500 after 3", direction.Rate, requests)
+	}
+
+	requests = 0
+	direction, err = user.WaitForRateCondition(ctx, "BTC", "USDT", "59500", cryptomus.RateAtMost, time.Millisecond)
+	if err != nil {
+		t.Fatalf("error waiting for rate: %v", err)
+	}
+	if direction.Rate != "59000" || requests != 4 {
+		t.Errorf("got rate %s after %d requests, want 59000 after 4", direction.Rate, requests)
+	}
+
+	if _, err := user.WaitForRate(ctx, "ETH", "USDT", "1", time.Millisecond); err == nil {
+		t.Error("expected error for an unknown direction")
+	}
+
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if _, err := user.WaitForRate(cancelled, "BTC", "USDT", "100000", time.Millisecond); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
+
+// TestEstimateConvertConcurrentUse shares the cached directions of one User between goroutines; run it with -race.
+func TestEstimateConvertConcurrentUse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"state": 0, "result": [{"from": "TRX", "to": "ETH", "min_from": "100.00000000", "max_from": "100000.00000000", "rate": "0.00003451"}]}`))
+	}))
+	defer server.Close()
+
+	user := cryptomus.NewUser("user", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if estimate, err := user.EstimateConvert("TRX", "ETH", "1000"); err != nil || estimate != "0.03451000" {
+				t.Errorf("got %s, %v, want 0.03451000", estimate, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
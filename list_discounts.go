@@ -1,9 +1,10 @@
 package cryptomus
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
 	"strings"
 )
 
@@ -24,8 +25,45 @@ type Discount struct {
 	Network string `json:"network"`
 	// Currency code
 	Currency string `json:"currency"`
-	// Discount percent
-	Discount string `json:"Discount percent"`
+	// Discount percent, e.g. "-10". Cryptomus sends it as a number in the list of discounts and as a string when it is set.
+	Discount string `json:"discount"`
+}
+
+// UnmarshalJSON decodes a discount, accepting the discount percent as a number or a string.
+func (d *Discount) UnmarshalJSON(data []byte) error {
+	type discount Discount
+	aux := struct {
+		Discount json.RawMessage `json:"discount"`
+		*discount
+	}{
+		discount: (*discount)(d),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	percent, err := numberOrString(aux.Discount)
+	if err != nil {
+		return fmt.Errorf("error decoding discount: %w", err)
+	}
+	d.Discount = percent
+
+	return nil
+}
+
+// Percent returns the discount percent as an int, the type DiscountRequest sets it with, e.g. -10 for "-10" or "-10.00".
+//
+// Discounts are whole percents, so it returns an error for a fractional value such as "5.5", as well as for one that is not a decimal number.
+func (d Discount) Percent() (int, error) {
+	percent, err := parseDecimal(d.Discount)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing discount: %w", err)
+	}
+	if !percent.IsInt() || !percent.Num().IsInt64() {
+		return 0, fmt.Errorf("discount %q is not a whole percent", d.Discount)
+	}
+	return int(percent.Num().Int64()), nil
 }
 
 // See "List of discounts" https://doc.cryptomus.com/business/discount/list
@@ -51,35 +89,62 @@ type Discount struct {
 //		]
 //	}
 func (m *Merchant) ListDiscounts() ([]Discount, error) {
-	httpResponse, err := m.sendPaymentRequest("POST", urlListDiscounts, struct{}{})
+	return m.ListDiscountsContext(context.Background())
+}
+
+// ListDiscountsContext is like ListDiscounts but sends the request with the provided context.
+func (m *Merchant) ListDiscountsContext(ctx context.Context) ([]Discount, error) {
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlListDiscounts, struct{}{})
 	if err != nil {
 		return nil, err
 	}
 	defer httpResponse.Body.Close()
 
 	var response = struct {
-		State   int        `json:"state"`
-		Result  []Discount `json:"result"`
-		Message string     `json:"message"`
-		Code    int        `json:"code"`
-		Error   string     `json:"error"`
+		State   int            `json:"state"`
+		Result  []Discount     `json:"result"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	return response.Result, nil
+}
+
+// ErrDiscountNotFound is returned by GetDiscount when the merchant has no payment service for the currency and network.
+var ErrDiscountNotFound = errors.New("discount not found")
+
+// GetDiscount is like ListDiscounts but returns only the discount of one currency on one network, e.g. USDT on tron.
+// Codes are matched case-insensitively, and network may be an alias set with WithNetworkAliases.
+//
+// The returned error wraps ErrDiscountNotFound if the currency and network are not in the list.
+//
+// See "List of discounts" https://doc.cryptomus.com/business/discount/list
+func (m *Merchant) GetDiscount(currency, network string) (*Discount, error) {
+	return m.GetDiscountContext(context.Background(), currency, network)
+}
+
+// GetDiscountContext is like GetDiscount but sends the request with the provided context.
+func (m *Merchant) GetDiscountContext(ctx context.Context, currency, network string) (*Discount, error) {
+	discounts, err := m.ListDiscountsContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return response.Result, nil
+	network = m.network(network)
+	for _, discount := range discounts {
+		if strings.EqualFold(discount.Currency, currency) && strings.EqualFold(discount.Network, network) {
+			return &discount, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s on %s", ErrDiscountNotFound, currency, network)
 }
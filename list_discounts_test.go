@@ -0,0 +1,211 @@
+package cryptomus_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+const discountsFixture = `{
+	"state": 0,
+	"result": [
+		{"currency": "BTC", "network": "btc", "discount": 0},
+		{"currency": "BUSD", "network": "bsc", "discount": -10},
+		{"currency": "DASH", "network": "dash", "discount": 0}
+	]
+}`
+
+func TestListDiscounts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(discountsFixture))
+	}))
+	defer server.Close()
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+	discounts, err := merchant.ListDiscounts()
+	if err != nil {
+		t.Fatalf("error listing discounts: %v", err)
+	}
+
+	want := []cryptomus.Discount{
+		{Currency: "BTC", Network: "btc", Discount: "0"},
+		{Currency: "BUSD", Network: "bsc", Discount: "-10"},
+		{Currency: "DASH", Network: "dash", Discount: "0"},
+	}
+	if len(discounts) != len(want) {
+		t.Fatalf("got %d discounts, want %d", len(discounts), len(want))
+	}
+	for i := range want {
+		if discounts[i] != want[i] {
+			t.Errorf("discount %d: got %+v, want %+v", i, discounts[i], want[i])
+		}
+	}
+}
+
+func TestGetDiscount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(discountsFixture))
+	}))
+	defer server.Close()
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL), cryptomus.WithNetworkAliases(map[string]string{"BEP20": "bsc"}))
+
+	discount, err := merchant.GetDiscount("busd", "BEP20")
+	if err != nil {
+		t.Fatalf("error getting discount: %v", err)
+	}
+	if discount.Currency != "BUSD" || discount.Network != "bsc" || discount.Discount != "-10" {
+		t.Errorf("got discount %+v", discount)
+	}
+
+	if _, err := merchant.GetDiscount("BUSD", "tron"); !errors.Is(err, cryptomus.ErrDiscountNotFound) {
+		t.Errorf("got error %v, want ErrDiscountNotFound", err)
+	}
+}
+
+func TestDiscountUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+		wantErr          bool
+	}{
+		{"number", `{"currency": "BUSD", "network": "bsc", "discount": -10}`, "-10", false},
+		{"string", `{"currency": "BUSD", "network": "bsc", "discount": "-10"}`, "-10", false},
+		{"fraction", `{"currency": "BUSD", "network": "bsc", "discount": 2.5}`, "2.5", false},
+		{"null", `{"currency": "BUSD", "network": "bsc", "discount": null}`, "", false},
+		{"missing", `{"currency": "BUSD", "network": "bsc"}`, "", false},
+		{"bool", `{"currency": "BUSD", "network": "bsc", "discount": true}`, "", true},
+		{"invalid", `{"currency": "BUSD", "network": "bsc", "discount": "-10}`, "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var discount cryptomus.Discount
+			err := json.Unmarshal([]byte(test.body), &discount)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			if discount.Discount != test.want || discount.Currency != "BUSD" || discount.Network != "bsc" {
+				t.Errorf("got %+v, want discount %q", discount, test.want)
+			}
+		})
+	}
+}
+
+func TestSetDiscountNumericResponse(t *testing.T) {
+	for _, discount := range []string{`-10`, `"-10"`} {
+		t.Run(discount, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"state": 0, "result": {"currency": "BUSD", "network": "bsc", "discount": ` + discount + `}}`))
+			}))
+			defer server.Close()
+
+			merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+			request := cryptomus.DiscountRequest{Currency: "BUSD", Network: "bsc", DiscountPercent: -10}
+			result, err := merchant.SetDiscount(request)
+			if err != nil {
+				t.Fatalf("error setting discount: %v", err)
+			}
+			if result.Discount != "-10" {
+				t.Errorf("got discount %q, want -10", result.Discount)
+			}
+			if percent, err := result.Percent(); err != nil || percent != request.DiscountPercent {
+				t.Errorf("got percent %d, error %v, want the %d set", percent, err, request.DiscountPercent)
+			}
+		})
+	}
+
+	for discount, want := range map[string]int{"-10": -10, "-10.00": -10, "5": 5, "0.0": 0} {
+		if percent, err := (cryptomus.Discount{Discount: discount}).Percent(); err != nil || percent != want {
+			t.Errorf("%q: got percent %d, error %v, want %d", discount, percent, err, want)
+		}
+	}
+	for _, discount := range []string{"", "5.5", "-0.25", "ten", "1e2"} {
+		if percent, err := (cryptomus.Discount{Discount: discount}).Percent(); err == nil {
+			t.Errorf("%q: got percent %d, expected an error", discount, percent)
+		}
+	}
+}
+
+func TestSetDiscounts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("error decoding request: %v", err)
+		}
+		if request["currency"] == "BUSD" {
+			w.Write([]byte(`{"state": 1, "message": "The service BUSD bsc was not found"}`))
+			return
+		}
+		fmt.Fprintf(w, `{"state": 0, "result": {"currency": %q, "network": %q, "discount": "%v"}}`, request["currency"], request["network"], request["discount_percent"])
+	}))
+	defer server.Close()
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL), cryptomus.WithNetworkAliases(map[string]string{"TRC20": "tron"}))
+	requests := []cryptomus.DiscountRequest{
+		{Network: "TRC20", Currency: "USDT", DiscountPercent: 5},
+		{Network: "bsc", Currency: "BUSD", DiscountPercent: -20},
+		{Network: "btc", Currency: "BTC", DiscountPercent: -1},
+	}
+
+	results, err := merchant.SetDiscounts(context.Background(), requests)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != len(requests) {
+		t.Fatalf("got %d results, want %d", len(results), len(requests))
+	}
+	for i, result := range results {
+		if result.Request.Currency != requests[i].Currency {
+			t.Errorf("result %d is for %s, want %s", i, result.Request.Currency, requests[i].Currency)
+		}
+	}
+	if results[0].Err != nil || results[0].Discount.Network != "tron" || results[0].Discount.Discount != "5" {
+		t.Errorf("USDT: got %+v, %v", results[0].Discount, results[0].Err)
+	}
+	if !cryptomus.IsNotFound(results[1].Err) || results[1].Discount != nil {
+		t.Errorf("BUSD: got %+v, %v, want a not found error", results[1].Discount, results[1].Err)
+	}
+	if results[2].Err != nil || results[2].Discount.Discount != "-1" {
+		t.Errorf("BTC: got %+v, %v", results[2].Discount, results[2].Err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err = merchant.SetDiscounts(ctx, requests)
+	if !errors.Is(err, context.Canceled) || !errors.Is(results[len(results)-1].Err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
+
+func TestDiscountRequestJSON(t *testing.T) {
+	documented := `{
+	    "network": "bsc",
+	    "currency": "BUSD",
+	    "discount_percent": -20
+	}`
+
+	var request cryptomus.DiscountRequest
+	if err := json.Unmarshal([]byte(documented), &request); err != nil {
+		t.Fatalf("error decoding documented request: %v", err)
+	}
+	want := cryptomus.DiscountRequest{Network: "bsc", Currency: "BUSD", DiscountPercent: -20}
+	if request != want {
+		t.Errorf("got %+v, want %+v", request, want)
+	}
+
+	body, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("error encoding request: %v", err)
+	}
+	if string(body) != `{"network":"bsc","currency":"BUSD","discount_percent":-20}` {
+		t.Errorf("got %s", body)
+	}
+}
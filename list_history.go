@@ -1,10 +1,12 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"net/http"
-	"strings"
+	"iter"
+	"net/url"
+	"time"
 )
 
 // See "Payment history" https://doc.cryptomus.com/business/payments/payment-history
@@ -17,6 +19,8 @@ import (
 //		"date_from": "2023-05-04 00:00:00"
 //		"date_to": "2023-05-16 23:59:59"
 //	}
+//
+// Use NewHistoryRange to build it from time.Time values.
 type HistoryRequest struct {
 	// (Optional) Filtering by creation date, from
 	//    format: YYYY-MM-DD H:mm:ss
@@ -28,6 +32,31 @@ type HistoryRequest struct {
 	DateTo *string `json:"date_to,omitempty"`
 }
 
+// historyTimeLayout is the date_from/date_to format of history requests, in the Cryptomus timezone UTC+3.
+const historyTimeLayout = "2006-01-02 15:04:05"
+
+// NewHistoryRange returns a HistoryRequest filtering by creation date from from to to, both inclusive.
+//
+// The times are converted to UTC+3, the timezone Cryptomus uses, whatever their location. A zero time leaves that side of the range open.
+//
+// It returns an error if from is after to.
+func NewHistoryRange(from, to time.Time) (HistoryRequest, error) {
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return HistoryRequest{}, fmt.Errorf("invalid history range: %s is after %s", from, to)
+	}
+
+	var request HistoryRequest
+	if !from.IsZero() {
+		dateFrom := from.In(cryptomusLocation).Format(historyTimeLayout)
+		request.DateFrom = &dateFrom
+	}
+	if !to.IsZero() {
+		dateTo := to.In(cryptomusLocation).Format(historyTimeLayout)
+		request.DateTo = &dateTo
+	}
+	return request, nil
+}
+
 // See "Payment history" https://doc.cryptomus.com/business/payments/payment-history
 //
 // # Response example
@@ -122,14 +151,16 @@ type HistoryRequest struct {
 //		}
 //	}
 type paymentHistoryResponse struct {
-	Items    []Invoice `json:"items"`
-	Paginate paginate  `json:"paginate"`
+	Items    []Payment `json:"items"`
+	Paginate Paginate  `json:"paginate"`
 }
 
+// Paginate is the pagination block of a history page.
+//
 // To get next/previous page entries, specify the next/previous cursor hash in the query parameters (?cursor=nextCursorHash)
 //
 // See "Payment history" https://doc.cryptomus.com/business/payments/payment-history
-type paginate struct {
+type Paginate struct {
 	// Number of items on the current page
 	Count int `json:"count"`
 	// Whether there are enough elements to split into multiple pages (true / false)
@@ -142,15 +173,70 @@ type paginate struct {
 	PerPage int `json:"perPage"`
 }
 
-// See "Payment history" https://doc.cryptomus.com/business/payments/payment-history
-func (m *Merchant) nextPaymentHistoryPage(currentPage *paymentHistoryResponse) (*paymentHistoryResponse, error) {
-	if currentPage.Paginate.NextCursor == "" {
-		return nil, nil
+// historySeq pages through a history lazily for the *HistorySeq methods. fetch returns the items and the next cursor of the page at cursor, or of the first page if cursor is empty.
+//
+// The next page is only fetched once every item of the current one has been yielded and ctx is not done. Past the page limit of WithMaxHistoryPages it yields ErrTruncated; what names the history in the errors of later pages.
+func historySeq[T any](ctx context.Context, o *options, what string, fetch func(cursor string) ([]T, string, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		items, next, err := fetch("")
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for pages := 1; ; pages++ {
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if next == "" {
+				return
+			}
+			if o.historyTruncated(pages) {
+				yield(zero, ErrTruncated)
+				return
+			}
+			if err := ctx.Err(); err != nil {
+				yield(zero, fmt.Errorf("error paging %s: %w", what, err))
+				return
+			}
+			items, next, err = fetch(next)
+			if err != nil {
+				yield(zero, fmt.Errorf("error paging %s: %w", what, err))
+				return
+			}
+		}
 	}
+}
 
-	url := urlListPaymentHistory + "?cursor=" + currentPage.Paginate.NextCursor
+// collectHistory collects the items of seq for the List*Context methods. On ErrTruncated it returns the items collected so far along with it; on any other error, none.
+func collectHistory[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var items []T
+	for item, err := range seq {
+		if errors.Is(err, ErrTruncated) {
+			return items, err
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
 
-	httpResponse, err := m.sendPaymentRequest("POST", url, nil)
+// paymentHistoryPage fetches the page of payment history at cursor, or the first page if cursor is empty.
+//
+// See "Payment history" https://doc.cryptomus.com/business/payments/payment-history
+func (m *Merchant) paymentHistoryPage(ctx context.Context, cursor string, request HistoryRequest) (*paymentHistoryResponse, error) {
+	path := urlListPaymentHistory
+	if cursor != "" {
+		path += "?cursor=" + url.QueryEscape(cursor)
+	}
+
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", path, request)
 	if err != nil {
 		return nil, err
 	}
@@ -160,24 +246,17 @@ func (m *Merchant) nextPaymentHistoryPage(currentPage *paymentHistoryResponse) (
 		State   int                    `json:"state"`
 		Result  paymentHistoryResponse `json:"result"`
 		Message string                 `json:"message"`
+		Errors  responseErrors         `json:"errors"`
 		Code    int                    `json:"code"`
 		Error   string                 `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
@@ -293,59 +372,53 @@ func (m *Merchant) nextPaymentHistoryPage(currentPage *paymentHistoryResponse) (
 //			"date_from": ["validation.regex"]
 //		}
 //	}
-func (m *Merchant) ListPaymentHistory(request HistoryRequest) ([]Invoice, error) {
-	httpResponse, err := m.sendPaymentRequest("POST", urlListPaymentHistory, request)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResponse.Body.Close()
-
-	var response = struct {
-		State   int                    `json:"state"`
-		Result  paymentHistoryResponse `json:"result"`
-		Message string                 `json:"message"`
-		Errors  struct {
-			DateFrom []string `json:"date_from"`
-			DateTo   []string `json:"date_to"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
-	}{}
+func (m *Merchant) ListPaymentHistory(request HistoryRequest) ([]Payment, error) {
+	return m.ListPaymentHistoryContext(context.Background(), request)
+}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
-	}
+// ListPaymentHistoryContext is like ListPaymentHistory but sends every page request with the provided context.
+func (m *Merchant) ListPaymentHistoryContext(ctx context.Context, request HistoryRequest) ([]Payment, error) {
+	return collectHistory(m.PaymentHistorySeq(ctx, request))
+}
 
-	var errs []string
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 {
-		if response.Message != "" {
-			errs = append(errs, response.Message)
-		}
-		errs = append(errs, response.Errors.DateFrom...)
-		errs = append(errs, response.Errors.DateTo...)
-		if response.Error != "" {
-			errs = append(errs, response.Error)
+// PaymentHistorySeq is like ListPaymentHistoryContext but fetches the pages lazily, one request per page, and yields the payments as they arrive.
+//
+// The next page is only requested once every payment of the current page has been yielded, so breaking out of the loop stops paging. An error is yielded once, with a zero Payment, and ends the sequence.
+//
+//	for payment, err := range merchant.PaymentHistorySeq(ctx, cryptomus.HistoryRequest{}) {
+//		if err != nil {
+//			return err
+//		}
+//		if payment.OrderID == orderID {
+//			break
+//		}
+//	}
+func (m *Merchant) PaymentHistorySeq(ctx context.Context, request HistoryRequest) iter.Seq2[Payment, error] {
+	return historySeq(ctx, &m.options, "payment history", func(cursor string) ([]Payment, string, error) {
+		page, err := m.paymentHistoryPage(ctx, cursor, request)
+		if err != nil {
+			return nil, "", err
 		}
-	}
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
-	}
+		return page.Items, page.Paginate.NextCursor, nil
+	})
+}
 
-	var invoices []Invoice
-	invoices = append(invoices, response.Result.Items...)
-	page := response.Result
+// ListPaymentHistoryPage returns the single page of payment history at cursor, or the first page if cursor is empty, with the cursors of the next and previous pages, e.g. for "next" and "previous" buttons.
+//
+// next or prev is empty if there is no such page. Pass the same request with every cursor.
+//
+// See "Payment history" https://doc.cryptomus.com/business/payments/payment-history
+func (m *Merchant) ListPaymentHistoryPage(request HistoryRequest, cursor string) (payments []Payment, next, prev string, err error) {
+	return m.ListPaymentHistoryPageContext(context.Background(), request, cursor)
+}
 
-	for page.Paginate.NextCursor != "" {
-		page, err := m.nextPaymentHistoryPage(&page)
-		if err != nil {
-			return nil, fmt.Errorf("error paging payment history: %w", err)
-		}
-		if page != nil {
-			invoices = append(invoices, page.Items...)
-		}
+// ListPaymentHistoryPageContext is like ListPaymentHistoryPage but sends the request with the provided context.
+func (m *Merchant) ListPaymentHistoryPageContext(ctx context.Context, request HistoryRequest, cursor string) (payments []Payment, next, prev string, err error) {
+	page, err := m.paymentHistoryPage(ctx, cursor, request)
+	if err != nil {
+		return nil, "", "", err
 	}
-
-	return invoices, nil
+	return page.Items, page.Paginate.NextCursor, page.Paginate.PreviousCursor, nil
 }
 
 // payoutHistoryResponse represents the response structure for a payout history request.
@@ -401,17 +474,19 @@ type payoutHistoryResponse struct {
 	// Array of Payouts
 	Items []Payout `json:"items"`
 	// Data for pagination
-	Paginate paginate `json:"paginate"`
+	Paginate Paginate `json:"paginate"`
 }
 
+// payoutHistoryPage fetches the page of payout history at cursor, or the first page if cursor is empty.
+//
 // See "Payout history" https://doc.cryptomus.com/business/payouts/payout-history
-func (m *Merchant) nextPayoutHistoryPage(currentPage *payoutHistoryResponse) (*payoutHistoryResponse, error) {
-	if currentPage.Paginate.NextCursor == "" {
-		return nil, nil
+func (m *Merchant) payoutHistoryPage(ctx context.Context, cursor string, request HistoryRequest) (*payoutHistoryResponse, error) {
+	path := urlListPayoutHistory
+	if cursor != "" {
+		path += "?cursor=" + url.QueryEscape(cursor)
 	}
 
-	url := urlListPayoutHistory + "?cursor=" + currentPage.Paginate.NextCursor
-	httpResponse, err := m.sendPayoutRequest("POST", url, nil)
+	httpResponse, err := m.sendPayoutRequest(ctx, "POST", path, request)
 	if err != nil {
 		return nil, err
 	}
@@ -421,24 +496,21 @@ func (m *Merchant) nextPayoutHistoryPage(currentPage *payoutHistoryResponse) (*p
 		State   int                   `json:"state"`
 		Result  payoutHistoryResponse `json:"result"`
 		Message string                `json:"message"`
+		Errors  responseErrors        `json:"errors"`
 		Code    int                   `json:"code"`
 		Error   string                `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := m.checkMerchantUUID(response.Result.MerchantUUID); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
@@ -507,58 +579,25 @@ func (m *Merchant) nextPayoutHistoryPage(currentPage *payoutHistoryResponse) (*p
 //		}
 //	}
 func (m *Merchant) ListPayoutHistory(request HistoryRequest) ([]Payout, error) {
-	httpResponse, err := m.sendPayoutRequest("POST", urlListPayoutHistory, request)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResponse.Body.Close()
-
-	var response = struct {
-		State   int                   `json:"state"`
-		Result  payoutHistoryResponse `json:"result"`
-		Message string                `json:"message"`
-		Errors  struct {
-			DateFrom []string `json:"date_from"`
-			DateTo   []string `json:"date_to"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
-	}{}
-
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
-	}
-
-	var errs []string
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 {
-		if response.Message != "" {
-			errs = append(errs, response.Message)
-		}
-		errs = append(errs, response.Errors.DateFrom...)
-		errs = append(errs, response.Errors.DateTo...)
-		if response.Error != "" {
-			errs = append(errs, response.Error)
-		}
-	}
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
-	}
+	return m.ListPayoutHistoryContext(context.Background(), request)
+}
 
-	var payouts []Payout
-	payouts = append(payouts, response.Result.Items...)
-	page := response.Result
+// ListPayoutHistoryContext is like ListPayoutHistory but sends every page request with the provided context.
+func (m *Merchant) ListPayoutHistoryContext(ctx context.Context, request HistoryRequest) ([]Payout, error) {
+	return collectHistory(m.PayoutHistorySeq(ctx, request))
+}
 
-	for page.Paginate.NextCursor != "" {
-		page, err := m.nextPayoutHistoryPage(&page)
+// PayoutHistorySeq is like ListPayoutHistoryContext but fetches the pages lazily, one request per page, and yields the payouts as they arrive.
+//
+// The next page is only requested once every payout of the current page has been yielded, so breaking out of the loop stops paging. An error is yielded once, with a zero Payout, and ends the sequence.
+func (m *Merchant) PayoutHistorySeq(ctx context.Context, request HistoryRequest) iter.Seq2[Payout, error] {
+	return historySeq(ctx, &m.options, "payout history", func(cursor string) ([]Payout, string, error) {
+		page, err := m.payoutHistoryPage(ctx, cursor, request)
 		if err != nil {
-			return nil, fmt.Errorf("error paging payout history: %w", err)
-		}
-		if page != nil {
-			payouts = append(payouts, page.Items...)
+			return nil, "", err
 		}
-	}
-
-	return payouts, nil
+		return page.Items, page.Paginate.NextCursor, nil
+	})
 }
 
 // See "List of recurring payments" https://doc.cryptomus.com/business/recurring/list
@@ -596,18 +635,19 @@ func (m *Merchant) ListPayoutHistory(request HistoryRequest) ([]Payout, error) {
 //	  }
 type recurringPaymentHistoryResponse struct {
 	Items    []RecurringPayment `json:"items"`
-	Paginate paginate           `json:"paginate"`
+	Paginate Paginate           `json:"paginate"`
 }
 
+// recurringPaymentsPage fetches the page of recurring payments at cursor, or the first page if cursor is empty.
+//
 // See "List of recurring payments" https://doc.cryptomus.com/business/recurring/list
-func (m *Merchant) nextRecurringPaymentHistoryPage(currentPage *recurringPaymentHistoryResponse) (*recurringPaymentHistoryResponse, error) {
-	if currentPage.Paginate.NextCursor == "" {
-		return nil, nil
+func (m *Merchant) recurringPaymentsPage(ctx context.Context, cursor string) (*recurringPaymentHistoryResponse, error) {
+	path := urlListRecurringPayments
+	if cursor != "" {
+		path += "?cursor=" + url.QueryEscape(cursor)
 	}
 
-	url := urlListRecurringPayments + "?cursor=" + currentPage.Paginate.NextCursor
-
-	httpResponse, err := m.sendPaymentRequest("POST", url, struct{}{})
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", path, struct{}{})
 	if err != nil {
 		return nil, err
 	}
@@ -617,24 +657,17 @@ func (m *Merchant) nextRecurringPaymentHistoryPage(currentPage *recurringPayment
 		State   int                             `json:"state"`
 		Result  recurringPaymentHistoryResponse `json:"result"`
 		Message string                          `json:"message"`
+		Errors  responseErrors                  `json:"errors"`
 		Code    int                             `json:"code"`
 		Error   string                          `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error retrieving next page of payment history with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
@@ -642,52 +675,51 @@ func (m *Merchant) nextRecurringPaymentHistoryPage(currentPage *recurringPayment
 
 // See "List of recurring payments" https://doc.cryptomus.com/business/recurring/list
 func (m *Merchant) ListRecurringPayments() ([]RecurringPayment, error) {
-	httpResponse, err := m.sendPaymentRequest("POST", urlListRecurringPayments, struct{}{})
-	if err != nil {
-		return nil, err
-	}
-	defer httpResponse.Body.Close()
+	return m.ListRecurringPaymentsContext(context.Background())
+}
 
-	var response = struct {
-		State   int                             `json:"state"`
-		Result  recurringPaymentHistoryResponse `json:"result"`
-		Message string                          `json:"message"`
-		Code    int                             `json:"code"`
-		Error   string                          `json:"error"`
-	}{}
+// ListRecurringPaymentsContext is like ListRecurringPayments but sends every page request with the provided context.
+func (m *Merchant) ListRecurringPaymentsContext(ctx context.Context) ([]RecurringPayment, error) {
+	return collectHistory(m.RecurringPaymentsSeq(ctx))
+}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
-	}
+// ListRecurringPaymentsWithPaginate is like ListRecurringPayments but also returns the paginate block of the first page, e.g. for its PerPage and Count.
+//
+// See "List of recurring payments" https://doc.cryptomus.com/business/recurring/list
+func (m *Merchant) ListRecurringPaymentsWithPaginate() ([]RecurringPayment, *Paginate, error) {
+	return m.ListRecurringPaymentsWithPaginateContext(context.Background())
+}
 
-	var errs []string
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 {
-		if response.Message != "" {
-			errs = append(errs, response.Message)
+// ListRecurringPaymentsWithPaginateContext is like ListRecurringPaymentsWithPaginate but sends every page request with the provided context.
+func (m *Merchant) ListRecurringPaymentsWithPaginateContext(ctx context.Context) ([]RecurringPayment, *Paginate, error) {
+	var paginate *Paginate
+	recurringPayments, err := collectHistory(historySeq(ctx, &m.options, "recurring payments", func(cursor string) ([]RecurringPayment, string, error) {
+		page, err := m.recurringPaymentsPage(ctx, cursor)
+		if err != nil {
+			return nil, "", err
 		}
-		if response.Error != "" {
-			errs = append(errs, response.Error)
+		if paginate == nil {
+			paginate = &page.Paginate
 		}
+		return page.Items, page.Paginate.NextCursor, nil
+	}))
+	if err != nil && !errors.Is(err, ErrTruncated) {
+		return nil, nil, err
 	}
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
-	}
-
-	var recurringPayments []RecurringPayment
-	recurringPayments = append(recurringPayments, response.Result.Items...)
-	page := response.Result
+	return recurringPayments, paginate, err
+}
 
-	for page.Paginate.NextCursor != "" {
-		page, err := m.nextRecurringPaymentHistoryPage(&page)
+// RecurringPaymentsSeq is like ListRecurringPaymentsContext but fetches the pages lazily, one request per page, and yields the recurring payments as they arrive.
+//
+// The next page is only requested once every recurring payment of the current page has been yielded, so breaking out of the loop stops paging. An error is yielded once, with a zero RecurringPayment, and ends the sequence.
+func (m *Merchant) RecurringPaymentsSeq(ctx context.Context) iter.Seq2[RecurringPayment, error] {
+	return historySeq(ctx, &m.options, "recurring payments", func(cursor string) ([]RecurringPayment, string, error) {
+		page, err := m.recurringPaymentsPage(ctx, cursor)
 		if err != nil {
-			return nil, fmt.Errorf("error paging recurring payments: %w", err)
+			return nil, "", err
 		}
-		if page != nil {
-			recurringPayments = append(recurringPayments, page.Items...)
-		}
-	}
-
-	return recurringPayments, nil
+		return page.Items, page.Paginate.NextCursor, nil
+	})
 }
 
 // See "Get orders list" https://doc.cryptomus.com/personal/converts/orders-list
@@ -725,9 +757,11 @@ func (m *Merchant) ListRecurringPayments() ([]RecurringPayment, error) {
 //	}
 type listOrdersResponse struct {
 	Items    []MarketOrder `json:"items"`
-	Paginate paginate      `json:"Paginate"`
+	Paginate Paginate      `json:"paginate"`
 }
 
+// orderHistoryPage fetches the page of orders at cursor, or the first page if cursor is empty.
+//
 // Available options for type:
 //   - market
 //   - limit
@@ -739,19 +773,8 @@ type listOrdersResponse struct {
 //   - cancelled
 //   - expired
 //   - failed
-func (u *User) nextOrderHistoryPage(cursor, orderType, orderStatus string) (*listOrdersResponse, error) {
-	url := urlListOrderHistory
-	if cursor != "" {
-		url = url + "?cursor=" + cursor
-	}
-	if orderType != "" {
-		url = url + "?type=" + orderType
-	}
-	if orderStatus != "" {
-		url = url + "?status=" + orderStatus
-	}
-
-	httpResponse, err := u.sendPaymentRequest("GET", url, nil)
+func (u *User) orderHistoryPage(ctx context.Context, cursor, orderType, orderStatus string) (*listOrdersResponse, error) {
+	httpResponse, err := u.sendPaymentRequest(ctx, "GET", orderHistoryURL(cursor, orderType, orderStatus), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -761,28 +784,39 @@ func (u *User) nextOrderHistoryPage(cursor, orderType, orderStatus string) (*lis
 		State   int                `json:"state"`
 		Result  listOrdersResponse `json:"result"`
 		Message string             `json:"message"`
+		Errors  responseErrors     `json:"errors"`
 		Code    int                `json:"code"`
 		Error   string             `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := u.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
+}
+
+// orderHistoryURL builds the order list URL with the optional cursor and filters in a single query string.
+func orderHistoryURL(cursor, orderType, orderStatus string) string {
+	query := url.Values{}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	if orderType != "" {
+		query.Set("type", orderType)
+	}
+	if orderStatus != "" {
+		query.Set("status", orderStatus)
+	}
 
+	if len(query) == 0 {
+		return urlListOrderHistory
+	}
+	return urlListOrderHistory + "?" + query.Encode()
 }
 
 // See "Get orders list" https://doc.cryptomus.com/personal/converts/orders-list
@@ -819,55 +853,41 @@ func (u *User) nextOrderHistoryPage(cursor, orderType, orderStatus string) (*lis
 //	  }
 //	}
 func (u *User) ListOrderHistory(orderType, orderStatus string) ([]MarketOrder, error) {
-	url := urlListOrderHistory
-	if orderType != "" {
-		url = url + "?type=" + orderType
-	}
-	if orderStatus != "" {
-		url = url + "?status=" + orderStatus
-	}
-
-	httpResponse, err := u.sendPaymentRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer httpResponse.Body.Close()
-
-	var response = struct {
-		State   int                `json:"state"`
-		Result  listOrdersResponse `json:"result"`
-		Message string             `json:"message"`
-		Code    int                `json:"code"`
-		Error   string             `json:"error"`
-	}{}
+	return u.ListOrderHistoryContext(context.Background(), orderType, orderStatus)
+}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
-	}
+// ListOrderHistoryContext is like ListOrderHistory but sends every page request with the provided context.
+func (u *User) ListOrderHistoryContext(ctx context.Context, orderType, orderStatus string) ([]MarketOrder, error) {
+	return collectHistory(u.OrderHistorySeq(ctx, orderType, orderStatus))
+}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
+// ListOrderHistoryPage returns the single page of orders at cursor, or the first page if cursor is empty, with the cursors of the next and previous pages, e.g. for incremental loading in a UI.
+//
+// next or prev is empty if there is no such page. Pass the same orderType and orderStatus with every cursor; an empty one does not filter.
+//
+// See "Get orders list" https://doc.cryptomus.com/personal/converts/orders-list
+func (u *User) ListOrderHistoryPage(orderType, orderStatus, cursor string) (orders []MarketOrder, next, prev string, err error) {
+	return u.ListOrderHistoryPageContext(context.Background(), orderType, orderStatus, cursor)
+}
 
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+// ListOrderHistoryPageContext is like ListOrderHistoryPage but sends the request with the provided context.
+func (u *User) ListOrderHistoryPageContext(ctx context.Context, orderType, orderStatus, cursor string) (orders []MarketOrder, next, prev string, err error) {
+	page, err := u.orderHistoryPage(ctx, cursor, orderType, orderStatus)
+	if err != nil {
+		return nil, "", "", err
 	}
+	return page.Items, page.Paginate.NextCursor, page.Paginate.PreviousCursor, nil
+}
 
-	var orders []MarketOrder
-	orders = append(orders, response.Result.Items...)
-	page := &response.Result
-	for page.Paginate.NextCursor != "" {
-		page, err = u.nextOrderHistoryPage(page.Paginate.NextCursor, orderType, orderStatus)
+// OrderHistorySeq is like ListOrderHistoryContext but fetches the pages lazily, one request per page, and yields the orders as they arrive.
+//
+// The next page is only requested once every order of the current page has been yielded, so breaking out of the loop stops paging. An error is yielded once, with a zero MarketOrder, and ends the sequence.
+func (u *User) OrderHistorySeq(ctx context.Context, orderType, orderStatus string) iter.Seq2[MarketOrder, error] {
+	return historySeq(ctx, &u.options, "orders history", func(cursor string) ([]MarketOrder, string, error) {
+		page, err := u.orderHistoryPage(ctx, cursor, orderType, orderStatus)
 		if err != nil {
-			return nil, fmt.Errorf("error paging orders history: %w", err)
+			return nil, "", err
 		}
-		if page != nil {
-			orders = append(orders, page.Items...)
-		}
-	}
-	return orders, nil
+		return page.Items, page.Paginate.NextCursor, nil
+	})
 }
@@ -0,0 +1,492 @@
+package cryptomus_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+const paymentHistoryFixture = `{
+	"state": 0,
+	"result": {
+		"items": [{
+			"uuid": "ac1af391-8e98-4335-b9d7-7b6f6b40f268",
+			"order_id": "20fe59c4601dd174985e497e3f6bbcd2",
+			"amount": "20.00",
+			"payment_amount": "0.00000000",
+			"payer_amount": "0.00064860",
+			"discount_percent": 0,
+			"discount": "0.00000000",
+			"payer_currency": "BTC",
+			"currency": "USD",
+			"merchant_amount": null,
+			"comments": null,
+			"network": "btc",
+			"address": "bc1qxm6ehuy6mz2l2h3ag88frcjvl2xxlr9hvnq835",
+			"from": null,
+			"txid": null,
+			"payment_status": "cancel",
+			"url": "https://pay.cryptomus.com/pay/ac1af391-8e98-4335-b9d7-7b6f6b40f268",
+			"expired_at": 1689172103,
+			"status": "cancel",
+			"is_final": true,
+			"additional_data": null,
+			"created_at": "2023-07-12T16:28:24+03:00",
+			"updated_at": "2023-07-12T17:30:16+03:00"
+		}, {
+			"uuid": "1bb48358-2905-4e98-b681-5f1948e818d1",
+			"order_id": "a3329f462eb036dad12b5409147809a3",
+			"amount": "15.00",
+			"payment_amount": "0.00",
+			"payer_amount": "14.25",
+			"discount_percent": 5,
+			"discount": "0.75",
+			"payer_currency": "USDT",
+			"currency": "USDT",
+			"merchant_amount": "15.43500000",
+			"comments": null,
+			"network": "tron",
+			"address": "TSChodGNEJ6D31d9uueFxJAVH9NxiJjTwC",
+			"from": null,
+			"txid": null,
+			"payment_status": "cancel",
+			"url": "https://pay.cryptomus.com/pay/1bb48358-2905-4e98-b681-5f1948e818d1",
+			"expired_at": 1689099958,
+			"status": "cancel",
+			"is_final": true,
+			"additional_data": null,
+			"created_at": "2023-07-11T20:25:58+03:00",
+			"updated_at": "2023-07-11T21:26:18+03:00"
+		}, {
+			"uuid": "70b8db5c-b952-406d-af26-4e1c34c27f15",
+			"order_id": "65bbe87b4098c17a31cff3e71e515243",
+			"amount": "15.00",
+			"payer_amount": "15.75",
+			"discount_percent": -5,
+			"discount": "-0.75",
+			"payer_currency": "USDT",
+			"currency": "USDT",
+			"network": "tron",
+			"address": "TXhfYSWt2oKRrHAJVJeYRuit6ZzKuoEKXj",
+			"from": "TQeuG8yrGTZKUYQqvXW6DjMfMGbHGYoVdm",
+			"txid": "d7f4c8c6d4d7b1a1d2b8f1c9e6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7",
+			"payment_status": "paid",
+			"url": "https://pay.cryptomus.com/pay/70b8db5c-b952-406d-af26-4e1c34c27f15",
+			"expired_at": 1689099831,
+			"status": "paid",
+			"is_final": true,
+			"additional_data": "customer #42",
+			"created_at": "2023-07-11T20:23:52+03:00",
+			"updated_at": "2023-07-11T21:24:17+03:00"
+		}],
+		"paginate": {
+			"count": 3,
+			"hasPages": false,
+			"nextCursor": null,
+			"previousCursor": null,
+			"perPage": 15
+		}
+	}
+}`
+
+func TestListPaymentHistoryFixture(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(paymentHistoryFixture))
+	}))
+	defer server.Close()
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+	payments, err := merchant.ListPaymentHistory(cryptomus.HistoryRequest{})
+	if err != nil {
+		t.Fatalf("error listing payment history: %v", err)
+	}
+	if len(payments) != 3 {
+		t.Fatalf("got %d payments, want 3", len(payments))
+	}
+
+	first := payments[0]
+	if first.MerchantAmount != nil || first.Comments != nil || first.From != nil || first.TxID != nil || first.AdditionalData != nil {
+		t.Errorf("expected null fields to decode as nil, got %+v", first)
+	}
+	if first.PayerAmount != "0.00064860" || first.Network != "btc" || !first.IsFinal {
+		t.Errorf("unexpected payment %+v", first)
+	}
+
+	if second := payments[1]; second.MerchantAmount == nil || *second.MerchantAmount != "15.43500000" || second.DiscountPercent != 5 {
+		t.Errorf("unexpected payment %+v", second)
+	}
+
+	third := payments[2]
+	if third.PaymentAmount != "" || third.MerchantAmount != nil {
+		t.Errorf("expected omitted fields to be empty, got %+v", third)
+	}
+	if third.From == nil || *third.From != "TQeuG8yrGTZKUYQqvXW6DjMfMGbHGYoVdm" || !third.HasTxID() {
+		t.Errorf("unexpected payment %+v", third)
+	}
+	if third.AdditionalData == nil || *third.AdditionalData != "customer #42" {
+		t.Errorf("unexpected additional data %v", third.AdditionalData)
+	}
+	if third.CreatedAt.IsZero() {
+		t.Error("created_at was not decoded")
+	}
+}
+
+func TestListPaymentHistoryPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"state": 0, "result": {"items": [{"uuid": "first"}], "paginate": {"nextCursor": "next"}}}`))
+			return
+		}
+		w.Write([]byte(`{"state": 0, "result": {"items": [{"uuid": "second"}], "paginate": {"nextCursor": null}}}`))
+	}))
+	defer server.Close()
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+	payments, err := merchant.ListPaymentHistory(cryptomus.HistoryRequest{})
+	if err != nil {
+		t.Fatalf("error listing payment history: %v", err)
+	}
+	if len(payments) != 2 || payments[0].UUID != "first" || payments[1].UUID != "second" {
+		t.Errorf("unexpected payments %+v", payments)
+	}
+}
+
+func TestListPaymentHistoryPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cursor := r.URL.Query().Get("cursor"); cursor != "eyJpZCI6Mn0=" {
+			t.Errorf("got cursor %q", cursor)
+		}
+		w.Write([]byte(`{"state": 0, "result": {"items": [{"uuid": "second"}], "paginate": {"count": 1, "hasPages": true, "nextCursor": "eyJpZCI6M30=", "previousCursor": "eyJpZCI6MX0=", "perPage": 1}}}`))
+	}))
+	defer server.Close()
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+	payments, next, prev, err := merchant.ListPaymentHistoryPage(cryptomus.HistoryRequest{}, "eyJpZCI6Mn0=")
+	if err != nil {
+		t.Fatalf("error listing payment history page: %v", err)
+	}
+	if len(payments) != 1 || payments[0].UUID != "second" {
+		t.Errorf("unexpected payments %+v", payments)
+	}
+	if next != "eyJpZCI6M30=" || prev != "eyJpZCI6MX0=" {
+		t.Errorf("got cursors next %q and previous %q", next, prev)
+	}
+}
+
+func TestPaymentHistorySeqStopsEarly(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"state": 0, "result": {"items": [{"uuid": "first"}, {"uuid": "second"}], "paginate": {"nextCursor": "next"}}}`))
+	}))
+	defer server.Close()
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+
+	var uuids []string
+	for payment, err := range merchant.PaymentHistorySeq(context.Background(), cryptomus.HistoryRequest{}) {
+		if err != nil {
+			t.Fatalf("error listing payment history: %v", err)
+		}
+		uuids = append(uuids, payment.UUID)
+		if len(uuids) == 2 {
+			break
+		}
+	}
+
+	if len(uuids) != 2 || uuids[0] != "first" || uuids[1] != "second" {
+		t.Errorf("unexpected payments %v", uuids)
+	}
+	if requests != 1 {
+		t.Errorf("got %d requests, want 1", requests)
+	}
+}
+
+// newPagedServer serves two pages of the given items, the second one at cursor "next", and counts the requests.
+func newPagedServer(t *testing.T, first, second string) (*httptest.Server, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"state": 0, "result": {"items": [` + first + `], "paginate": {"nextCursor": "next"}}}`))
+			return
+		}
+		w.Write([]byte(`{"state": 0, "result": {"items": [` + second + `], "paginate": {"nextCursor": null}}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestPayoutHistorySeq(t *testing.T) {
+	server, requests := newPagedServer(t, `{"uuid": "first"}, {"uuid": "second"}`, `{"uuid": "third"}`)
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+
+	var uuids []string
+	for payout, err := range merchant.PayoutHistorySeq(context.Background(), cryptomus.HistoryRequest{}) {
+		if err != nil {
+			t.Fatalf("error listing payout history: %v", err)
+		}
+		uuids = append(uuids, payout.UUID)
+	}
+	if len(uuids) != 3 || uuids[2] != "third" || *requests != 2 {
+		t.Errorf("got %v in %d requests", uuids, *requests)
+	}
+
+	*requests = 0
+	for range merchant.PayoutHistorySeq(context.Background(), cryptomus.HistoryRequest{}) {
+		break
+	}
+	if *requests != 1 {
+		t.Errorf("got %d requests after breaking on the first payout, want 1", *requests)
+	}
+}
+
+func TestListHistoryContextCancellation(t *testing.T) {
+	tests := map[string]func(*cryptomus.Merchant, context.Context) error{
+		"payments": func(m *cryptomus.Merchant, ctx context.Context) error {
+			_, err := m.ListPaymentHistoryContext(ctx, cryptomus.HistoryRequest{})
+			return err
+		},
+		"payouts": func(m *cryptomus.Merchant, ctx context.Context) error {
+			_, err := m.ListPayoutHistoryContext(ctx, cryptomus.HistoryRequest{})
+			return err
+		},
+		"recurring payments": func(m *cryptomus.Merchant, ctx context.Context) error {
+			_, err := m.ListRecurringPaymentsContext(ctx)
+			return err
+		},
+		"recurring payments with paginate": func(m *cryptomus.Merchant, ctx context.Context) error {
+			_, _, err := m.ListRecurringPaymentsWithPaginateContext(ctx)
+			return err
+		},
+	}
+
+	for name, list := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// The first page cancels the context, so the second one must not be requested.
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				cancel()
+				w.Write([]byte(`{"state": 0, "result": {"items": [{"uuid": "first"}], "paginate": {"nextCursor": "next"}}}`))
+			}))
+			defer server.Close()
+
+			merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+			if err := list(merchant, ctx); !errors.Is(err, context.Canceled) {
+				t.Errorf("got error %v, want context.Canceled", err)
+			}
+			if requests != 1 {
+				t.Errorf("got %d requests, want 1", requests)
+			}
+		})
+	}
+}
+
+func TestRecurringPaymentsSeq(t *testing.T) {
+	server, requests := newPagedServer(t, `{"uuid": "first"}`, `{"uuid": "second"}`)
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+
+	var uuids []string
+	for recurringPayment, err := range merchant.RecurringPaymentsSeq(context.Background()) {
+		if err != nil {
+			t.Fatalf("error listing recurring payments: %v", err)
+		}
+		uuids = append(uuids, recurringPayment.UUID)
+	}
+	if len(uuids) != 2 || uuids[1] != "second" || *requests != 2 {
+		t.Errorf("got %v in %d requests", uuids, *requests)
+	}
+
+	*requests = 0
+	for range merchant.RecurringPaymentsSeq(context.Background()) {
+		break
+	}
+	if *requests != 1 {
+		t.Errorf("got %d requests after breaking on the first recurring payment, want 1", *requests)
+	}
+}
+
+func TestListRecurringPaymentsWithPaginate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"state": 0, "result": {"items": [{"uuid": "first"}], "paginate": {"count": 1, "hasPages": true, "nextCursor": "next", "previousCursor": null, "perPage": 1}}}`))
+			return
+		}
+		w.Write([]byte(`{"state": 0, "result": {"items": [{"uuid": "second"}], "paginate": {"count": 1, "hasPages": true, "nextCursor": null, "previousCursor": "prev", "perPage": 1}}}`))
+	}))
+	defer server.Close()
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+	recurringPayments, paginate, err := merchant.ListRecurringPaymentsWithPaginate()
+	if err != nil {
+		t.Fatalf("error listing recurring payments: %v", err)
+	}
+	if len(recurringPayments) != 2 || recurringPayments[1].UUID != "second" {
+		t.Errorf("unexpected recurring payments %+v", recurringPayments)
+	}
+	if paginate == nil || paginate.Count != 1 || paginate.PerPage != 1 || !paginate.HasPages || paginate.NextCursor != "next" || paginate.PreviousCursor != "" {
+		t.Errorf("unexpected paginate %+v", paginate)
+	}
+}
+
+func TestOrderHistorySeq(t *testing.T) {
+	server, requests := newPagedServer(t, `{"order_id": 1}`, `{"order_id": "2"}`)
+	user := cryptomus.NewUser("user", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+
+	var orderIDs []string
+	for order, err := range user.OrderHistorySeq(context.Background(), "market", "") {
+		if err != nil {
+			t.Fatalf("error listing order history: %v", err)
+		}
+		orderIDs = append(orderIDs, order.OrderID)
+	}
+	if len(orderIDs) != 2 || orderIDs[0] != "1" || orderIDs[1] != "2" || *requests != 2 {
+		t.Errorf("got %v in %d requests", orderIDs, *requests)
+	}
+
+	*requests = 0
+	for range user.OrderHistorySeq(context.Background(), "market", "") {
+		break
+	}
+	if *requests != 1 {
+		t.Errorf("got %d requests after breaking on the first order, want 1", *requests)
+	}
+}
+
+func TestListOrderHistoryPage(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"state": 0, "result": {"items": [{"order_id": 49347}], "paginate": {"count": 1, "hasPages": true, "nextCursor": "eyJpZCI6M30=", "previousCursor": "eyJpZCI6MX0=", "perPage": 1}}}`))
+	}))
+	defer server.Close()
+
+	user := cryptomus.NewUser("user", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+
+	tests := []struct {
+		name, orderType, orderStatus, cursor string
+		want                                 url.Values
+	}{
+		{"no filters", "", "", "", url.Values{}},
+		{"type", "limit", "", "", url.Values{"type": {"limit"}}},
+		{"status", "", "active", "", url.Values{"status": {"active"}}},
+		{"type and status", "limit", "active", "", url.Values{"type": {"limit"}, "status": {"active"}}},
+		{"cursor", "", "", "eyJpZCI6Mn0=", url.Values{"cursor": {"eyJpZCI6Mn0="}}},
+		{"type, status and cursor", "market", "completed", "eyJpZCI6Mn0=", url.Values{"type": {"market"}, "status": {"completed"}, "cursor": {"eyJpZCI6Mn0="}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			orders, next, prev, err := user.ListOrderHistoryPage(test.orderType, test.orderStatus, test.cursor)
+			if err != nil {
+				t.Fatalf("error listing order history page: %v", err)
+			}
+			if !reflect.DeepEqual(query, test.want) {
+				t.Errorf("got query %v, want %v", query, test.want)
+			}
+			if len(orders) != 1 || orders[0].OrderID != "49347" {
+				t.Errorf("unexpected orders %+v", orders)
+			}
+			if next != "eyJpZCI6M30=" || prev != "eyJpZCI6MX0=" {
+				t.Errorf("got cursors next %q and previous %q", next, prev)
+			}
+		})
+	}
+}
+
+func TestOrderHistorySeqPageError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"state": 0, "result": {"items": [{"order_id": 1}], "paginate": {"nextCursor": "next"}}}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"message": "Server error, #1", "code": 500}`))
+	}))
+	defer server.Close()
+	user := cryptomus.NewUser("user", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+
+	var orders, errs int
+	for _, err := range user.OrderHistorySeq(context.Background(), "", "") {
+		if err != nil {
+			errs++
+			if !cryptomus.IsServerError(err) {
+				t.Errorf("unexpected error %v", err)
+			}
+			continue
+		}
+		orders++
+	}
+	if orders != 1 || errs != 1 {
+		t.Errorf("got %d orders and %d errors, want 1 and 1", orders, errs)
+	}
+}
+
+func TestNewHistoryRange(t *testing.T) {
+	from := time.Date(2023, 5, 3, 21, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 5, 16, 23, 59, 59, 0, time.FixedZone("UTC+3", 3*60*60))
+
+	request, err := cryptomus.NewHistoryRange(from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if request.DateFrom == nil || *request.DateFrom != "2023-05-04 00:00:00" {
+		t.Errorf("got date_from %v, want 2023-05-04 00:00:00", request.DateFrom)
+	}
+	if request.DateTo == nil || *request.DateTo != "2023-05-16 23:59:59" {
+		t.Errorf("got date_to %v, want 2023-05-16 23:59:59", request.DateTo)
+	}
+
+	request, err = cryptomus.NewHistoryRange(time.Time{}, to)
+	if err != nil || request.DateFrom != nil || request.DateTo == nil {
+		t.Errorf("expected an open start, got %+v, %v", request, err)
+	}
+
+	if _, err := cryptomus.NewHistoryRange(to, from.Add(-time.Hour)); err == nil {
+		t.Error("expected error when from is after to")
+	}
+}
+
+func TestWithMaxHistoryPages(t *testing.T) {
+	server, requests := newPagedServer(t, `{"uuid": "first"}`, `{"uuid": "second"}`)
+
+	limited := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL), cryptomus.WithMaxHistoryPages(1))
+	payments, err := limited.ListPaymentHistory(cryptomus.HistoryRequest{})
+	if !errors.Is(err, cryptomus.ErrTruncated) {
+		t.Fatalf("got error %v, want ErrTruncated", err)
+	}
+	if len(payments) != 1 || payments[0].UUID != "first" || *requests != 1 {
+		t.Errorf("got %+v in %d requests, want the first page only", payments, *requests)
+	}
+
+	*requests = 0
+	var uuids []string
+	var seqErr error
+	for payout, err := range limited.PayoutHistorySeq(context.Background(), cryptomus.HistoryRequest{}) {
+		if err != nil {
+			seqErr = err
+			break
+		}
+		uuids = append(uuids, payout.UUID)
+	}
+	if !errors.Is(seqErr, cryptomus.ErrTruncated) || len(uuids) != 1 || *requests != 1 {
+		t.Errorf("got %v and error %v in %d requests, want the first page and ErrTruncated", uuids, seqErr, *requests)
+	}
+
+	*requests = 0
+	enough := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL), cryptomus.WithMaxHistoryPages(2))
+	recurringPayments, err := enough.ListRecurringPayments()
+	if err != nil || len(recurringPayments) != 2 || *requests != 2 {
+		t.Errorf("got %d recurring payments and error %v in %d requests, want 2, nil and 2", len(recurringPayments), err, *requests)
+	}
+}
@@ -1,9 +1,9 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
+	"math/big"
 	"strings"
 )
 
@@ -39,34 +39,32 @@ type Service struct {
 //
 // See "List of services" https://doc.cryptomus.com/business/payments/list-of-services
 func (m *Merchant) ListPaymentServices() ([]Service, error) {
-	httpResponse, err := m.sendPaymentRequest("POST", urlListPaymentServices, nil)
+	return m.ListPaymentServicesContext(context.Background())
+}
+
+// ListPaymentServicesContext is like ListPaymentServices but sends the request with the provided context.
+func (m *Merchant) ListPaymentServicesContext(ctx context.Context) ([]Service, error) {
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlListPaymentServices, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer httpResponse.Body.Close()
 
 	var response = struct {
-		State   int       `json:"state"`
-		Result  []Service `json:"result"`
-		Message string    `json:"message"`
-		Code    int       `json:"code"`
-		Error   string    `json:"error"`
+		State   int            `json:"state"`
+		Result  []Service      `json:"result"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return response.Result, nil
@@ -80,35 +78,126 @@ func (m *Merchant) ListPaymentServices() ([]Service, error) {
 //
 // See "List of services" https://doc.cryptomus.com/business/payouts/list-of-services
 func (m *Merchant) ListPayoutServices() ([]Service, error) {
-	httpResponse, err := m.sendPayoutRequest("POST", urlListPayoutServices, nil)
+	return m.ListPayoutServicesContext(context.Background())
+}
+
+// ListPayoutServicesContext is like ListPayoutServices but sends the request with the provided context.
+func (m *Merchant) ListPayoutServicesContext(ctx context.Context) ([]Service, error) {
+	httpResponse, err := m.sendPayoutRequest(ctx, "POST", urlListPayoutServices, nil)
 	if err != nil {
 		return nil, err
 	}
 	defer httpResponse.Body.Close()
 
 	var response = struct {
-		State   int       `json:"state"`
-		Result  []Service `json:"result"`
-		Message string    `json:"message"`
-		Code    int       `json:"code"`
-		Error   string    `json:"error"`
+		State   int            `json:"state"`
+		Result  []Service      `json:"result"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
+	}
+
+	return response.Result, nil
+}
+
+// MinAmountDecimal returns the minimum amount limit as an exact decimal, or nil if it is empty.
+func (s Service) MinAmountDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(s.Limit.MinAmount)
+}
+
+// MaxAmountDecimal returns the maximum amount limit as an exact decimal, or nil if it is empty.
+func (s Service) MaxAmountDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(s.Limit.MaxAmount)
+}
+
+// FeeAmountDecimal returns the fixed fee amount of the commission as an exact decimal, or nil if it is empty.
+func (s Service) FeeAmountDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(s.Commission.FeeAmount)
+}
+
+// FeePercentDecimal returns the percentage of the commission as an exact decimal, or nil if it is empty.
+func (s Service) FeePercentDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(s.Commission.Percent)
+}
+
+// SupportsAmount reports whether amount is within the minimum and maximum amount of the service, limits included. An empty limit does not restrict the amount.
+//
+// It returns an error if amount or a limit is not a decimal number. It does not check IsAvailable.
+func (s Service) SupportsAmount(amount string) (bool, error) {
+	value, err := parseDecimal(amount)
+	if err != nil {
+		return false, err
+	}
+	minimum, err := s.MinAmountDecimal()
+	if err != nil {
+		return false, fmt.Errorf("error parsing min_amount of %s %s: %w", s.Currency, s.Network, err)
+	}
+	maximum, err := s.MaxAmountDecimal()
+	if err != nil {
+		return false, fmt.Errorf("error parsing max_amount of %s %s: %w", s.Currency, s.Network, err)
+	}
+
+	if minimum != nil && value.Cmp(minimum) < 0 {
+		return false, nil
+	}
+	if maximum != nil && value.Cmp(maximum) > 0 {
+		return false, nil
 	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
+	return true, nil
+}
+
+// PayoutOption is a network a payout in some currency can be sent through, with its fee and limits.
+type PayoutOption struct {
+	// Blockchain network code
+	Network string
+	// Fixed fee amount
+	FeeAmount string
+	// Percentage of Cryptomus payout commission
+	FeePercent string
+	// Minimum amount available for payout
+	MinAmount string
+	// Maximum amount available for payout
+	MaxAmount string
+}
+
+// PayoutNetworkOptions returns the networks a payout in currency can currently use, with their fees and limits, e.g. to let a user pick the cheapest network for USDT.
+//
+// The options are built from the available services of ListPayoutServices, in the order Cryptomus lists them. The currency is matched case-insensitively.
+//
+// See "List of services" https://doc.cryptomus.com/business/payouts/list-of-services
+func (m *Merchant) PayoutNetworkOptions(currency string) ([]PayoutOption, error) {
+	return m.PayoutNetworkOptionsContext(context.Background(), currency)
+}
+
+// PayoutNetworkOptionsContext is like PayoutNetworkOptions but sends the request with the provided context.
+func (m *Merchant) PayoutNetworkOptionsContext(ctx context.Context, currency string) ([]PayoutOption, error) {
+	services, err := m.ListPayoutServicesContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	var payoutOptions []PayoutOption
+	for _, service := range services {
+		if !service.IsAvailable || !strings.EqualFold(service.Currency, currency) {
+			continue
+		}
+		payoutOptions = append(payoutOptions, PayoutOption{
+			Network:    service.Network,
+			FeeAmount:  service.Commission.FeeAmount,
+			FeePercent: service.Commission.Percent,
+			MinAmount:  service.Limit.MinAmount,
+			MaxAmount:  service.Limit.MaxAmount,
+		})
 	}
 
-	return response.Result, nil
+	return payoutOptions, nil
 }
@@ -0,0 +1,91 @@
+package cryptomus_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestPayoutNetworkOptions(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/payout/services" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"state": 0, "result": [
+			{"network": "TRON", "currency": "USDT", "is_available": true, "limit": {"min_amount": "10.00000000", "max_amount": "10000000.00000000"}, "commission": {"fee_amount": "1.00", "percent": "0.00"}},
+			{"network": "ETH", "currency": "USDT", "is_available": false, "limit": {"min_amount": "20.00000000", "max_amount": "10000000.00000000"}, "commission": {"fee_amount": "5.00", "percent": "0.00"}},
+			{"network": "BSC", "currency": "USDT", "is_available": true, "limit": {"min_amount": "5.00000000", "max_amount": "10000000.00000000"}, "commission": {"fee_amount": "0.30", "percent": "1.00"}},
+			{"network": "TRON", "currency": "TRX", "is_available": true, "limit": {"min_amount": "1.00000000", "max_amount": "10000000.00000000"}, "commission": {"fee_amount": "1.00", "percent": "0.00"}}
+		]}`))
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	got, err := merchant.PayoutNetworkOptions("usdt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []cryptomus.PayoutOption{
+		{Network: "TRON", FeeAmount: "1.00", FeePercent: "0.00", MinAmount: "10.00000000", MaxAmount: "10000000.00000000"},
+		{Network: "BSC", FeeAmount: "0.30", FeePercent: "1.00", MinAmount: "5.00000000", MaxAmount: "10000000.00000000"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestServiceLimits(t *testing.T) {
+	var services []cryptomus.Service
+	body := `[
+		{"network": "TRON", "currency": "USDT", "is_available": true, "limit": {"min_amount": "1.00000000", "max_amount": "10000000.00000000"}, "commission": {"fee_amount": "0.00", "percent": "0.00"}},
+		{"network": "btc", "currency": "BTC", "is_available": true, "limit": {"min_amount": "0.00001000", "max_amount": "1000000.00000000"}, "commission": {"fee_amount": "0.00000000", "percent": "1.50"}},
+		{"network": "bsc", "currency": "BNB", "is_available": true, "limit": {"min_amount": "", "max_amount": "bad"}, "commission": {"fee_amount": "", "percent": ""}}
+	]`
+	if err := json.Unmarshal([]byte(body), &services); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	usdt, btc, bnb := services[0], services[1], services[2]
+
+	if minimum, err := btc.MinAmountDecimal(); err != nil || minimum.Cmp(big.NewRat(1, 100000)) != 0 {
+		t.Errorf("got min amount %v, %v, want 0.00001", minimum, err)
+	}
+	if maximum, err := usdt.MaxAmountDecimal(); err != nil || maximum.Cmp(big.NewRat(10000000, 1)) != 0 {
+		t.Errorf("got max amount %v, %v, want 10000000", maximum, err)
+	}
+	if percent, err := btc.FeePercentDecimal(); err != nil || percent.Cmp(big.NewRat(3, 2)) != 0 {
+		t.Errorf("got fee percent %v, %v, want 1.5", percent, err)
+	}
+	if fee, err := bnb.FeeAmountDecimal(); err != nil || fee != nil {
+		t.Errorf("got fee amount %v, %v, want nil for an empty fee", fee, err)
+	}
+
+	tests := []struct {
+		service cryptomus.Service
+		amount  string
+		want    bool
+	}{
+		{usdt, "1", true},
+		{usdt, "0.99999999", false},
+		{usdt, "10000000.00000000", true},
+		{usdt, "10000000.00000001", false},
+		{btc, "0.00001", true},
+		{btc, "0.00000999", false},
+	}
+	for _, test := range tests {
+		got, err := test.service.SupportsAmount(test.amount)
+		if err != nil || got != test.want {
+			t.Errorf("%s SupportsAmount(%s) = %v, %v, want %v", test.service.Currency, test.amount, got, err, test.want)
+		}
+	}
+
+	if _, err := usdt.SupportsAmount("1,5"); err == nil {
+		t.Error("expected error for a malformed amount")
+	}
+	if _, err := bnb.SupportsAmount("1"); err == nil {
+		t.Error("expected error for a malformed limit")
+	}
+}
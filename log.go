@@ -0,0 +1,78 @@
+package cryptomus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// redacted replaces the API keys in logged text.
+const redacted = "[REDACTED]"
+
+// WithLogger logs every request and response at debug level to logger: the method, URL, signed body and sign of requests, and the status and body of responses.
+//
+// It is meant for diagnosing signature mismatches and validation errors. The API keys given to NewMerchant or NewUser are replaced with [REDACTED] wherever they appear; the sign is logged as is, since the keys cannot be recovered from it.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// logRequest logs a request about to be sent if a logger is set.
+func (o *options) logRequest(ctx context.Context, request *http.Request, body []byte) {
+	if o.logger == nil {
+		return
+	}
+	o.logger.DebugContext(ctx, "cryptomus request",
+		slog.String("method", request.Method),
+		slog.String("url", request.URL.String()),
+		slog.String("body", o.redact(string(body))),
+		slog.String("sign", request.Header.Get("sign")),
+	)
+}
+
+// logResponse logs the response to a request, or the error that prevented it, if a logger is set.
+//
+// It reads the whole body and replaces it with a buffered copy, so the response can still be decoded.
+func (o *options) logResponse(ctx context.Context, request *http.Request, response *http.Response, err error) error {
+	if o.logger == nil {
+		return nil
+	}
+	if err != nil {
+		o.logger.DebugContext(ctx, "cryptomus request failed",
+			slog.String("method", request.Method),
+			slog.String("url", request.URL.String()),
+			slog.String("error", o.redact(err.Error())),
+		)
+		return nil
+	}
+
+	body, readErr := io.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	if readErr != nil {
+		return fmt.Errorf("error reading response body: %w", readErr)
+	}
+
+	o.logger.DebugContext(ctx, "cryptomus response",
+		slog.String("method", request.Method),
+		slog.String("url", request.URL.String()),
+		slog.Int("status", response.StatusCode),
+		slog.String("body", o.redact(string(body))),
+	)
+	return nil
+}
+
+// redact replaces the API keys in s.
+func (o *options) redact(s string) string {
+	for _, secret := range o.secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+	return s
+}
@@ -0,0 +1,43 @@
+package cryptomus_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestWithLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"state": 0, "result": {"uuid": "26109ba0-b05b-4ee0-93d1-fd62c822ce95", "order_id": "1", "amount": "15"}}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	merchant := cryptomus.NewMerchant("merchant", "secret-payment-key", "secret-payout-key", cryptomus.WithBaseURL(server.URL), cryptomus.WithLogger(logger))
+
+	// The key ends up in the body only by mistake, but must not be logged even then.
+	additionalData := "secret-payment-key"
+	payment, err := merchant.CreateInvoice(cryptomus.Invoice{Amount: "15", Currency: "USD", OrderID: "1", AdditionalData: &additionalData})
+	if err != nil {
+		t.Fatalf("error creating invoice: %v", err)
+	}
+	if payment.UUID != "26109ba0-b05b-4ee0-93d1-fd62c822ce95" {
+		t.Errorf("response was not decoded after logging, got %+v", payment)
+	}
+
+	output := logs.String()
+	if strings.Contains(output, "secret-payment-key") || strings.Contains(output, "secret-payout-key") {
+		t.Errorf("logs contain an API key:\n%s", output)
+	}
+	for _, want := range []string{"cryptomus request", "v1/payment", `\"order_id\":\"1\"`, "[REDACTED]", "cryptomus response", "status=200", "26109ba0"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("logs do not contain %q:\n%s", want, output)
+		}
+	}
+}
@@ -1,6 +1,18 @@
 package cryptomus
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// orderTimeLayout is the layout of convert order timestamps, e.g. "2024-03-25 , 11:24:55".
+const orderTimeLayout = "2006-01-02 , 15:04:05"
+
+// cryptomusLocation is the UTC+3 timezone Cryptomus reports its timestamps in.
+var cryptomusLocation = time.FixedZone("UTC+3", 3*60*60)
 
 // See "Create market order" https://doc.cryptomus.com/personal/converts/market-order
 //
@@ -27,15 +39,17 @@ import "time"
 //	}
 type MarketOrder struct {
 	// Id of convert
+	//
+	// The order list returns numeric ids, which are decoded into their decimal string form.
 	OrderID string `json:"order_id"`
 	// Convert amount from
 	ConvertAmountFrom string `json:"convert_amount_from"`
 	// Convert amount to
 	ConvertAmountTo string `json:"convert_amount_to"`
-	// Executed amount to
-	ExecutedAmountTo string `json:"executed_amount_to"`
-	// Executed amount from
-	ExecutedAmountFrom string `json:"executed_amount_from"`
+	// Executed amount to, null until the order is executed
+	ExecutedAmountTo *string `json:"executed_amount_to"`
+	// Executed amount from, null until the order is executed
+	ExecutedAmountFrom *string `json:"executed_amount_from"`
 	// Convert currency from
 	ConvertCurrencyFrom string `json:"convert_currency_from"`
 	// Convert currency to
@@ -67,3 +81,95 @@ type MarketOrder struct {
 	// Date time when order completed (only if order completed)
 	CompletedAt time.Time `json:"completed_at"`
 }
+
+// UnmarshalJSON decodes a convert order, accepting numeric order ids and the "2024-03-25 , 11:24:55" timestamp format.
+func (o *MarketOrder) UnmarshalJSON(data []byte) error {
+	type marketOrder MarketOrder
+	aux := struct {
+		OrderID     json.RawMessage `json:"order_id"`
+		CreatedAt   *string         `json:"created_at"`
+		ExpiresAt   *string         `json:"expires_at"`
+		CompletedAt *string         `json:"completed_at"`
+		*marketOrder
+	}{
+		marketOrder: (*marketOrder)(o),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	orderID := strings.TrimSpace(string(aux.OrderID))
+	switch {
+	case orderID == "" || orderID == "null":
+		o.OrderID = ""
+	case strings.HasPrefix(orderID, `"`):
+		if err := json.Unmarshal(aux.OrderID, &o.OrderID); err != nil {
+			return fmt.Errorf("error decoding order_id: %w", err)
+		}
+	default:
+		o.OrderID = orderID
+	}
+
+	var err error
+	if o.CreatedAt, err = parseOrderTime(aux.CreatedAt); err != nil {
+		return fmt.Errorf("error parsing created_at: %w", err)
+	}
+	if o.ExpiresAt, err = parseOrderTime(aux.ExpiresAt); err != nil {
+		return fmt.Errorf("error parsing expires_at: %w", err)
+	}
+	if o.CompletedAt, err = parseOrderTime(aux.CompletedAt); err != nil {
+		return fmt.Errorf("error parsing completed_at: %w", err)
+	}
+
+	return nil
+}
+
+// parseOrderTime parses a convert order timestamp in UTC+3. A missing or null value yields the zero time.
+//
+// Besides the documented "2024-03-25 , 11:24:55" layout, it accepts the same timestamp without the comma and RFC 3339 timestamps.
+func parseOrderTime(value *string) (time.Time, error) {
+	if value == nil || *value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.ParseInLocation(orderTimeLayout, *value, cryptomusLocation)
+	if err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateTime, *value, cryptomusLocation); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, *value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, err
+}
+
+// The decimal accessors below parse the amounts of the order exactly, without float rounding.
+// They return nil and no error when the field is null or empty, e.g. the executed amounts of an order that is not executed yet.
+
+// ConvertAmountFromDecimal returns convert_amount_from as an exact decimal.
+func (o MarketOrder) ConvertAmountFromDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(o.ConvertAmountFrom)
+}
+
+// ConvertAmountToDecimal returns convert_amount_to as an exact decimal.
+func (o MarketOrder) ConvertAmountToDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(o.ConvertAmountTo)
+}
+
+// ExecutedAmountFromDecimal returns executed_amount_from as an exact decimal.
+func (o MarketOrder) ExecutedAmountFromDecimal() (*big.Rat, error) {
+	return parseNullableDecimal(o.ExecutedAmountFrom)
+}
+
+// ExecutedAmountToDecimal returns executed_amount_to as an exact decimal.
+func (o MarketOrder) ExecutedAmountToDecimal() (*big.Rat, error) {
+	return parseNullableDecimal(o.ExecutedAmountTo)
+}
+
+// CurrentRateDecimal returns current_rate as an exact decimal.
+func (o MarketOrder) CurrentRateDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(o.CurrentRate)
+}
@@ -0,0 +1,111 @@
+package cryptomus_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestMarketOrderUnmarshal(t *testing.T) {
+	utc3 := time.FixedZone("UTC+3", 3*60*60)
+
+	var order cryptomus.MarketOrder
+	body := `{
+		"order_id": "2d9bf426-98ef-448b-84c2-03cc1ec78feb",
+		"convert_amount_from": "10.000",
+		"convert_amount_to": "3.000",
+		"executed_amount_from": null,
+		"executed_amount_to": null,
+		"convert_currency_from": "USDT",
+		"convert_currency_to": "XMR",
+		"type": "market",
+		"status": "completed",
+		"created_at": "2024-07-11 , 18:06:04",
+		"current_rate": "100",
+		"completed_at": "2024-07-11 , 18:06:04"
+	}`
+	if err := json.Unmarshal([]byte(body), &order); err != nil {
+		t.Fatalf("error decoding order: %v", err)
+	}
+	if order.OrderID != "2d9bf426-98ef-448b-84c2-03cc1ec78feb" || order.ExecutedAmountFrom != nil || order.ExecutedAmountTo != nil {
+		t.Errorf("unexpected order %+v", order)
+	}
+	if want := time.Date(2024, 7, 11, 18, 6, 4, 0, utc3); !order.CreatedAt.Equal(want) || !order.CompletedAt.Equal(want) {
+		t.Errorf("got created_at %v, completed_at %v, want %v", order.CreatedAt, order.CompletedAt, want)
+	}
+	if !order.ExpiresAt.IsZero() {
+		t.Errorf("expected missing expires_at to be zero, got %v", order.ExpiresAt)
+	}
+
+	var listed cryptomus.MarketOrder
+	body = `{
+		"order_id": 49347,
+		"convert_amount_from": "0.03700249",
+		"convert_amount_to": "2476.39230892",
+		"executed_amount_from": "0.03700249",
+		"executed_amount_to": "2476.39230892",
+		"convert_currency_from": "BTC",
+		"convert_currency_to": "USDT",
+		"type": "limit",
+		"status": "active",
+		"created_at": "2024-03-25 , 11:24:55",
+		"current_rate": "66925.01798999",
+		"limit": "67000",
+		"expires_at": "2024-03-26 , 11:24:55",
+		"completed_at": null
+	}`
+	if err := json.Unmarshal([]byte(body), &listed); err != nil {
+		t.Fatalf("error decoding order: %v", err)
+	}
+	if listed.OrderID != "49347" || listed.ExecutedAmountTo == nil || *listed.ExecutedAmountTo != "2476.39230892" {
+		t.Errorf("unexpected order %+v", listed)
+	}
+	if want := time.Date(2024, 3, 26, 11, 24, 55, 0, utc3); !listed.ExpiresAt.Equal(want) {
+		t.Errorf("got expires_at %v, want %v", listed.ExpiresAt, want)
+	}
+	if !listed.CompletedAt.IsZero() {
+		t.Errorf("expected null completed_at to be zero, got %v", listed.CompletedAt)
+	}
+
+	if err := json.Unmarshal([]byte(`{"created_at": "yesterday"}`), &order); err == nil {
+		t.Error("expected error for a malformed timestamp")
+	}
+}
+
+func TestMarketOrderAmountDecimals(t *testing.T) {
+	var order cryptomus.MarketOrder
+	body := `{"convert_amount_from": "0.12345678", "current_rate": "64213.50000000", "executed_amount_from": null, "executed_amount_to": ""}`
+	if err := json.Unmarshal([]byte(body), &order); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	amount, err := order.ConvertAmountFromDecimal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := big.NewRat(12345678, 100000000); amount.Cmp(want) != 0 {
+		t.Errorf("convert_amount_from: got %v, want %v", amount, want)
+	}
+
+	rate, err := order.CurrentRateDecimal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := big.NewRat(128427, 2); rate.Cmp(want) != 0 {
+		t.Errorf("current_rate: got %v, want %v", rate, want)
+	}
+
+	for name, value := range map[string]func() (*big.Rat, error){
+		"convert_amount_to":    order.ConvertAmountToDecimal,
+		"executed_amount_from": order.ExecutedAmountFromDecimal,
+		"executed_amount_to":   order.ExecutedAmountToDecimal,
+	} {
+		got, err := value()
+		if err != nil || got != nil {
+			t.Errorf("%s: got %v, %v, want nil, nil", name, got, err)
+		}
+	}
+}
@@ -1,106 +1,157 @@
 package cryptomus
 
 import (
-	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/base64"
 	"encoding/hex"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"time"
+	"strings"
 )
 
 // You need a merchant with different API keys for accepting payment and making payouts.
 //
 // See "Getting API keys" https://doc.cryptomus.com/business/general/getting-api-keys
+//
+// A Merchant is safe for concurrent use by multiple goroutines, so create one per merchant and share it. Its methods do not modify it; do not change its fields once it is in use.
 type Merchant struct {
 	MerchantUUID, PaymentAPIKey, PayoutAPIKey string
-	client                                    *http.Client
+	options
 }
 
 // NewMerchant creates a merchant with different API keys for accepting payment and making payouts.
 //
 // See "Getting API keys" https://doc.cryptomus.com/business/general/getting-api-keys
-func NewMerchant(merchantUUID, paymentAPIKey, PayoutAPIKey string) *Merchant {
-	return &Merchant{
+func NewMerchant(merchantUUID, paymentAPIKey, PayoutAPIKey string, opts ...Option) *Merchant {
+	m := &Merchant{
 		MerchantUUID:  merchantUUID,
 		PaymentAPIKey: paymentAPIKey,
 		PayoutAPIKey:  PayoutAPIKey,
-		client:        &http.Client{Timeout: 10 * time.Second},
+		options:       newOptions(opts),
 	}
+	m.secrets = []string{paymentAPIKey, PayoutAPIKey}
+	return m
+}
+
+// Base64Body returns the standard, padded base64 encoding of body, the intermediate step of the sign of requests and webhooks:
+//
+//	sign = hex(md5(Base64Body(body) + apiKey))
+//
+// For a webhook, body is the received JSON with its "sign" member removed and every other byte unchanged.
+//
+// It is exposed so that systems written in other languages can check their implementation against this package step by step.
+// For example, with the body of a request to create an invoice and the payment API key "payment-key":
+//
+//	body:             {"amount":"15","currency":"USD","order_id":"1"}
+//	Base64Body(body): eyJhbW91bnQiOiIxNSIsImN1cnJlbmN5IjoiVVNEIiwib3JkZXJfaWQiOiIxIn0=
+//	sign:             md5("eyJhbW91bnQiOiIxNSIsImN1cnJlbmN5IjoiVVNEIiwib3JkZXJfaWQiOiIxIn0=payment-key")
+//	                  = 34f805b7f71a91dd3ca118a49aead428
+//
+// See "Request format" https://doc.cryptomus.com/business/general/request-format
+func Base64Body(body []byte) string {
+	return base64.StdEncoding.EncodeToString(body)
 }
 
-// signPaymentPayload generates MD5 hash of the body of the POST request encoded in base64 and combined with your payment API key.
+// SignPayment returns the sign Cryptomus expects for a request with the given body to a payment endpoint, as sent in the sign header.
+//
+// The sign is the hex-encoded MD5 hash of the base64-encoded body followed by the payment API key:
+//
+//	md5(base64(body) + PaymentAPIKey)
+//
+// body must be the exact bytes sent. It is also how Cryptomus signs the webhooks of payments and wallets.
 //
 // See "Request format" https://doc.cryptomus.com/business/general/request-format
-func (m *Merchant) signPaymentPayload(jsonData []byte) (string, error) {
-	base64Data := base64.StdEncoding.EncodeToString(jsonData)
-	hash := md5.Sum([]byte(base64Data + m.PaymentAPIKey))
-	return hex.EncodeToString(hash[:]), nil
+func (m *Merchant) SignPayment(body []byte) string {
+	hash := md5.Sum([]byte(Base64Body(body) + m.PaymentAPIKey))
+	return hex.EncodeToString(hash[:])
 }
 
-// signPayoutPayload generates MD5 hash of the body of the POST request encoded in base64 and combined with your payout API key.
+// SignPayout returns the sign Cryptomus expects for a request with the given body to a payout endpoint, as sent in the sign header.
+//
+// The sign is the hex-encoded MD5 hash of the base64-encoded body followed by the payout API key:
+//
+//	md5(base64(body) + PayoutAPIKey)
+//
+// body must be the exact bytes sent. It is also how Cryptomus signs the webhooks of payouts.
 //
 // See "Request format" https://doc.cryptomus.com/business/general/request-format
-func (m *Merchant) signPayoutPayload(jsonData []byte) (string, error) {
-	base64Data := base64.StdEncoding.EncodeToString(jsonData)
-	hash := md5.Sum([]byte(base64Data + m.PayoutAPIKey))
-	return hex.EncodeToString(hash[:]), nil
+func (m *Merchant) SignPayout(body []byte) string {
+	hash := md5.Sum([]byte(Base64Body(body) + m.PayoutAPIKey))
+	return hex.EncodeToString(hash[:])
 }
 
-func (m *Merchant) sendPaymentRequest(method, url string, request any) (*http.Response, error) {
-	jsonData, err := json.Marshal(request)
+func (m *Merchant) sendPaymentRequest(ctx context.Context, method, path string, request any) (*http.Response, error) {
+	jsonData, err := m.marshal(request)
 	if err != nil {
 		return nil, fmt.Errorf("error marshalling request data: %w", err)
 	}
 
-	httpRequest, err := http.NewRequest(method, url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
+	signature := m.SignPayment(jsonData)
 
-	signature, err := m.signPaymentPayload(jsonData)
-	if err != nil {
-		return nil, fmt.Errorf("error generating signature: %w", err)
-	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("merchant", m.MerchantUUID)
+	header.Set("sign", signature)
 
-	httpRequest.Header.Set("Content-Type", "application/json")
-	httpRequest.Header.Set("merchant", m.MerchantUUID)
-	httpRequest.Header.Set("sign", signature)
+	return m.do(ctx, method, path, jsonData, header)
+}
 
-	httpResponse, err := m.client.Do(httpRequest)
+func (m *Merchant) sendPayoutRequest(ctx context.Context, method, path string, request any) (*http.Response, error) {
+	jsonData, err := m.marshal(request)
 	if err != nil {
-		return nil, fmt.Errorf("error sending request: %w", err)
+		return nil, fmt.Errorf("error marshalling request data: %w", err)
 	}
 
-	return httpResponse, nil
+	signature := m.SignPayout(jsonData)
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("merchant", m.MerchantUUID)
+	header.Set("sign", signature)
+
+	return m.do(ctx, method, path, jsonData, header)
 }
 
-func (m *Merchant) sendPayoutRequest(method, url string, request any) (*http.Response, error) {
-	jsonData, err := json.Marshal(request)
-	if err != nil {
-		return nil, fmt.Errorf("error marshalling request data: %w", err)
+// checkMerchantUUID returns ErrMerchantMismatch if WithResponseMerchantCheck is set and merchantUUID, as echoed in a response, is not the one of m.
+func (m *Merchant) checkMerchantUUID(merchantUUID string) error {
+	if m.checkMerchant && merchantUUID != "" && !strings.EqualFold(merchantUUID, m.MerchantUUID) {
+		return fmt.Errorf("%w: got merchant %s, configured %s", ErrMerchantMismatch, merchantUUID, m.MerchantUUID)
 	}
+	return nil
+}
 
-	httpRequest, err := http.NewRequest(method, url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
+// ErrInvalidCredentials is returned by VerifyCredentials when Cryptomus rejects the merchant uuid or one of the API keys.
+var ErrInvalidCredentials = errors.New("invalid merchant credentials")
 
-	signature, err := m.signPayoutPayload(jsonData)
-	if err != nil {
-		return nil, fmt.Errorf("error generating signature: %w", err)
+// VerifyCredentials checks the merchant uuid and both API keys with one lightweight request each, listing the payment and the payout services, e.g. to fail fast on startup.
+//
+// If Cryptomus rejects a request as unauthorized, the returned error wraps both ErrInvalidCredentials and the APIError, and names the key that failed.
+// Other errors, e.g. network or server errors, are returned as they are: the credentials may still be valid.
+func (m *Merchant) VerifyCredentials(ctx context.Context) error {
+	if _, err := m.ListPaymentServicesContext(ctx); err != nil {
+		return credentialsError("payment API key", err)
 	}
+	if _, err := m.ListPayoutServicesContext(ctx); err != nil {
+		return credentialsError("payout API key", err)
+	}
+	return nil
+}
 
-	httpRequest.Header.Set("Content-Type", "application/json")
-	httpRequest.Header.Set("merchant", m.MerchantUUID)
-	httpRequest.Header.Set("sign", signature)
-
-	httpResponse, err := m.client.Do(httpRequest)
-	if err != nil {
-		return nil, fmt.Errorf("error sending request: %w", err)
+// credentialsError wraps err with ErrInvalidCredentials if it is an unauthorized APIError for a request signed with key.
+func credentialsError(key string, err error) error {
+	apiError, ok := asAPIError(err)
+	if ok && (apiError.HTTPStatus == http.StatusUnauthorized || apiError.HTTPStatus == http.StatusForbidden) {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidCredentials, key, err)
 	}
+	return fmt.Errorf("error verifying %s: %w", key, err)
+}
 
-	return httpResponse, nil
+// Close closes the idle keep-alive connections of the HTTP client of m, e.g. before discarding m in a long-running process.
+//
+// Calling it is optional: m holds no other resources, and it stays usable after Close, opening new connections as needed.
+// With the default client, or one set with WithHTTPClient that shares its transport, it closes the idle connections of that shared transport, such as http.DefaultTransport, too.
+func (m *Merchant) Close() {
+	m.client.CloseIdleConnections()
 }
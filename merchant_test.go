@@ -0,0 +1,176 @@
+package cryptomus_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestMerchantContextCancellation(t *testing.T) {
+	newMockServer(t, blockUntilCancelled(t))
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := merchant.CreateInvoiceContext(ctx, cryptomus.Invoice{Amount: "15", Currency: "USD", OrderID: "1"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context deadline error, got %v", err)
+	}
+}
+
+func TestMerchantSign(t *testing.T) {
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	body := []byte(`{"amount":"15","currency":"USD","order_id":"1"}`)
+
+	if got, want := cryptomus.Base64Body(body), "eyJhbW91bnQiOiIxNSIsImN1cnJlbmN5IjoiVVNEIiwib3JkZXJfaWQiOiIxIn0="; got != want {
+		t.Errorf("Base64Body = %s, want %s", got, want)
+	}
+	if got, want := merchant.SignPayment(body), "34f805b7f71a91dd3ca118a49aead428"; got != want {
+		t.Errorf("SignPayment = %s, want %s", got, want)
+	}
+	if got, want := merchant.SignPayout(body), "8d8fa4b7b6d85bd6ec39faddbe0e1b42"; got != want {
+		t.Errorf("SignPayout = %s, want %s", got, want)
+	}
+
+	// The sign header of an actual request is SignPayment of its body.
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		sent, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("error reading request body: %v", err)
+		}
+		if got, want := r.Header.Get("sign"), merchant.SignPayment(sent); got != want {
+			t.Errorf("sign header = %s, want %s", got, want)
+		}
+		w.Write([]byte(`{"state": 0, "result": {}}`))
+	})
+	if _, err := merchant.CreateInvoice(cryptomus.Invoice{Amount: "15", Currency: "USD", OrderID: "1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestVerifyCredentials(t *testing.T) {
+	tests := []struct {
+		name            string
+		payment, payout int
+		wantInvalid     bool
+		wantErr         bool
+		wantMessagePart string
+	}{
+		{"valid", http.StatusOK, http.StatusOK, false, false, ""},
+		{"invalid payment key", http.StatusUnauthorized, http.StatusOK, true, true, "payment API key"},
+		{"invalid payout key", http.StatusOK, http.StatusForbidden, true, true, "payout API key"},
+		{"server error", http.StatusInternalServerError, http.StatusOK, false, true, "payment API key"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				status := test.payment
+				if r.URL.Path == "/v1/payout/services" {
+					status = test.payout
+				}
+				w.WriteHeader(status)
+				switch status {
+				case http.StatusOK:
+					w.Write([]byte(`{"state": 0, "result": []}`))
+				case http.StatusInternalServerError:
+					w.Write([]byte(`{"message": "Server error, #1", "code": 500}`))
+				default:
+					w.Write([]byte(`{"state": 1, "message": "Unauthorized"}`))
+				}
+			})
+
+			merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+			err := merchant.VerifyCredentials(context.Background())
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error: %v", err, test.wantErr)
+			}
+			if got := errors.Is(err, cryptomus.ErrInvalidCredentials); got != test.wantInvalid {
+				t.Errorf("got error %v, want ErrInvalidCredentials: %v", err, test.wantInvalid)
+			}
+			if err != nil && !strings.Contains(err.Error(), test.wantMessagePart) {
+				t.Errorf("got error %q, want it to name the %s", err, test.wantMessagePart)
+			}
+			var apiError *cryptomus.APIError
+			if err != nil && !errors.As(err, &apiError) {
+				t.Errorf("got error %v, want it to wrap the APIError", err)
+			}
+		})
+	}
+}
+
+// TestMerchantConcurrentUse shares one Merchant between goroutines; run it with -race.
+func TestMerchantConcurrentUse(t *testing.T) {
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithRateLimit(1000, 10))
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("sign") != merchant.SignPayment(body) {
+			t.Errorf("got sign %s for body %s", r.Header.Get("sign"), body)
+		}
+		var invoice cryptomus.Invoice
+		json.Unmarshal(body, &invoice)
+		fmt.Fprintf(w, `{"state": 0, "result": {"order_id": %q, "amount": %q, "currency": "USDT"}}`, invoice.OrderID, invoice.Amount)
+	})
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			orderID := strconv.Itoa(i)
+			payment, err := merchant.CreateInvoice(cryptomus.Invoice{Amount: "15", Currency: "USDT", OrderID: orderID})
+			if err != nil {
+				t.Errorf("order %s: unexpected error: %v", orderID, err)
+				return
+			}
+			if payment.OrderID != orderID {
+				t.Errorf("got order %s, want %s", payment.OrderID, orderID)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMerchantClose(t *testing.T) {
+	var mu sync.Mutex
+	connections := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		connections[r.RemoteAddr] = true
+		mu.Unlock()
+		w.Write([]byte(`{"state": 0, "result": []}`))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{}}
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL), cryptomus.WithHTTPClient(client))
+	merchant.Close()
+
+	for range 2 {
+		if _, err := merchant.ListPaymentServices(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(connections) != 1 {
+		t.Errorf("got %d connections, want the idle one reused", len(connections))
+	}
+
+	merchant.Close()
+	if _, err := merchant.ListPaymentServices(); err != nil {
+		t.Fatalf("after Close: unexpected error: %v", err)
+	}
+	if len(connections) != 2 {
+		t.Errorf("got %d connections, want a new one after Close", len(connections))
+	}
+}
@@ -0,0 +1,57 @@
+package cryptomus_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// redirectTransport sends every request to the mock server, whatever host it was built for.
+type redirectTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (t redirectTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request = request.Clone(request.Context())
+	request.URL.Scheme = t.target.Scheme
+	request.URL.Host = t.target.Host
+	request.Host = t.target.Host
+	return t.next.RoundTrip(request)
+}
+
+// newMockServer starts a server with the given handler and routes all requests made through
+// http.DefaultTransport to it for the duration of the test.
+func newMockServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("error parsing mock server URL: %v", err)
+	}
+
+	original := http.DefaultTransport
+	http.DefaultTransport = redirectTransport{target: target, next: original}
+	t.Cleanup(func() {
+		http.DefaultTransport = original
+		server.Close()
+	})
+
+	return server
+}
+
+// blockUntilCancelled is a handler that only returns once the client gives up on the request.
+func blockUntilCancelled(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+			t.Error("request was not cancelled")
+		}
+	}
+}
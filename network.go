@@ -0,0 +1,48 @@
+package cryptomus
+
+import "strings"
+
+// WithNetworkAliases makes the requests that take a network code send the code aliases maps it to, e.g. {"TRC20": "tron", "BEP20": "bsc"}, so network names from other systems can be passed as they are.
+//
+// Aliases are matched case-insensitively; codes without an alias are sent unchanged. Calling it again adds to the aliases set before.
+//
+// It applies to CreateInvoice (network, currencies and except_currencies), CreatePayout, CreateStaticWallet, SetDiscount, GetDiscount and the TestWebhook methods.
+func WithNetworkAliases(aliases map[string]string) Option {
+	return func(o *options) {
+		if o.networkAliases == nil {
+			o.networkAliases = make(map[string]string, len(aliases))
+		}
+		for alias, network := range aliases {
+			o.networkAliases[strings.ToLower(alias)] = network
+		}
+	}
+}
+
+// network returns the network code network is an alias of, or network itself.
+func (o *options) network(network string) string {
+	if code, ok := o.networkAliases[strings.ToLower(network)]; ok {
+		return code
+	}
+	return network
+}
+
+// networkPtr is like network for optional codes.
+func (o *options) networkPtr(network *string) *string {
+	if network == nil {
+		return nil
+	}
+	code := o.network(*network)
+	return &code
+}
+
+// currencies returns a copy of currencies with their networks resolved by network.
+func (o *options) currencies(currencies []Currency) []Currency {
+	if currencies == nil || o.networkAliases == nil {
+		return currencies
+	}
+	resolved := make([]Currency, len(currencies))
+	for i, currency := range currencies {
+		resolved[i] = Currency{Currency: currency.Currency, Network: o.networkPtr(currency.Network)}
+	}
+	return resolved
+}
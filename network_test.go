@@ -0,0 +1,48 @@
+package cryptomus_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestWithNetworkAliases(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = nil
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("error decoding request: %v", err)
+		}
+		w.Write([]byte(`{"state": 0, "result": {}}`))
+	}))
+	defer server.Close()
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL), cryptomus.WithNetworkAliases(map[string]string{"TRC20": "tron", "bep20": "bsc"}))
+	stringPtr := func(s string) *string { return &s }
+
+	currencies := []cryptomus.Currency{{Currency: "USDT", Network: stringPtr("BEP20")}, {Currency: "BTC"}}
+	invoice := cryptomus.Invoice{Amount: "15", Currency: "USDT", OrderID: "1", Network: stringPtr("trc20"), Currencies: currencies}
+	if _, err := merchant.CreateInvoice(invoice); err != nil {
+		t.Fatalf("error creating invoice: %v", err)
+	}
+	if received["network"] != "tron" {
+		t.Errorf("got network %v, want tron", received["network"])
+	}
+	sent := received["currencies"].([]any)
+	if sent[0].(map[string]any)["network"] != "bsc" || sent[1].(map[string]any)["network"] != nil {
+		t.Errorf("got currencies %v", sent)
+	}
+	if *currencies[0].Network != "BEP20" {
+		t.Errorf("the currencies of the caller were modified: %s", *currencies[0].Network)
+	}
+
+	if _, err := merchant.CreateStaticWallet(cryptomus.StaticWalletRequest{Currency: "USDT", Network: "ETH", OrderID: "2"}); err != nil {
+		t.Fatalf("error creating static wallet: %v", err)
+	}
+	if received["network"] != "ETH" {
+		t.Errorf("got network %v for a code without alias, want ETH", received["network"])
+	}
+}
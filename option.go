@@ -0,0 +1,229 @@
+package cryptomus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a Merchant or User.
+type Option func(*options)
+
+// options holds the settings shared by Merchant and User.
+type options struct {
+	client              *http.Client
+	timeout             *time.Duration
+	baseURL             string
+	maxAttempts         int
+	retryDelay          time.Duration
+	correlationIDHeader string
+	userAgent           string
+	checkMerchant       bool
+	checkInvoice        bool
+	checkPairs          bool
+	batchConcurrency    int
+	maxHistoryPages     int
+	limiter             *rateLimiter
+	logger              *slog.Logger
+	secrets             []string
+	networkAliases      map[string]string
+	marshal             func(any) ([]byte, error)
+	unmarshal           func([]byte, any) error
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		client:           &http.Client{Timeout: 10 * time.Second},
+		baseURL:          urlEndpoint,
+		userAgent:        defaultUserAgent,
+		maxAttempts:      1,
+		batchConcurrency: defaultBatchConcurrency,
+		marshal:          json.Marshal,
+		unmarshal:        json.Unmarshal,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.timeout != nil {
+		client := *o.client
+		client.Timeout = *o.timeout
+		o.client = &client
+	}
+	return o
+}
+
+// WithHTTPClient sends requests with the given client instead of the default client with a 10-second timeout.
+//
+// Use it to configure a proxy, TLS settings or connection pooling. The timeout of the client is kept unless WithTimeout is set.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		if client != nil {
+			o.client = client
+		}
+	}
+}
+
+// WithTimeout sets the time limit for each request, 10 seconds by default. A zero timeout means no time limit.
+//
+// It also applies to the client passed to WithHTTPClient, whichever option comes first; the client is copied rather than modified.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = &timeout
+	}
+}
+
+// WithBaseURL sends requests to baseURL instead of https://api.cryptomus.com/, e.g. to point a Merchant or User at an httptest.Server.
+//
+// Endpoint paths such as "v1/payment" are appended to baseURL; a trailing slash is added if missing. An empty baseURL keeps the default.
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		if baseURL == "" {
+			return
+		}
+		if !strings.HasSuffix(baseURL, "/") {
+			baseURL += "/"
+		}
+		o.baseURL = baseURL
+	}
+}
+
+// WithCorrelationIDHeader sets the header headerName, e.g. "X-Request-ID", on every request whose context carries a correlation ID from ContextWithCorrelationID.
+//
+// The header is not part of the signature, which only covers the request body.
+func WithCorrelationIDHeader(headerName string) Option {
+	return func(o *options) {
+		o.correlationIDHeader = headerName
+	}
+}
+
+// Version is the version of this package, sent in the default User-Agent.
+const Version = "0.1.0"
+
+// defaultUserAgent identifies requests sent by this package unless WithUserAgent is set.
+const defaultUserAgent = "cryptomus-go/" + Version
+
+// WithUserAgent sends userAgent as the User-Agent header of every request instead of "cryptomus-go/" followed by Version.
+//
+// Keep the default in it, e.g. "my-shop/2.1 cryptomus-go/0.1.0", so Cryptomus support can still tell the requests come from this package. An empty userAgent keeps the default.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		if userAgent != "" {
+			o.userAgent = userAgent
+		}
+	}
+}
+
+// ErrMerchantMismatch is returned when WithResponseMerchantCheck is set and a response belongs to another merchant than the one configured.
+var ErrMerchantMismatch = errors.New("response is for another merchant")
+
+// WithResponseMerchantCheck makes a Merchant compare the merchant uuid echoed in responses with its MerchantUUID and fail with ErrMerchantMismatch if they differ, e.g. because the API keys of one merchant were paired with the uuid of another.
+//
+// Only responses that echo a merchant uuid can be checked; currently that is the payout history. It has no effect on a User.
+func WithResponseMerchantCheck() Option {
+	return func(o *options) {
+		o.checkMerchant = true
+	}
+}
+
+// ErrInvoiceMismatch is returned when WithInvoiceMismatchCheck is set and CreateInvoice gets back an existing invoice with another amount or currency than requested.
+var ErrInvoiceMismatch = errors.New("invoice with this order_id already exists with another amount or currency")
+
+// WithInvoiceMismatchCheck makes CreateInvoice compare the amount and currency of the returned invoice with the request and fail with ErrInvoiceMismatch if they differ.
+//
+// Cryptomus returns the existing invoice when order_id is reused and ignores the new parameters, so without the check an accidentally reused order_id goes unnoticed.
+// The existing invoice is returned along with the error.
+//
+// If the invoice lists currencies, CreateInvoice also checks them against ListPaymentServices with Invoice.ValidateCurrencies before sending, so an invoice does not offer a currency or network Cryptomus cannot process; this costs one more request.
+// It has no effect on a User.
+func WithInvoiceMismatchCheck() Option {
+	return func(o *options) {
+		o.checkInvoice = true
+	}
+}
+
+// defaultBatchConcurrency is the number of requests CreatePayouts and SetDiscounts send at a time unless WithBatchConcurrency is set.
+const defaultBatchConcurrency = 4
+
+// WithBatchConcurrency sets the number of requests the batch methods, CreatePayouts and SetDiscounts, send at a time, 4 by default. A value below 1 sends them one by one.
+func WithBatchConcurrency(n int) Option {
+	return func(o *options) {
+		o.batchConcurrency = max(n, 1)
+	}
+}
+
+// WithRateLimit spaces requests to at most rps per second on average, allowing bursts of up to burst requests, to stay within the Cryptomus rate limits.
+//
+// Requests wait for their turn, or fail with the error of their context if it is done first. Every attempt of a retried request counts.
+// Each Merchant or User has its own limit; share one by making all requests through the same Merchant or User. A rps of 0 or less means no limit, the default.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(o *options) {
+		o.limiter = nil
+		if rps > 0 {
+			o.limiter = newRateLimiter(rps, burst)
+		}
+	}
+}
+
+// ErrTruncated is returned with the items collected so far when a history method stops at the page limit set with WithMaxHistoryPages while more pages are left.
+var ErrTruncated = errors.New("history truncated at the maximum number of pages")
+
+// WithMaxHistoryPages makes the history methods, e.g. ListPaymentHistory, fetch at most n pages and return what they got with ErrTruncated if there are more.
+//
+// The history iterators, e.g. PaymentHistorySeq, yield ErrTruncated after the last page instead. A value below 1 means no limit, the default.
+func WithMaxHistoryPages(n int) Option {
+	return func(o *options) {
+		o.maxHistoryPages = max(n, 0)
+	}
+}
+
+// historyTruncated reports whether a history that has fetched pages pages must stop before the next one.
+func (o *options) historyTruncated(pages int) bool {
+	return o.maxHistoryPages > 0 && pages >= o.maxHistoryPages
+}
+
+type correlationIDKey struct{}
+
+// ContextWithCorrelationID returns a copy of ctx carrying id, which is sent in the header configured with WithCorrelationIDHeader by the Context variants of the Merchant and User methods.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID set with ContextWithCorrelationID, if any.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// WithJSONCodec replaces encoding/json with the given functions, e.g. to use a faster JSON library for large history responses.
+//
+// unmarshal decodes every response payload. marshal encodes request bodies; the signature is computed over the exact bytes marshal returns, so any codec producing valid JSON keeps requests correctly signed.
+//
+// The codec is never used on the signing path itself: VerifySign must reproduce the bytes Cryptomus signed and always encodes webhook payloads with encoding/json.
+//
+// A nil function keeps the encoding/json default.
+func WithJSONCodec(marshal func(any) ([]byte, error), unmarshal func([]byte, any) error) Option {
+	return func(o *options) {
+		if marshal != nil {
+			o.marshal = marshal
+		}
+		if unmarshal != nil {
+			o.unmarshal = unmarshal
+		}
+	}
+}
+
+// decode reads the whole response body and unmarshals it into v with the configured codec.
+func (o *options) decode(body io.Reader, v any) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+
+	return o.unmarshal(data, v)
+}
@@ -0,0 +1,210 @@
+package cryptomus_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestWithJSONCodec(t *testing.T) {
+	var signature string
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get("sign")
+		w.Write([]byte(`{"state": 0, "result": {"uuid": "26109ba0-b05b-4ee0-93d1-fd62c822ce95", "order_id": "1", "amount": "15.00", "currency": "USD"}}`))
+	})
+
+	var marshalled, unmarshalled int
+	marshal := func(v any) ([]byte, error) {
+		marshalled++
+		return json.Marshal(v)
+	}
+	unmarshal := func(data []byte, v any) error {
+		unmarshalled++
+		return json.Unmarshal(data, v)
+	}
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithJSONCodec(marshal, unmarshal))
+	payment, err := merchant.CreateInvoice(cryptomus.Invoice{Amount: "15", Currency: "USD", OrderID: "1"})
+	if err != nil {
+		t.Fatalf("error creating invoice: %v", err)
+	}
+
+	if payment.UUID != "26109ba0-b05b-4ee0-93d1-fd62c822ce95" {
+		t.Errorf("unexpected payment %+v", payment)
+	}
+	if marshalled != 1 || unmarshalled != 1 {
+		t.Errorf("expected the codec to be used once each way, got marshal %d, unmarshal %d", marshalled, unmarshalled)
+	}
+	if signature == "" {
+		t.Error("request was not signed")
+	}
+}
+
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"state": 0, "result": []}`))
+	})
+
+	transport := &countingTransport{}
+	client := &http.Client{Transport: transport}
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithHTTPClient(client))
+	if _, err := merchant.ListPaymentServices(); err != nil {
+		t.Fatalf("error listing payment services: %v", err)
+	}
+
+	user := cryptomus.NewUser("user", "payment-key", "payout-key", cryptomus.WithHTTPClient(client))
+	if _, err := user.ListDirections(); err != nil {
+		t.Fatalf("error listing directions: %v", err)
+	}
+
+	if transport.requests != 2 {
+		t.Errorf("expected 2 requests through the provided client, got %d", transport.requests)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	newMockServer(t, blockUntilCancelled(t))
+
+	client := &http.Client{}
+	for name, opts := range map[string][]cryptomus.Option{
+		"client first":  {cryptomus.WithHTTPClient(client), cryptomus.WithTimeout(50 * time.Millisecond)},
+		"timeout first": {cryptomus.WithTimeout(50 * time.Millisecond), cryptomus.WithHTTPClient(client)},
+	} {
+		merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", opts...)
+		if _, err := merchant.ListPaymentServices(); err == nil {
+			t.Fatalf("%s: expected timeout error", name)
+		}
+	}
+	if client.Timeout != 0 {
+		t.Errorf("provided client was modified, timeout %s", client.Timeout)
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`{"state": 0, "result": []}`))
+	}))
+	defer server.Close()
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+	if _, err := merchant.ListPaymentServices(); err != nil {
+		t.Fatalf("error listing payment services: %v", err)
+	}
+
+	user := cryptomus.NewUser("user", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL+"/"))
+	if _, err := user.ListDirections(); err != nil {
+		t.Fatalf("error listing directions: %v", err)
+	}
+
+	want := []string{"/v1/payment/services", "/v2/user-api/convert/direction-list"}
+	if len(paths) != len(want) || paths[0] != want[0] || paths[1] != want[1] {
+		t.Errorf("got paths %v, want %v", paths, want)
+	}
+}
+
+func TestWithCorrelationIDHeader(t *testing.T) {
+	var correlationID, signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		correlationID = r.Header.Get("X-Request-ID")
+		signature = r.Header.Get("sign")
+		w.Write([]byte(`{"state": 0, "result": []}`))
+	}))
+	defer server.Close()
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL), cryptomus.WithCorrelationIDHeader("X-Request-ID"))
+	if _, err := merchant.ListPaymentServices(); err != nil {
+		t.Fatalf("error listing payment services: %v", err)
+	}
+	if correlationID != "" {
+		t.Errorf("expected no correlation ID without one in the context, got %q", correlationID)
+	}
+	unsigned := signature
+
+	ctx := cryptomus.ContextWithCorrelationID(context.Background(), "req-42")
+	if _, err := merchant.ListPaymentServicesContext(ctx); err != nil {
+		t.Fatalf("error listing payment services: %v", err)
+	}
+	if correlationID != "req-42" {
+		t.Errorf("got correlation ID %q, want req-42", correlationID)
+	}
+	if signature != unsigned {
+		t.Error("correlation ID changed the signature")
+	}
+}
+
+func TestWithUserAgent(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"state": 0, "result": []}`))
+	}))
+	defer server.Close()
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+	if _, err := merchant.ListPayoutServices(); err != nil {
+		t.Fatalf("error listing payout services: %v", err)
+	}
+	if want := "cryptomus-go/" + cryptomus.Version; userAgent != want {
+		t.Errorf("got User-Agent %q, want %q", userAgent, want)
+	}
+
+	user := cryptomus.NewUser("user", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL), cryptomus.WithUserAgent("my-shop/2.1"))
+	if _, err := user.ListDirections(); err != nil {
+		t.Fatalf("error listing directions: %v", err)
+	}
+	if userAgent != "my-shop/2.1" {
+		t.Errorf("got User-Agent %q, want my-shop/2.1", userAgent)
+	}
+}
+
+func TestWithResponseMerchantCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"state": 0, "result": {"merchant_uuid": "c26b80a8-9549-4b4c-a8f6-8bba2f4f4b1f", "items": [{"uuid": "a7c0caec"}], "paginate": {"nextCursor": null}}}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		merchantUUID string
+		opts         []cryptomus.Option
+		wantErr      bool
+	}{
+		{"matching", "C26B80A8-9549-4B4C-A8F6-8BBA2F4F4B1F", []cryptomus.Option{cryptomus.WithResponseMerchantCheck()}, false},
+		{"mismatch", "0f3c5d2e-0000-4000-8000-000000000000", []cryptomus.Option{cryptomus.WithResponseMerchantCheck()}, true},
+		{"mismatch without check", "0f3c5d2e-0000-4000-8000-000000000000", nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merchant := cryptomus.NewMerchant(test.merchantUUID, "payment-key", "payout-key", append(test.opts, cryptomus.WithBaseURL(server.URL))...)
+			payouts, err := merchant.ListPayoutHistory(cryptomus.HistoryRequest{})
+			if test.wantErr {
+				if !errors.Is(err, cryptomus.ErrMerchantMismatch) {
+					t.Errorf("got %v, %v, want ErrMerchantMismatch", payouts, err)
+				}
+				return
+			}
+			if err != nil || len(payouts) != 1 {
+				t.Errorf("got %v, %v, want one payout", payouts, err)
+			}
+		})
+	}
+}
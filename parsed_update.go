@@ -0,0 +1,180 @@
+package cryptomus
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ParsedUpdate is an Update with its amounts parsed as exact decimals and its status typed.
+//
+// Fields missing from the webhook are left zero: nil for amounts and "" for strings. Raw keeps the Update it was parsed from.
+type ParsedUpdate struct {
+	// wallet, payment or payout
+	Type string
+	// uuid of the payment/payout
+	UUID string
+	// Order ID in your system
+	OrderID string
+	// The amount of the payment/payout
+	Amount *big.Rat
+	// (Only in Payment) Amount actually paid by client
+	PaymentAmount *big.Rat
+	// (Only in Payment) Amount actually paid by client in USD
+	PaymentAmountUSD *big.Rat
+	// The amount added to or debited from the merchant's balance
+	MerchantAmount *big.Rat
+	// Cryptomus commission amount
+	Commission *big.Rat
+	// (Only in Payout) Amount in payer_currency of the payout
+	PayerAmount *big.Rat
+	// Whether the invoice/withdrawal is finalized
+	IsFinal bool
+	// Status of a payment or wallet update, "" for a payout
+	PaymentStatus PaymentStatus
+	// Status of a payout update, "" for a payment or wallet
+	PayoutStatus PayoutStatus
+	// (Only in Payment) Payer's wallet address
+	From string
+	// The blockchain network for payment/payout
+	Network string
+	// The currency of payment/payout
+	Currency string
+	// The cryptocurrency code in which payment/payout will be actually made
+	PayerCurrency string
+	// Transaction hash on the blockchain
+	TxID string
+	// (Only in Payment) Additional information string that you provided when creating an invoice
+	AdditionalData string
+	// (Only in Payment) Automatic conversion of the payment, nil if it is not enabled
+	Convert *AutomaticConvert
+	// The Update as received
+	Raw Update
+}
+
+// Parse returns u with its amounts parsed as exact decimals and its status typed. It fails if an amount is not a decimal number.
+//
+// Parse does not check the sign; verify it first, or use OnUpdateParsed with WebhookHandler, which does.
+func (u Update) Parse() (*ParsedUpdate, error) {
+	parsed := ParsedUpdate{
+		Type:           deref(u.Type),
+		UUID:           deref(u.UUID),
+		OrderID:        deref(u.OrderID),
+		IsFinal:        u.IsFinal != nil && *u.IsFinal,
+		From:           deref(u.From),
+		Network:        deref(u.Network),
+		Currency:       deref(u.Currency),
+		PayerCurrency:  deref(u.PayerCurrency),
+		TxID:           deref(u.TxID),
+		AdditionalData: deref(u.AdditionalData),
+		Convert:        u.Convert,
+		Raw:            u,
+	}
+	if parsed.Type == "payout" {
+		parsed.PayoutStatus = u.PayoutStatus()
+	} else {
+		parsed.PaymentStatus = u.PaymentStatus()
+	}
+
+	amounts := []struct {
+		field string
+		value *string
+		dst   **big.Rat
+	}{
+		{"amount", u.Amount, &parsed.Amount},
+		{"payment_amount", u.PaymentAmount, &parsed.PaymentAmount},
+		{"payment_amount_usd", u.PaymentAmountUSD, &parsed.PaymentAmountUSD},
+		{"merchant_amount", u.MerchantAmount, &parsed.MerchantAmount},
+		{"commission", u.Commission, &parsed.Commission},
+		{"payer_amount", u.PayerAmount, &parsed.PayerAmount},
+	}
+	for _, amount := range amounts {
+		value, err := parseNullableDecimal(amount.value)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s of update: %w", amount.field, err)
+		}
+		*amount.dst = value
+	}
+
+	return &parsed, nil
+}
+
+// OnUpdateParsed adapts handle to the handler of WebhookHandler, so that it receives every verified update parsed with Update.Parse:
+//
+//	http.Handle("/webhook", merchant.WebhookHandler(cryptomus.OnUpdateParsed(func(update cryptomus.ParsedUpdate) error {
+//		if update.PaymentStatus.IsSuccess() {
+//			return fulfil(update.OrderID, update.MerchantAmount)
+//		}
+//		return nil
+//	})))
+//
+// It receives payment, wallet and payout updates alike; check Type. An update that fails to parse is answered like a failed handle, so Cryptomus sends it again.
+func OnUpdateParsed(handle func(ParsedUpdate) error) func(Update) error {
+	return func(update Update) error {
+		parsed, err := update.Parse()
+		if err != nil {
+			return err
+		}
+		return handle(*parsed)
+	}
+}
+
+// HandlePaymentWebhook verifies a payment or wallet webhook and returns it parsed, for handlers that read the request themselves rather than use WebhookHandler.
+//
+// body is the request body exactly as received. sign is the signature to check it against; pass "" to use the sign member of body, which is where Cryptomus sends it.
+// The sign is checked against body without its sign member with the payment API key, like VerifySignRaw.
+// It then parses the update with Update.Parse and fails if the update is not a payment or wallet update, has no uuid or has an unknown status.
+//
+// See "Webhook" https://doc.cryptomus.com/business/payments/webhook
+func (m *Merchant) HandlePaymentWebhook(body []byte, sign string) (ParsedUpdate, error) {
+	return m.handleWebhook(body, sign, false)
+}
+
+// HandlePayoutWebhook is like HandlePaymentWebhook for payout webhooks, whose sign is checked with the payout API key.
+//
+// See "Webhook" https://doc.cryptomus.com/business/payouts/webhook
+func (m *Merchant) HandlePayoutWebhook(body []byte, sign string) (ParsedUpdate, error) {
+	return m.handleWebhook(body, sign, true)
+}
+
+// handleWebhook implements HandlePaymentWebhook and HandlePayoutWebhook.
+func (m *Merchant) handleWebhook(body []byte, sign string, payout bool) (ParsedUpdate, error) {
+	update, err := ParseWebhook(body)
+	if err != nil {
+		return ParsedUpdate{}, err
+	}
+	updateType := *update.Type
+	if payout != (updateType == "payout") {
+		return ParsedUpdate{}, fmt.Errorf("unexpected %s webhook", updateType)
+	}
+
+	unsigned := body
+	if cut, bodySign, err := cutSign(body); err == nil {
+		unsigned = cut
+		if sign == "" {
+			sign = bodySign
+		}
+	}
+	if sign == "" {
+		return ParsedUpdate{}, errors.New("webhook has no sign")
+	}
+	if err := m.checkSign(unsigned, sign, updateType); err != nil {
+		return ParsedUpdate{}, err
+	}
+
+	parsed, err := update.Parse()
+	if err != nil {
+		return ParsedUpdate{}, err
+	}
+	if parsed.UUID == "" {
+		return ParsedUpdate{}, errors.New("webhook has no uuid")
+	}
+	if payout && !parsed.PayoutStatus.IsValid() {
+		return ParsedUpdate{}, fmt.Errorf("unknown payout status %q", parsed.PayoutStatus)
+	}
+	if !payout && !parsed.PaymentStatus.IsValid() {
+		return ParsedUpdate{}, fmt.Errorf("unknown payment status %q", parsed.PaymentStatus)
+	}
+
+	return *parsed, nil
+}
@@ -0,0 +1,124 @@
+package cryptomus_test
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestOnPaymentParsed(t *testing.T) {
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+
+	var received *cryptomus.ParsedUpdate
+	handler := merchant.WebhookHandler(cryptomus.OnUpdateParsed(func(update cryptomus.ParsedUpdate) error {
+		received = &update
+		return nil
+	}))
+
+	body := signWebhook(`{"type":"payment","uuid":"62f88b36","order_id":"97a75bf8","amount":"3.00000000","payment_amount":"3.00000000","payment_amount_usd":"0.23","merchant_amount":"2.94000000","commission":"0.06000000","is_final":true,"status":"paid","network":"tron","currency":"TRX","payer_currency":"TRX","additional_data":null,"txid":"6f0d9c"}`, "payment-key")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body)))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("got status %d (%s)", recorder.Code, recorder.Body)
+	}
+	if received == nil {
+		t.Fatal("handler was not called")
+	}
+	if received.PaymentStatus != cryptomus.StatusPaid || received.PayoutStatus != "" || !received.IsFinal || received.OrderID != "97a75bf8" || received.AdditionalData != "" {
+		t.Errorf("unexpected update %+v", received)
+	}
+	if want := big.NewRat(294, 100); received.MerchantAmount == nil || received.MerchantAmount.Cmp(want) != 0 {
+		t.Errorf("got merchant amount %v, want %v", received.MerchantAmount, want)
+	}
+	if received.PayerAmount != nil {
+		t.Errorf("got payer amount %v for a payment", received.PayerAmount)
+	}
+
+	received = nil
+	tampered := strings.Replace(body, `"2.94000000"`, `"29.4000000"`, 1)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(tampered)))
+	if recorder.Code != http.StatusUnauthorized || received != nil {
+		t.Errorf("tampered update: got status %d, handler called: %v", recorder.Code, received != nil)
+	}
+}
+
+func TestUpdateParse(t *testing.T) {
+	update, err := cryptomus.ParseWebhook([]byte(`{"type": "payout", "status": "fail", "payer_amount": "207.00000000", "amount": "207"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parsed, err := update.Parse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if parsed.PayoutStatus != cryptomus.PayoutStatusFail || parsed.PaymentStatus != "" || parsed.PayerAmount.Cmp(big.NewRat(207, 1)) != 0 {
+		t.Errorf("unexpected update %+v", parsed)
+	}
+
+	bad := "2,94"
+	update.MerchantAmount = &bad
+	if _, err := update.Parse(); err == nil || !strings.Contains(err.Error(), "merchant_amount") {
+		t.Errorf("got error %v, want one for merchant_amount", err)
+	}
+}
+
+func TestHandleWebhook(t *testing.T) {
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	payment := `{"type":"payment","uuid":"62f88b36-a9d5-4fa6-aa26-e040c3dbf26d","order_id":"97a75bf8","amount":"3.00000000","merchant_amount":"2.94","is_final":true,"status":"paid","currency":"TRX"}`
+	payout := `{"type":"payout","uuid":"2b852d86-3cf1-43fb-b1bb-36f0b7d12151","order_id":"129359","amount":"207.00000000","is_final":true,"status":"paid","currency":"USDT"}`
+
+	parsed, err := merchant.HandlePaymentWebhook([]byte(signWebhook(payment, "payment-key")), "")
+	if err != nil {
+		t.Fatalf("error handling payment webhook: %v", err)
+	}
+	if parsed.PaymentStatus != cryptomus.StatusPaid || parsed.MerchantAmount.Cmp(big.NewRat(147, 50)) != 0 {
+		t.Errorf("unexpected parsed payment %+v", parsed)
+	}
+
+	// A sign passed separately is checked against the body as it is.
+	signed := signWebhook(payout, "payout-key")
+	sign := signed[strings.LastIndex(signed, `"sign":"`)+8 : len(signed)-2]
+	if parsed, err := merchant.HandlePayoutWebhook([]byte(payout), sign); err != nil || parsed.PayoutStatus != cryptomus.PayoutStatusPaid {
+		t.Errorf("got %+v, %v, want a paid payout", parsed, err)
+	}
+
+	failures := map[string]func() error{
+		"payout key for payment": func() error {
+			_, err := merchant.HandlePaymentWebhook([]byte(signWebhook(payment, "payout-key")), "")
+			return err
+		},
+		"payout to payment handler": func() error {
+			_, err := merchant.HandlePaymentWebhook([]byte(signWebhook(payout, "payout-key")), "")
+			return err
+		},
+		"payment to payout handler": func() error {
+			_, err := merchant.HandlePayoutWebhook([]byte(signWebhook(payment, "payment-key")), "")
+			return err
+		},
+		"unknown status": func() error {
+			body := strings.Replace(payment, `"paid"`, `"teleported"`, 1)
+			_, err := merchant.HandlePaymentWebhook([]byte(signWebhook(body, "payment-key")), "")
+			return err
+		},
+		"malformed amount": func() error {
+			body := strings.Replace(payment, `"2.94"`, `"2,94"`, 1)
+			_, err := merchant.HandlePaymentWebhook([]byte(signWebhook(body, "payment-key")), "")
+			return err
+		},
+		"no sign": func() error {
+			_, err := merchant.HandlePaymentWebhook([]byte(payment), "")
+			return err
+		},
+	}
+	for name, handle := range failures {
+		if err := handle(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
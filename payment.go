@@ -1,6 +1,13 @@
 package cryptomus
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+)
 
 // Payment defines the payment information from Cryptomus
 //
@@ -90,15 +97,22 @@ type Payment struct {
 	// Invoice currency code
 	Currency string `json:"currency"`
 	// Amount in crypto that will be credited to your balance. If invoice payer_currency is not specified, the value will be null.
-	MerchantAmount string `json:"merchant_amount"`
+	MerchantAmount *string `json:"merchant_amount"`
+	// Comments on the invoice, null if there are none
+	Comments *string `json:"comments"`
 	// Blockchain network code
 	Network string `json:"network"`
 	// Wallet address for payment
 	Address string `json:"address"`
-	// The address of the wallet from which the payment was made
-	From string `json:"from"`
+	// The address of the wallet from which the payment was made, null until the payment is seen on the blockchain
+	From *string `json:"from"`
 	// Transaction hash
-	TxID string `json:"txid"`
+	//
+	// The txid will be null if:
+	//  1. payment was paid by p2p (The payer withdrew funds from his Cryptomus account to the address indicated in the invoice and the payment was made without blockchain, only in our system)
+	//  2. Payment was not paid
+	//  3. Something was wrong with the payment or the client made a mistake and we marked it as ‘paid’ manually
+	TxID *string `json:"txid"`
 	// Payment status
 	//  - paid: The payment was successful and the client paid exactly as much as required.
 	//  - paid_over: The payment was successful and client paid more than required.
@@ -114,7 +128,7 @@ type Payment struct {
 	//  - refund_fail: An error occurred during the refund
 	//  - refund_paid: The refund was successful
 	//  - locked: Funds are locked due to the AML program
-	PaymentStatus string `json:"payment_status"`
+	PaymentStatus PaymentStatus `json:"payment_status"`
 	// URL payment page
 	URL string `json:"url"`
 	// Timestamp of expiration of the invoice
@@ -123,10 +137,127 @@ type Payment struct {
 	//
 	// When invoice is finalized it is impossible to pay an invoice (it's either paid or expired)
 	IsFinal bool `json:"is_final"`
-	// Additional information
-	AdditionalData string `json:"additional_data"`
+	// Additional information, null if none was passed when creating the invoice
+	AdditionalData *string `json:"additional_data"`
 	// Creation date of the invoice. Timezone is UTC+3
 	CreatedAt time.Time `json:"created_at"`
 	// Last invoice updated date. Timezone is UTC+3
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// HasTxID reports whether the payment has a transaction hash on the blockchain.
+func (p Payment) HasTxID() bool {
+	return p.TxID != nil && *p.TxID != ""
+}
+
+// paymentURISchemes maps Cryptomus network codes to the URI scheme wallets use for payment requests with an amount, and to the native coin of the network, the only currency the scheme requests.
+var paymentURISchemes = map[string]struct{ scheme, coin string }{
+	"btc":  {"bitcoin", "BTC"},
+	"bch":  {"bitcoincash", "BCH"},
+	"ltc":  {"litecoin", "LTC"},
+	"doge": {"dogecoin", "DOGE"},
+	"dash": {"dash", "DASH"},
+	"sol":  {"solana", "SOL"},
+}
+
+// PaymentURI returns a BIP21-style payment URI (e.g. bitcoin:ADDR?amount=X) for the payment, which pre-fills the address and payer amount in wallets.
+//
+// Payment URIs request the native coin of a network, so PaymentURI only supports payments whose payer currency is that coin: BTC on btc, BCH on bch, LTC on ltc, DOGE on doge, DASH on dash and SOL on sol.
+// It returns an error for tokens, e.g. USDT on tron or sol, since a wallet would read the amount as the native coin and ask the payer for the wrong asset.
+// It also returns an error if the payment has no address or payer amount yet.
+//
+// Use QRFromURI to render the URI as a QR code.
+func (p Payment) PaymentURI() (string, error) {
+	if p.Address == "" {
+		return "", errors.New("payment has no address")
+	}
+	if p.PayerAmount == "" {
+		return "", errors.New("payment has no payer amount")
+	}
+	uri, ok := paymentURISchemes[strings.ToLower(p.Network)]
+	if !ok {
+		return "", fmt.Errorf("no payment URI scheme for network %q", p.Network)
+	}
+	if !strings.EqualFold(p.PayerCurrency, uri.coin) {
+		return "", fmt.Errorf("no payment URI for %q on network %q: only the native coin %s can be requested", p.PayerCurrency, p.Network, uri.coin)
+	}
+
+	address := strings.TrimPrefix(p.Address, uri.scheme+":")
+	query := url.Values{}
+	query.Set("amount", p.PayerAmount)
+
+	return uri.scheme + ":" + address + "?" + query.Encode(), nil
+}
+
+// IsPayable reports whether the client can still pay the invoice: it is not final, has not expired and its status is check, process, confirm_check or wrong_amount_waiting.
+func (p Payment) IsPayable() bool {
+	if p.IsFinal {
+		return false
+	}
+	if p.ExpiredAt != 0 && !time.Now().Before(time.Unix(p.ExpiredAt, 0)) {
+		return false
+	}
+	switch p.PaymentStatus {
+	case StatusCheck, StatusProcess, StatusConfirmCheck, StatusWrongAmountWaiting:
+		return true
+	}
+	return false
+}
+
+// The decimal accessors below parse the monetary fields exactly, without float rounding.
+// They return nil and no error when the field is null or empty, and an error when it is not a decimal number.
+
+// AmountDecimal returns amount as an exact decimal.
+func (p Payment) AmountDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(p.Amount)
+}
+
+// PaymentAmountDecimal returns payment_amount as an exact decimal.
+func (p Payment) PaymentAmountDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(p.PaymentAmount)
+}
+
+// PayerAmountDecimal returns payer_amount as an exact decimal.
+func (p Payment) PayerAmountDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(p.PayerAmount)
+}
+
+// MerchantAmountDecimal returns merchant_amount as an exact decimal.
+func (p Payment) MerchantAmountDecimal() (*big.Rat, error) {
+	return parseNullableDecimal(p.MerchantAmount)
+}
+
+// DiscountDecimal returns discount as an exact decimal.
+func (p Payment) DiscountDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(p.Discount)
+}
+
+// EffectiveDiscountPercent returns the discount actually applied to the payment as a percentage with 2 decimal places, e.g. "5.00" for a 5% discount or "-5.00" for a 5% additional commission.
+//
+// It is computed from discount and payer_amount rather than taken from discount_percent, so it also covers discounts set for the whole service with SetDiscount, for which discount_percent is 0.
+// The percentage is of the undiscounted amount in payer_currency, payer_amount + discount, which is the invoice amount when currency and payer_currency are the same.
+//
+// It returns an error if payer_amount is not set yet, i.e. the payer has not chosen a currency, or if an amount is not a decimal number. An empty discount counts as no discount.
+func (p Payment) EffectiveDiscountPercent() (string, error) {
+	payerAmount, err := p.PayerAmountDecimal()
+	if err != nil {
+		return "", fmt.Errorf("error parsing payer_amount: %w", err)
+	}
+	if payerAmount == nil {
+		return "", errors.New("payer_amount is not set")
+	}
+	discount, err := p.DiscountDecimal()
+	if err != nil {
+		return "", fmt.Errorf("error parsing discount: %w", err)
+	}
+	if discount == nil {
+		discount = new(big.Rat)
+	}
+
+	base := new(big.Rat).Add(payerAmount, discount)
+	if base.Sign() == 0 {
+		return "", errors.New("undiscounted amount is zero")
+	}
+	percent := new(big.Rat).Quo(discount, base)
+	return percent.Mul(percent, big.NewRat(100, 1)).FloatString(2), nil
+}
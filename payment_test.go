@@ -0,0 +1,138 @@
+package cryptomus_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestPaymentURI(t *testing.T) {
+	tests := []struct {
+		name    string
+		payment cryptomus.Payment
+		want    string
+	}{
+		{"bitcoin", cryptomus.Payment{Network: "btc", PayerCurrency: "BTC", Address: "1BoatSLRHtKNngkdXEeobR76b53LETtpyT", PayerAmount: "0.00123"}, "bitcoin:1BoatSLRHtKNngkdXEeobR76b53LETtpyT?amount=0.00123"},
+		{"solana", cryptomus.Payment{Network: "SOL", PayerCurrency: "sol", Address: "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU", PayerAmount: "1.5"}, "solana:7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU?amount=1.5"},
+	}
+	for _, test := range tests {
+		uri, err := test.payment.PaymentURI()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if uri != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, uri, test.want)
+		}
+	}
+}
+
+func TestPaymentURIMissingFields(t *testing.T) {
+	for name, payment := range map[string]cryptomus.Payment{
+		"no address":        {Network: "btc", PayerCurrency: "BTC", PayerAmount: "0.1"},
+		"no payer amount":   {Network: "btc", PayerCurrency: "BTC", Address: "1BoatSLRHtKNngkdXEeobR76b53LETtpyT"},
+		"unknown network":   {Network: "unknown", PayerCurrency: "BTC", Address: "addr", PayerAmount: "0.1"},
+		"no payer currency": {Network: "btc", Address: "1BoatSLRHtKNngkdXEeobR76b53LETtpyT", PayerAmount: "0.1"},
+		"token on tron":     {Network: "tron", PayerCurrency: "USDT", Address: "TXhfYSWt2oKRrHAJVJeYRuit6ZzKuoEKXj", PayerAmount: "15"},
+		"native on tron":    {Network: "tron", PayerCurrency: "TRX", Address: "TXhfYSWt2oKRrHAJVJeYRuit6ZzKuoEKXj", PayerAmount: "15"},
+		"token on sol":      {Network: "sol", PayerCurrency: "USDT", Address: "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU", PayerAmount: "15"},
+	} {
+		if uri, err := payment.PaymentURI(); err == nil {
+			t.Errorf("%s: got %q, expected error", name, uri)
+		}
+	}
+}
+
+func TestPaymentIsPayable(t *testing.T) {
+	future := time.Now().Add(time.Hour).Unix()
+	past := time.Now().Add(-time.Hour).Unix()
+
+	for name, test := range map[string]struct {
+		payment cryptomus.Payment
+		want    bool
+	}{
+		"waiting":           {cryptomus.Payment{PaymentStatus: cryptomus.StatusCheck, ExpiredAt: future}, true},
+		"partially paid":    {cryptomus.Payment{PaymentStatus: cryptomus.StatusWrongAmountWaiting, ExpiredAt: future}, true},
+		"expired":           {cryptomus.Payment{PaymentStatus: cryptomus.StatusCheck, ExpiredAt: past}, false},
+		"final":             {cryptomus.Payment{PaymentStatus: cryptomus.StatusCheck, ExpiredAt: future, IsFinal: true}, false},
+		"paid":              {cryptomus.Payment{PaymentStatus: cryptomus.StatusPaid, ExpiredAt: future}, false},
+		"refund in process": {cryptomus.Payment{PaymentStatus: cryptomus.StatusRefundProcess, ExpiredAt: future}, false},
+	} {
+		if got := test.payment.IsPayable(); got != test.want {
+			t.Errorf("%s: got %v, want %v", name, got, test.want)
+		}
+	}
+}
+
+func TestPaymentAmountDecimals(t *testing.T) {
+	var payment cryptomus.Payment
+	body := `{"amount": "15.43500000", "payment_amount": "0.00000001", "payer_amount": "", "discount": "-0.12345678", "merchant_amount": null}`
+	if err := json.Unmarshal([]byte(body), &payment); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		value func() (*big.Rat, error)
+		want  string
+	}{
+		{"amount", payment.AmountDecimal, "15.435"},
+		{"payment_amount", payment.PaymentAmountDecimal, "0.00000001"},
+		{"discount", payment.DiscountDecimal, "-0.12345678"},
+	}
+	for _, test := range tests {
+		got, err := test.value()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		want, _ := new(big.Rat).SetString(test.want)
+		if got == nil || got.Cmp(want) != 0 {
+			t.Errorf("%s: got %v, want %s", test.name, got, test.want)
+		}
+	}
+
+	for name, value := range map[string]func() (*big.Rat, error){
+		"payer_amount":    payment.PayerAmountDecimal,
+		"merchant_amount": payment.MerchantAmountDecimal,
+	} {
+		got, err := value()
+		if err != nil || got != nil {
+			t.Errorf("%s: got %v, %v, want nil, nil", name, got, err)
+		}
+	}
+
+	payment.Amount = "1e3"
+	if _, err := payment.AmountDecimal(); err == nil {
+		t.Error("expected error for malformed amount")
+	}
+}
+
+func TestPaymentEffectiveDiscountPercent(t *testing.T) {
+	tests := []struct {
+		name    string
+		payment cryptomus.Payment
+		want    string
+	}{
+		{"commission", cryptomus.Payment{Amount: "15.00", PayerAmount: "15.75", Discount: "-0.75", DiscountPercent: -5, Currency: "USDT", PayerCurrency: "USDT"}, "-5.00"},
+		{"service discount", cryptomus.Payment{Amount: "20.00", PayerAmount: "0.00061617", Discount: "0.00003243", Currency: "USD", PayerCurrency: "BTC"}, "5.00"},
+		{"no discount", cryptomus.Payment{Amount: "10", PayerAmount: "10", Currency: "USDT", PayerCurrency: "USDT"}, "0.00"},
+	}
+	for _, test := range tests {
+		got, err := test.payment.EffectiveDiscountPercent()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if got != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, got, test.want)
+		}
+	}
+
+	if _, err := (cryptomus.Payment{Amount: "20.00", Currency: "USD"}).EffectiveDiscountPercent(); err == nil {
+		t.Error("expected error without payer_amount")
+	}
+	if _, err := (cryptomus.Payment{PayerAmount: "1,5", Discount: "0"}).EffectiveDiscountPercent(); err == nil {
+		t.Error("expected error for malformed payer_amount")
+	}
+}
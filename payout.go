@@ -1,5 +1,12 @@
 package cryptomus
 
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+)
+
 // Payout holds the response structure for a payout transaction request.
 //
 // See "Creating a payout" https://doc.cryptomus.com/business/payouts/creating-payout
@@ -48,19 +55,75 @@ type Payout struct {
 	//  - fail: Payout failed
 	//  - cancel: Payout cancelled
 	//  - system_fail: A system error has occurred
-	Status string `json:"status"`
+	Status PayoutStatus `json:"status"`
 	// Whether the payout is finalized
 	//
 	// The payout process is considered finalized once it has been successfully paid or if it has failed. In the event of a payout failure, the funds will be returned to your balance, requiring you to initiate the payout process again.
 	IsFinal bool `json:"is_final"`
-	// The remaining funds on the merchant's balance.
-	Balance float64 `json:"balance"`
+	// The remaining funds on the merchant's balance, e.g. "129". Cryptomus sends it as a number in CreatePayout and GetPayoutInformation and as a string in ListPayoutHistory.
+	Balance string `json:"balance"`
 	// Cryptocurrency code in which the payout will be actually made. The payout address will receive the payout currency. (only in CreatePayout)
 	PayerCurrency string `json:"payer_currency"`
-	// Amount in payer_currency of the payout. (only in CreatePayout)
-	PayerAmount float64 `json:"payer_amount"`
+	// Amount in payer_currency of the payout, e.g. "3". (only in CreatePayout)
+	PayerAmount string `json:"payer_amount"`
 	// Creation date of the payout. Timezone is UTC+3 (only in ListPayoutHistory)
 	CreatedAt string `json:"created_at"`
 	// Last payout updated date. Timezone is UTC+3 (only in ListPayoutHistory)
 	UpdatedAt string `json:"updated_at"`
 }
+
+// UnmarshalJSON decodes a payout, accepting balance and payer_amount as numbers or strings.
+func (p *Payout) UnmarshalJSON(data []byte) error {
+	type payout Payout
+	aux := struct {
+		Balance     json.RawMessage `json:"balance"`
+		PayerAmount json.RawMessage `json:"payer_amount"`
+		*payout
+	}{
+		payout: (*payout)(p),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var err error
+	if p.Balance, err = numberOrString(aux.Balance); err != nil {
+		return fmt.Errorf("error decoding balance: %w", err)
+	}
+	if p.PayerAmount, err = numberOrString(aux.PayerAmount); err != nil {
+		return fmt.Errorf("error decoding payer_amount: %w", err)
+	}
+	return nil
+}
+
+// AmountDecimal returns amount as an exact decimal, or nil if it is empty.
+func (p Payout) AmountDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(p.Amount)
+}
+
+// BalanceDecimal returns balance as an exact decimal, or nil if it is empty.
+func (p Payout) BalanceDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(p.Balance)
+}
+
+// PayerAmountDecimal returns payer_amount as an exact decimal, or nil if it is empty.
+func (p Payout) PayerAmountDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(p.PayerAmount)
+}
+
+// Created returns created_at as a time.Time, or the zero time if it is not set or cannot be parsed.
+//
+// Timestamps without an offset are taken as UTC+3, the timezone Cryptomus reports them in.
+func (p Payout) Created() time.Time {
+	t, _ := parseOrderTime(&p.CreatedAt)
+	return t
+}
+
+// Updated returns updated_at as a time.Time, or the zero time if it is not set or cannot be parsed.
+//
+// Timestamps without an offset are taken as UTC+3, the timezone Cryptomus reports them in.
+func (p Payout) Updated() time.Time {
+	t, _ := parseOrderTime(&p.UpdatedAt)
+	return t
+}
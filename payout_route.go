@@ -0,0 +1,125 @@
+package cryptomus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrNoPayoutRoute is returned by CheapestPayoutRoute when no payout service can send the amount from the business balances.
+var ErrNoPayoutRoute = errors.New("no payout route")
+
+// CheapestPayoutRoute finds the currency and network to pay fiatAmount of fiatCurrency, e.g. 100 USD, to address with the lowest fee, and returns the withdrawal to create with CreatePayout.
+//
+// Every available payout service is a candidate: the amount is converted to its currency with GetExchangeRate, and the service is skipped if the converted amount is outside its limits or more than the business balance in that currency can cover along with the fee.
+// The fees, fee_amount plus percent of the amount, are compared in fiatCurrency. The withdrawal is in fiatCurrency with to_currency and network set to the chosen service and is_subtract true, so the fee is taken from the balance as it was checked.
+//
+// The withdrawal has no order_id; set one before creating the payout. Otherwise it passes Validate.
+// An address is only valid on some networks, so make sure address fits the chosen one, or narrow the services of the merchant, before paying out. Rates and fees can change before the payout is created.
+//
+// The returned error wraps ErrNoPayoutRoute if no service fits.
+func (m *Merchant) CheapestPayoutRoute(fiatAmount, fiatCurrency, address string) (*Withdrawal, error) {
+	return m.CheapestPayoutRouteContext(context.Background(), fiatAmount, fiatCurrency, address)
+}
+
+// CheapestPayoutRouteContext is like CheapestPayoutRoute but sends the requests with the provided context.
+func (m *Merchant) CheapestPayoutRouteContext(ctx context.Context, fiatAmount, fiatCurrency, address string) (*Withdrawal, error) {
+	normalized, err := NormalizeAmount(fiatAmount)
+	if err != nil {
+		return nil, err
+	}
+	amount, _ := parseDecimal(normalized)
+
+	exchangeRates, err := GetExchangeRateContext(ctx, fiatCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("error getting exchange rates of %s: %w", fiatCurrency, err)
+	}
+	rates := make(map[string]*big.Rat, len(exchangeRates))
+	for _, exchangeRate := range exchangeRates {
+		if rate, err := parseDecimal(exchangeRate.Course); err == nil && rate.Sign() > 0 {
+			rates[strings.ToUpper(exchangeRate.To)] = rate
+		}
+	}
+	rates[strings.ToUpper(fiatCurrency)] = big.NewRat(1, 1)
+
+	services, err := m.ListPayoutServicesContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing payout services: %w", err)
+	}
+	balances, _, err := m.BalanceByCurrencyContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting balance: %w", err)
+	}
+
+	var best *Service
+	var bestFee *big.Rat
+	for _, service := range services {
+		rate, ok := rates[strings.ToUpper(service.Currency)]
+		if !service.IsAvailable || !ok {
+			continue
+		}
+		converted := new(big.Rat).Mul(amount, rate)
+		if supported, err := service.SupportsAmount(formatDecimal(converted, decimalPlaces)); err != nil || !supported {
+			continue
+		}
+
+		fee, err := payoutFee(service, converted)
+		if err != nil {
+			continue
+		}
+		balance, err := parseOptionalDecimal(balances[strings.ToUpper(service.Currency)].Balance)
+		if err != nil || balance == nil || balance.Cmp(new(big.Rat).Add(converted, fee)) < 0 {
+			continue
+		}
+
+		fiatFee := fee.Quo(fee, rate)
+		if best == nil || fiatFee.Cmp(bestFee) < 0 {
+			best, bestFee = &service, fiatFee
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("%w: for %s %s", ErrNoPayoutRoute, fiatAmount, fiatCurrency)
+	}
+
+	isSubtract := true
+	withdrawal := Withdrawal{
+		Amount:     normalized,
+		Currency:   fiatCurrency,
+		Address:    address,
+		IsSubtract: &isSubtract,
+		Network:    &best.Network,
+		ToCurrency: &best.Currency,
+	}
+	if strings.EqualFold(best.Currency, fiatCurrency) {
+		withdrawal.ToCurrency = nil
+	}
+
+	check := withdrawal
+	check.OrderID = "route"
+	if err := check.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating payout route: %w", err)
+	}
+	return &withdrawal, nil
+}
+
+// payoutFee returns the fee of service for a payout of amount in its currency: fee_amount plus percent of amount.
+func payoutFee(service Service, amount *big.Rat) (*big.Rat, error) {
+	fee := new(big.Rat)
+	feeAmount, err := service.FeeAmountDecimal()
+	if err != nil {
+		return nil, err
+	}
+	if feeAmount != nil {
+		fee.Add(fee, feeAmount)
+	}
+	percent, err := service.FeePercentDecimal()
+	if err != nil {
+		return nil, err
+	}
+	if percent != nil {
+		fee.Add(fee, new(big.Rat).Mul(amount, new(big.Rat).Quo(percent, big.NewRat(100, 1))))
+	}
+	return fee, nil
+}
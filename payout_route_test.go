@@ -0,0 +1,58 @@
+package cryptomus_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestCheapestPayoutRoute(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/exchange-rate/USD/list":
+			w.Write([]byte(`{"state": 0, "result": [{"from": "USD", "to": "USDT", "course": "1"}, {"from": "USD", "to": "TRX", "course": "8"}, {"from": "USD", "to": "BTC", "course": "0.00001"}]}`))
+		case "/v1/payout/services":
+			w.Write([]byte(`{"state": 0, "result": [
+				{"network": "ETH", "currency": "USDT", "is_available": false, "limit": {"min_amount": "1", "max_amount": "1000000"}, "commission": {"fee_amount": "0.01", "percent": "0"}},
+				{"network": "BSC", "currency": "USDT", "is_available": true, "limit": {"min_amount": "5", "max_amount": "1000000"}, "commission": {"fee_amount": "0.30", "percent": "1.00"}},
+				{"network": "TRON", "currency": "USDT", "is_available": true, "limit": {"min_amount": "10", "max_amount": "1000000"}, "commission": {"fee_amount": "1.00", "percent": "0.00"}},
+				{"network": "TRON", "currency": "TRX", "is_available": true, "limit": {"min_amount": "1", "max_amount": "1000000"}, "commission": {"fee_amount": "1.00", "percent": "0.00"}},
+				{"network": "BTC", "currency": "BTC", "is_available": true, "limit": {"min_amount": "0.0001", "max_amount": "100"}, "commission": {"fee_amount": "0.0001", "percent": "0"}}
+			]}`))
+		case "/v1/balance":
+			w.Write([]byte(`{"state": 0, "result": [{"balance": {"merchant": [
+				{"uuid": "1", "balance": "500.00000000", "currency_code": "USDT"},
+				{"uuid": "2", "balance": "100.00000000", "currency_code": "TRX"},
+				{"uuid": "3", "balance": "1.00000000", "currency_code": "BTC"}
+			], "user": []}}]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+
+	// TRX would be cheapest, but 100 USD is 800 TRX, more than the balance; USDT on TRON costs 1 USD, on BSC 1.30 USD.
+	withdrawal, err := merchant.CheapestPayoutRoute("100", "USD", "TDD97yguPESTpcrJMqU6h2ozZbibv4Vaqm")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *withdrawal.Network != "TRON" || *withdrawal.ToCurrency != "USDT" || withdrawal.Amount != "100" || withdrawal.Currency != "USD" || !*withdrawal.IsSubtract {
+		t.Errorf("unexpected withdrawal %+v", withdrawal)
+	}
+
+	// 10 USD is 80 TRX, which the balance covers with the fee of 1 TRX, worth 0.125 USD.
+	withdrawal, err = merchant.CheapestPayoutRoute("10", "USD", "TDD97yguPESTpcrJMqU6h2ozZbibv4Vaqm")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *withdrawal.Network != "TRON" || *withdrawal.ToCurrency != "TRX" {
+		t.Errorf("got %s on %s, want TRX on TRON", *withdrawal.ToCurrency, *withdrawal.Network)
+	}
+
+	if _, err := merchant.CheapestPayoutRoute("1000000", "USD", "TDD97yguPESTpcrJMqU6h2ozZbibv4Vaqm"); !errors.Is(err, cryptomus.ErrNoPayoutRoute) {
+		t.Errorf("got error %v, want ErrNoPayoutRoute", err)
+	}
+}
@@ -0,0 +1,26 @@
+package cryptomus_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestPayoutTimes(t *testing.T) {
+	payout := cryptomus.Payout{CreatedAt: "2023-06-21T17:25:55+03:00", UpdatedAt: "2023-06-21 17:34:38"}
+
+	if want := time.Date(2023, 6, 21, 14, 25, 55, 0, time.UTC); !payout.Created().Equal(want) {
+		t.Errorf("got created %v, want %v", payout.Created(), want)
+	}
+	if want := time.Date(2023, 6, 21, 14, 34, 38, 0, time.UTC); !payout.Updated().Equal(want) {
+		t.Errorf("got updated %v, want %v", payout.Updated(), want)
+	}
+
+	if created := (cryptomus.Payout{}).Created(); !created.IsZero() {
+		t.Errorf("got created %v for an empty created_at, want the zero time", created)
+	}
+	if updated := (cryptomus.Payout{UpdatedAt: "yesterday"}).Updated(); !updated.IsZero() {
+		t.Errorf("got updated %v for a malformed updated_at, want the zero time", updated)
+	}
+}
@@ -0,0 +1,20 @@
+package cryptomus
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// publicClient sends the requests of the public market endpoints, which need no credentials.
+var publicClient = &http.Client{Timeout: 10 * time.Second}
+
+func sendPublicRequest(ctx context.Context, path string) (*http.Response, error) {
+	httpRequest, err := http.NewRequestWithContext(ctx, "GET", urlEndpoint+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("User-Agent", defaultUserAgent)
+
+	return publicClient.Do(httpRequest)
+}
@@ -0,0 +1,468 @@
+package cryptomus
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// QRFromURI renders uri as a QR code image so it can be scanned by a wallet.
+//
+// The QR code is encoded in byte mode with error correction level M, using the smallest version (1 to 10) that fits the uri.
+// Each module is drawn as an 8x8 pixel square and the code is surrounded by the 4 module quiet zone required by the specification.
+//
+// Payment URIs built by Payment.PaymentURI fit comfortably; uris longer than 213 bytes return an error.
+func QRFromURI(uri string) (image.Image, error) {
+	if uri == "" {
+		return nil, errors.New("empty uri")
+	}
+
+	modules, err := encodeQR([]byte(uri))
+	if err != nil {
+		return nil, err
+	}
+
+	const (
+		scale      = 8
+		quietZone  = 4
+		lightColor = 0xff
+		darkColor  = 0x00
+	)
+
+	size := (len(modules) + 2*quietZone) * scale
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for i := range img.Pix {
+		img.Pix[i] = lightColor
+	}
+	for y, row := range modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetGray((x+quietZone)*scale+dx, (y+quietZone)*scale+dy, color.Gray{Y: darkColor})
+				}
+			}
+		}
+	}
+
+	return img, nil
+}
+
+// qrVersion describes the error correction block structure of a QR code version at error correction level M.
+type qrVersion struct {
+	// Number of error correction codewords in each block
+	ecPerBlock int
+	// Number of data codewords in each block, one entry per block
+	blocks []int
+	// Center coordinates of the alignment patterns
+	alignment []int
+}
+
+// qrVersions lists versions 1 to 10 at error correction level M, indexed by version-1.
+var qrVersions = []qrVersion{
+	{10, []int{16}, nil},
+	{16, []int{28}, []int{6, 18}},
+	{26, []int{44}, []int{6, 22}},
+	{18, []int{32, 32}, []int{6, 26}},
+	{24, []int{43, 43}, []int{6, 30}},
+	{16, []int{27, 27, 27, 27}, []int{6, 34}},
+	{18, []int{31, 31, 31, 31}, []int{6, 22, 38}},
+	{22, []int{38, 38, 39, 39}, []int{6, 24, 42}},
+	{22, []int{36, 36, 36, 37, 37}, []int{6, 26, 46}},
+	{26, []int{43, 43, 43, 43, 44}, []int{6, 28, 50}},
+}
+
+func (v qrVersion) dataCodewords() int {
+	n := 0
+	for _, b := range v.blocks {
+		n += b
+	}
+	return n
+}
+
+// qrBits accumulates a bit stream, most significant bit first.
+type qrBits struct {
+	bytes []byte
+	n     int
+}
+
+func (b *qrBits) write(value uint, length int) {
+	for i := length - 1; i >= 0; i-- {
+		if b.n%8 == 0 {
+			b.bytes = append(b.bytes, 0)
+		}
+		if value>>uint(i)&1 == 1 {
+			b.bytes[b.n/8] |= 0x80 >> uint(b.n%8)
+		}
+		b.n++
+	}
+}
+
+// encodeQR encodes data in byte mode and returns the QR code modules as rows of dark (true) and light (false) values.
+func encodeQR(data []byte) ([][]bool, error) {
+	version := 0
+	for i, v := range qrVersions {
+		countBits := 8
+		if i+1 >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(data) <= 8*v.dataCodewords() {
+			version = i + 1
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("data too long for a QR code: %d bytes", len(data))
+	}
+	v := qrVersions[version-1]
+
+	var bits qrBits
+	bits.write(0b0100, 4)
+	if version < 10 {
+		bits.write(uint(len(data)), 8)
+	} else {
+		bits.write(uint(len(data)), 16)
+	}
+	for _, c := range data {
+		bits.write(uint(c), 8)
+	}
+	capacity := 8 * v.dataCodewords()
+	bits.write(0, min(4, capacity-bits.n))
+	if bits.n%8 != 0 {
+		bits.write(0, 8-bits.n%8)
+	}
+	for pad := uint(0xec); bits.n < capacity; pad ^= 0xec ^ 0x11 {
+		bits.write(pad, 8)
+	}
+
+	codewords := interleaveQR(bits.bytes, v)
+
+	size := 17 + 4*version
+	q := &qrMatrix{size: size}
+	q.modules = make([][]bool, size)
+	q.function = make([][]bool, size)
+	for i := 0; i < size; i++ {
+		q.modules[i] = make([]bool, size)
+		q.function[i] = make([]bool, size)
+	}
+	q.drawFunctionPatterns(version, v)
+	q.drawCodewords(codewords)
+
+	best, bestPenalty := -1, 0
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormat(mask)
+		if penalty := q.penalty(); best < 0 || penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+		q.applyMask(mask)
+	}
+	q.applyMask(best)
+	q.drawFormat(best)
+
+	return q.modules, nil
+}
+
+// interleaveQR splits data into blocks, appends the Reed-Solomon error correction codewords of each block and interleaves the result.
+func interleaveQR(data []byte, v qrVersion) []byte {
+	divisor := reedSolomonDivisor(v.ecPerBlock)
+	dataBlocks := make([][]byte, len(v.blocks))
+	ecBlocks := make([][]byte, len(v.blocks))
+	offset := 0
+	for i, n := range v.blocks {
+		dataBlocks[i] = data[offset : offset+n]
+		ecBlocks[i] = reedSolomonRemainder(dataBlocks[i], divisor)
+		offset += n
+	}
+
+	var result []byte
+	for i := 0; i < v.blocks[len(v.blocks)-1]; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < v.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies two elements of GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x, y byte) byte {
+	var z byte
+	for i := 7; i >= 0; i-- {
+		carry := z >> 7
+		z <<= 1
+		if carry == 1 {
+			z ^= 0x1d
+		}
+		if y>>uint(i)&1 == 1 {
+			z ^= x
+		}
+	}
+	return z
+}
+
+// reedSolomonDivisor returns the coefficients of the generator polynomial of the given degree, excluding the leading 1.
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := 0; j < degree; j++ {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < degree {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// reedSolomonRemainder returns the error correction codewords of data for the given generator polynomial.
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMultiply(d, factor)
+		}
+	}
+	return result
+}
+
+// qrMatrix holds the modules of a QR code while it is drawn, along with which of them belong to function patterns.
+type qrMatrix struct {
+	size     int
+	modules  [][]bool
+	function [][]bool
+}
+
+func (q *qrMatrix) set(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.function[y][x] = true
+}
+
+func (q *qrMatrix) drawFunctionPatterns(version int, v qrVersion) {
+	for i := 0; i < q.size; i++ {
+		q.set(6, i, i%2 == 0)
+		q.set(i, 6, i%2 == 0)
+	}
+
+	q.drawFinder(3, 3)
+	q.drawFinder(q.size-4, 3)
+	q.drawFinder(3, q.size-4)
+
+	n := len(v.alignment)
+	for i, x := range v.alignment {
+		for j, y := range v.alignment {
+			if (i == 0 && j == 0) || (i == 0 && j == n-1) || (i == n-1 && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					q.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve the format areas, they are drawn once the mask is chosen.
+	q.drawFormat(0)
+
+	if version >= 7 {
+		rem := version
+		for i := 0; i < 12; i++ {
+			rem = rem<<1 ^ (rem>>11)*0x1f25
+		}
+		bits := version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := bits>>uint(i)&1 == 1
+			a, b := q.size-11+i%3, i/3
+			q.set(a, b, dark)
+			q.set(b, a, dark)
+		}
+	}
+}
+
+// drawFinder draws a finder pattern and its separator centered at (x, y).
+func (q *qrMatrix) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= q.size || yy < 0 || yy >= q.size {
+				continue
+			}
+			d := max(abs(dx), abs(dy))
+			q.set(xx, yy, d != 2 && d != 4)
+		}
+	}
+}
+
+// drawFormat draws both copies of the format information for error correction level M and the given mask.
+func (q *qrMatrix) drawFormat(mask int) {
+	data := mask // Level M is encoded as 00.
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>uint(i)&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		q.set(8, i, bit(i))
+	}
+	q.set(8, 7, bit(6))
+	q.set(8, 8, bit(7))
+	q.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.set(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		q.set(q.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.set(8, q.size-15+i, bit(i))
+	}
+	q.set(8, q.size-8, true)
+}
+
+// drawCodewords places codewords in the zigzag order defined by the specification, skipping function modules.
+func (q *qrMatrix) drawCodewords(codewords []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = q.size - 1 - vert
+				}
+				if q.function[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				q.modules[y][x] = codewords[i/8]>>uint(7-i%8)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// applyMask flips every data module selected by mask. Applying the same mask twice undoes it.
+func (q *qrMatrix) applyMask(mask int) {
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.function[y][x] {
+				continue
+			}
+			var flip bool
+			switch mask {
+			case 0:
+				flip = (x+y)%2 == 0
+			case 1:
+				flip = y%2 == 0
+			case 2:
+				flip = x%3 == 0
+			case 3:
+				flip = (x+y)%3 == 0
+			case 4:
+				flip = (x/3+y/2)%2 == 0
+			case 5:
+				flip = x*y%2+x*y%3 == 0
+			case 6:
+				flip = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				flip = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if flip {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the matrix using the four rules of the specification; the mask with the lowest score is used.
+func (q *qrMatrix) penalty() int {
+	at := func(x, y int, vertical bool) bool {
+		if vertical {
+			return q.modules[x][y]
+		}
+		return q.modules[y][x]
+	}
+
+	score := 0
+	finderLike := [][]bool{
+		{true, false, true, true, true, false, true, false, false, false, false},
+		{false, false, false, false, true, false, true, true, true, false, true},
+	}
+	for _, vertical := range []bool{false, true} {
+		for y := 0; y < q.size; y++ {
+			run := 1
+			for x := 1; x <= q.size; x++ {
+				if x < q.size && at(x, y, vertical) == at(x-1, y, vertical) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += 3 + run - 5
+				}
+				run = 1
+			}
+
+			for x := 0; x+11 <= q.size; x++ {
+				for _, pattern := range finderLike {
+					match := true
+					for k, dark := range pattern {
+						if at(x+k, y, vertical) != dark {
+							match = false
+							break
+						}
+					}
+					if match {
+						score += 40
+					}
+				}
+			}
+		}
+	}
+
+	dark := 0
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.modules[y][x] {
+				dark++
+			}
+			if x+1 < q.size && y+1 < q.size {
+				c := q.modules[y][x]
+				if c == q.modules[y][x+1] && c == q.modules[y+1][x] && c == q.modules[y+1][x+1] {
+					score += 3
+				}
+			}
+		}
+	}
+	percent := dark * 100 / (q.size * q.size)
+	score += abs(percent-50) / 5 * 10
+
+	return score
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,377 @@
+package cryptomus_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestQRFromURI(t *testing.T) {
+	img, err := cryptomus.QRFromURI("bitcoin:1BoatSLRHtKNngkdXEeobR76b53LETtpyT?amount=0.00123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 57 bytes need version 4 (33x33 modules) plus a 4 module quiet zone on each side, 8 pixels per module.
+	if size := img.Bounds().Dx(); size != (33+8)*8 {
+		t.Fatalf("got image size %d, want %d", size, (33+8)*8)
+	}
+
+	isDark := func(x, y int) bool {
+		return color.GrayModel.Convert(img.At((x+4)*8, (y+4)*8)).(color.Gray).Y == 0
+	}
+	if isDark(-1, -1) {
+		t.Error("quiet zone should be light")
+	}
+	// Top left finder pattern: dark outer ring, light ring, dark center.
+	if !isDark(0, 0) || isDark(1, 1) || !isDark(3, 3) || isDark(7, 7) {
+		t.Error("finder pattern not found in the top left corner")
+	}
+}
+
+func TestQRFromURITooLong(t *testing.T) {
+	if _, err := cryptomus.QRFromURI(string(make([]byte, 214))); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// qrFormatM lists the 15-bit format information of error correction level M for masks 0 to 7, from table C.1 of ISO/IEC 18004.
+var qrFormatM = []int{
+	0b101010000010010, 0b101000100100101, 0b101111001111100, 0b101101101001011,
+	0b100010111111001, 0b100000011001110, 0b100111110010111, 0b100101010100000,
+}
+
+// qrVersionInfo lists the 18-bit version information of versions 7 to 10, from table D.1 of ISO/IEC 18004.
+var qrVersionInfo = map[int]int{7: 0x07c94, 8: 0x085bc, 9: 0x09a99, 10: 0x0a4d3}
+
+// qrBlocksM lists the error correction codewords per block and the data codewords of each block for versions 1 to 10 at level M, from table 9 of ISO/IEC 18004.
+var qrBlocksM = map[int]struct {
+	ec     int
+	blocks []int
+}{
+	1: {10, []int{16}}, 2: {16, []int{28}}, 3: {26, []int{44}}, 4: {18, []int{32, 32}}, 5: {24, []int{43, 43}},
+	6: {16, []int{27, 27, 27, 27}}, 7: {18, []int{31, 31, 31, 31}}, 8: {22, []int{38, 38, 39, 39}},
+	9: {22, []int{36, 36, 36, 37, 37}}, 10: {26, []int{43, 43, 43, 43, 44}},
+}
+
+// qrAlignment lists the alignment pattern center coordinates of versions 1 to 10, from annex E of ISO/IEC 18004.
+var qrAlignment = map[int][]int{
+	2: {6, 18}, 3: {6, 22}, 4: {6, 26}, 5: {6, 30}, 6: {6, 34}, 7: {6, 22, 38}, 8: {6, 24, 42}, 9: {6, 26, 46}, 10: {6, 28, 50},
+}
+
+// TestQRFromURIDecodes reads back the rendered codes of every version the encoder uses, following ISO/IEC 18004 independently of the encoder:
+// the format and version information must match the tables of the specification, every block must be a Reed-Solomon codeword, and the data must decode to the uri.
+func TestQRFromURIDecodes(t *testing.T) {
+	for _, test := range []struct {
+		length, version int
+	}{
+		{1, 1}, {14, 1}, {15, 2}, {57, 4}, {106, 6}, {107, 7}, {150, 8}, {170, 9}, {180, 9}, {181, 10}, {213, 10},
+	} {
+		uri := ("bitcoin:" + strings.Repeat("x", test.length))[:test.length]
+		img, err := cryptomus.QRFromURI(uri)
+		if err != nil {
+			t.Fatalf("%d bytes: unexpected error: %v", test.length, err)
+		}
+		got, err := decodeQR(img, test.version)
+		if err != nil {
+			t.Errorf("%d bytes: %v", test.length, err)
+			continue
+		}
+		if got != uri {
+			t.Errorf("%d bytes: decoded %q, want %q", test.length, got, uri)
+		}
+	}
+}
+
+// qrError is the error returned by decodeQR.
+type qrError string
+
+func (e qrError) Error() string { return string(e) }
+
+// decodeQR decodes a byte mode QR code of the given version at level M drawn by QRFromURI.
+func decodeQR(img image.Image, version int) (string, error) {
+	size := 17 + 4*version
+	if img.Bounds().Dx() != (size+8)*8 || img.Bounds().Dy() != (size+8)*8 {
+		return "", qrError("unexpected image size for the version")
+	}
+	dark := func(x, y int) bool {
+		return color.GrayModel.Convert(img.At((x+4)*8+4, (y+4)*8+4)).(color.Gray).Y == 0
+	}
+	for i := -4; i < size+4; i++ {
+		if dark(i, -1) || dark(i, size) || dark(-1, i) || dark(size, i) {
+			return "", qrError("quiet zone is not light")
+		}
+	}
+
+	function := make([][]bool, size)
+	for row := range function {
+		function[row] = make([]bool, size)
+	}
+	reserve := func(row0, col0, rows, cols int) {
+		for row := max(row0, 0); row < min(row0+rows, size); row++ {
+			for col := max(col0, 0); col < min(col0+cols, size); col++ {
+				function[row][col] = true
+			}
+		}
+	}
+
+	// Finder patterns, separators and format information.
+	for _, corner := range [][2]int{{0, 0}, {0, size - 7}, {size - 7, 0}} {
+		for row := -1; row <= 7; row++ {
+			for col := -1; col <= 7; col++ {
+				r, c := corner[0]+row, corner[1]+col
+				if r < 0 || r >= size || c < 0 || c >= size {
+					continue
+				}
+				d := max(abs(row-3), abs(col-3))
+				if dark(c, r) != (d <= 1 || d == 3) {
+					return "", qrError("broken finder pattern")
+				}
+			}
+		}
+		reserve(corner[0]-1, corner[1]-1, 9, 9)
+	}
+	reserve(8, 0, 1, 9)
+	reserve(0, 8, 9, 1)
+	reserve(8, size-8, 1, 8)
+	reserve(size-8, 8, 8, 1)
+	if !dark(8, size-8) {
+		return "", qrError("dark module is light")
+	}
+
+	// Alignment patterns, except where they would overlap a finder pattern.
+	centers := qrAlignment[version]
+	for _, row := range centers {
+		for _, col := range centers {
+			if function[row][col] {
+				continue
+			}
+			for dr := -2; dr <= 2; dr++ {
+				for dc := -2; dc <= 2; dc++ {
+					if dark(col+dc, row+dr) != (max(abs(dr), abs(dc)) != 1) {
+						return "", qrError("broken alignment pattern")
+					}
+				}
+			}
+			reserve(row-2, col-2, 5, 5)
+		}
+	}
+
+	// Timing patterns, which the alignment patterns on row and column 6 are part of.
+	for i := 8; i < size-8; i++ {
+		if dark(i, 6) != (i%2 == 0) || dark(6, i) != (i%2 == 0) {
+			return "", qrError("broken timing pattern")
+		}
+	}
+	reserve(6, 0, 1, size)
+	reserve(0, 6, size, 1)
+
+	// Version information, in the 6x3 blocks next to the top right and bottom left finder patterns.
+	if version >= 7 {
+		topRight, bottomLeft := 0, 0
+		for i := 17; i >= 0; i-- {
+			row, col := i/3, size-11+i%3
+			topRight = topRight<<1 | bit(dark(col, row))
+			bottomLeft = bottomLeft<<1 | bit(dark(row, col))
+		}
+		if topRight != qrVersionInfo[version] || bottomLeft != qrVersionInfo[version] {
+			return "", qrError("wrong version information")
+		}
+		reserve(0, size-11, 6, 3)
+		reserve(size-11, 0, 3, 6)
+	}
+
+	// Format information, most significant bit first: around the top left finder pattern, then below the top right and right of the bottom left one.
+	first, second := 0, 0
+	for _, position := range [][2]int{{8, 0}, {8, 1}, {8, 2}, {8, 3}, {8, 4}, {8, 5}, {8, 7}, {8, 8}, {7, 8}, {5, 8}, {4, 8}, {3, 8}, {2, 8}, {1, 8}, {0, 8}} {
+		first = first<<1 | bit(dark(position[1], position[0]))
+	}
+	for i := range 15 {
+		if i < 7 {
+			second = second<<1 | bit(dark(8, size-1-i))
+		} else {
+			second = second<<1 | bit(dark(size-15+i, 8))
+		}
+	}
+	if first != second {
+		return "", qrError("format information copies differ")
+	}
+	mask := -1
+	for m, format := range qrFormatM {
+		if first == format {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		return "", qrError("format information is not level M")
+	}
+
+	// Data modules, unmasked, in two module wide columns from the right, alternately upwards and downwards.
+	masked := func(row, col int) bool {
+		switch mask {
+		case 0:
+			return (row+col)%2 == 0
+		case 1:
+			return row%2 == 0
+		case 2:
+			return col%3 == 0
+		case 3:
+			return (row+col)%3 == 0
+		case 4:
+			return (row/2+col/3)%2 == 0
+		case 5:
+			return row*col%2+row*col%3 == 0
+		case 6:
+			return (row*col%2+row*col%3)%2 == 0
+		default:
+			return ((row+col)%2+row*col%3)%2 == 0
+		}
+	}
+	var stream []byte
+	n := 0
+	upwards := true
+	for right := size - 1; right > 0; right -= 2 {
+		if right == 6 {
+			right--
+		}
+		for i := range size {
+			row := i
+			if upwards {
+				row = size - 1 - i
+			}
+			for _, col := range []int{right, right - 1} {
+				if function[row][col] {
+					continue
+				}
+				if n%8 == 0 {
+					stream = append(stream, 0)
+				}
+				if dark(col, row) != masked(row, col) {
+					stream[n/8] |= 0x80 >> (n % 8)
+				}
+				n++
+			}
+		}
+		upwards = !upwards
+	}
+
+	// Deinterleave the blocks and check each against its error correction codewords.
+	layout := qrBlocksM[version]
+	blocks := make([][]byte, len(layout.blocks))
+	i := 0
+	for k := range layout.blocks[len(layout.blocks)-1] {
+		for b, length := range layout.blocks {
+			if k < length {
+				blocks[b] = append(blocks[b], stream[i])
+				i++
+			}
+		}
+	}
+	for range layout.ec {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], stream[i])
+			i++
+		}
+	}
+	var data []byte
+	for b, block := range blocks {
+		if !isReedSolomonCodeword(block, layout.ec) {
+			return "", qrError("block fails the Reed-Solomon check")
+		}
+		data = append(data, block[:layout.blocks[b]]...)
+	}
+
+	// Byte mode segment, terminator and pad codewords.
+	read := func(offset, length int) int {
+		value := 0
+		for i := offset; i < offset+length; i++ {
+			value = value<<1 | int(data[i/8]>>(7-i%8)&1)
+		}
+		return value
+	}
+	if read(0, 4) != 0b0100 {
+		return "", qrError("not byte mode")
+	}
+	countBits := 8
+	if version >= 10 {
+		countBits = 16
+	}
+	count := read(4, countBits)
+	offset := 4 + countBits
+	if offset+8*count > 8*len(data) {
+		return "", qrError("character count exceeds the data")
+	}
+	var decoded bytes.Buffer
+	for range count {
+		decoded.WriteByte(byte(read(offset, 8)))
+		offset += 8
+	}
+	padStart := (offset + min(4, 8*len(data)-offset) + 7) / 8 * 8
+	for ; offset < padStart; offset++ {
+		if read(offset, 1) != 0 {
+			return "", qrError("terminator or bit padding is not zero")
+		}
+	}
+	for pad := 0; offset < 8*len(data); pad++ {
+		want := 0xec
+		if pad%2 == 1 {
+			want = 0x11
+		}
+		if read(offset, 8) != want {
+			return "", qrError("wrong pad codeword")
+		}
+		offset += 8
+	}
+	return decoded.String(), nil
+}
+
+// isReedSolomonCodeword reports whether block, data followed by ec error correction codewords, is divisible by the generator polynomial of the specification,
+// i.e. evaluates to zero at the roots 2^0 to 2^(ec-1) of GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func isReedSolomonCodeword(block []byte, ec int) bool {
+	var exp [255]byte
+	var log [256]int
+	x := 1
+	for i := range 255 {
+		exp[i] = byte(x)
+		log[x] = i
+		x <<= 1
+		if x >= 256 {
+			x ^= 0x11d
+		}
+	}
+	multiply := func(a, b byte) byte {
+		if a == 0 || b == 0 {
+			return 0
+		}
+		return exp[(log[a]+log[b])%255]
+	}
+
+	for i := range ec {
+		var value byte
+		for _, c := range block {
+			value = multiply(value, exp[i]) ^ c
+		}
+		if value != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func bit(dark bool) int {
+	if dark {
+		return 1
+	}
+	return 0
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
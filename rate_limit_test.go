@@ -0,0 +1,48 @@
+package cryptomus_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		times = append(times, time.Now())
+		w.Write([]byte(`{"state": 0, "result": []}`))
+	}))
+	defer server.Close()
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL), cryptomus.WithRateLimit(20, 1))
+	for range 4 {
+		if _, _, err := merchant.GetBalance(); err != nil {
+			t.Fatalf("error getting balance: %v", err)
+		}
+	}
+
+	// At 20 requests per second without burst, the requests are at least 50ms apart.
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap < 40*time.Millisecond {
+			t.Errorf("request %d came %v after the previous one, want about 50ms", i, gap)
+		}
+	}
+
+	slow := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL), cryptomus.WithRateLimit(0.1, 1))
+	if _, _, err := slow.GetBalance(); err != nil {
+		t.Fatalf("error getting balance: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := slow.GetBalanceContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want context.DeadlineExceeded while waiting for the limit", err)
+	}
+	if len(times) != 5 {
+		t.Errorf("got %d requests, want 5", len(times))
+	}
+}
@@ -0,0 +1,76 @@
+package cryptomus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DoPayment signs body with the payment API key, sends it to the endpoint at path, e.g. "v1/payment/info", and returns the raw result of the response.
+//
+// It is an escape hatch for endpoints this package does not wrap yet; prefer the typed methods where they exist.
+// body is marshaled like the body of every other request. A failed request returns an *APIError, as the typed methods do.
+//
+// See "Request format" https://doc.cryptomus.com/business/general/request-format
+func (m *Merchant) DoPayment(ctx context.Context, method, path string, body any) (json.RawMessage, error) {
+	httpResponse, err := m.sendPaymentRequest(ctx, method, strings.TrimPrefix(path, "/"), body)
+	if err != nil {
+		return nil, err
+	}
+	return m.decodeRaw(httpResponse)
+}
+
+// DoPayout is like DoPayment for payout endpoints, signing body with the payout API key.
+func (m *Merchant) DoPayout(ctx context.Context, method, path string, body any) (json.RawMessage, error) {
+	httpResponse, err := m.sendPayoutRequest(ctx, method, strings.TrimPrefix(path, "/"), body)
+	if err != nil {
+		return nil, err
+	}
+	return m.decodeRaw(httpResponse)
+}
+
+// DoPayment is like Merchant.DoPayment for the endpoints of the personal API, e.g. "v2/user-api/convert/order-list/".
+//
+// See "Request format" https://doc.cryptomus.com/personal/general/request-format
+func (u *User) DoPayment(ctx context.Context, method, path string, body any) (json.RawMessage, error) {
+	httpResponse, err := u.sendPaymentRequest(ctx, method, strings.TrimPrefix(path, "/"), body)
+	if err != nil {
+		return nil, err
+	}
+	return u.decodeRaw(httpResponse)
+}
+
+// DoPayout is like DoPayment for payout endpoints, signing body with the payout API key.
+func (u *User) DoPayout(ctx context.Context, method, path string, body any) (json.RawMessage, error) {
+	httpResponse, err := u.sendPayoutRequest(ctx, method, strings.TrimPrefix(path, "/"), body)
+	if err != nil {
+		return nil, err
+	}
+	return u.decodeRaw(httpResponse)
+}
+
+// decodeRaw closes the body of httpResponse and returns its result, or an *APIError if the request failed.
+func (o *options) decodeRaw(httpResponse *http.Response) (json.RawMessage, error) {
+	defer httpResponse.Body.Close()
+
+	var response = struct {
+		State   int             `json:"state"`
+		Result  json.RawMessage `json:"result"`
+		Message string          `json:"message"`
+		Errors  responseErrors  `json:"errors"`
+		Code    int             `json:"code"`
+		Error   string          `json:"error"`
+	}{}
+
+	if err := o.decode(httpResponse.Body, &response); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
+	}
+
+	return response.Result, nil
+}
@@ -0,0 +1,50 @@
+package cryptomus_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestDoPayment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path != "/v1/payment/new-endpoint" || string(body) != `{"uuid":"70b8db5c"}` {
+			t.Errorf("got %s %s with body %s", r.Method, r.URL.Path, body)
+		}
+		if r.Header.Get("merchant") != "merchant" || r.Header.Get("sign") == "" {
+			t.Errorf("request is not signed: %v", r.Header)
+		}
+		if r.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"state": 1, "errors": {"uuid": ["validation.required"]}}`))
+			return
+		}
+		w.Write([]byte(`{"state": 0, "result": {"uuid": "70b8db5c", "new_field": 42}}`))
+	}))
+	defer server.Close()
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+	body := map[string]string{"uuid": "70b8db5c"}
+
+	result, err := merchant.DoPayment(context.Background(), http.MethodPost, "/v1/payment/new-endpoint", body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded struct {
+		NewField int `json:"new_field"`
+	}
+	if err := json.Unmarshal(result, &decoded); err != nil || decoded.NewField != 42 {
+		t.Errorf("got result %s, %v", result, err)
+	}
+
+	_, err = merchant.DoPayout(context.Background(), http.MethodPost, "v1/payment/new-endpoint?fail=1", body)
+	if !cryptomus.IsValidationError(err) {
+		t.Errorf("got error %v, want a validation error", err)
+	}
+}
@@ -1,7 +1,10 @@
 package cryptomus
 
+import "strings"
+
 // Record (invoice/payout) represents parameters required to identify an entity by either UUID or OrderID.
 // Only one of UUID or OrderID is required; if both are provided, OrderID will be prioritized for identification.
+// Use ByUUID or ByOrderID to set only one of them.
 //
 // See "Payment information" https://doc.cryptomus.com/business/payments/payment-information
 //
@@ -26,3 +29,29 @@ type RecordID struct {
 	//    required_without: uuid
 	OrderID *string `json:"order_id,omitempty"`
 }
+
+// ByUUID returns a RecordID identifying a record by its uuid only.
+func ByUUID(uuid string) RecordID {
+	return RecordID{UUID: &uuid}
+}
+
+// ByOrderID returns a RecordID identifying a record by its order_id only.
+func ByOrderID(orderID string) RecordID {
+	return RecordID{OrderID: &orderID}
+}
+
+// Validate checks that at least one of UUID or OrderID is set, so a request that Cryptomus would reject with "validation.required_without" fails without being sent.
+//
+// The methods taking a RecordID call it before sending the request; the error is a *ValidationError keyed by "uuid" and "order_id".
+func (r RecordID) Validate() error {
+	errs := fieldErrors{}
+	if isBlank(r.UUID) && isBlank(r.OrderID) {
+		errs.add("uuid", "is required without order_id")
+		errs.add("order_id", "is required without uuid")
+	}
+	return errs.err()
+}
+
+func isBlank(s *string) bool {
+	return s == nil || strings.TrimSpace(*s) == ""
+}
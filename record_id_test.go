@@ -0,0 +1,107 @@
+package cryptomus_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestRecordIDValidate(t *testing.T) {
+	blank := " "
+	uuid, orderID := "26109ba0", "1"
+
+	tests := []struct {
+		name    string
+		id      cryptomus.RecordID
+		wantErr bool
+	}{
+		{"neither", cryptomus.RecordID{}, true},
+		{"blank uuid", cryptomus.RecordID{UUID: &blank}, true},
+		{"uuid", cryptomus.ByUUID(uuid), false},
+		{"order id", cryptomus.ByOrderID("1"), false},
+		{"uuid and blank order id", cryptomus.RecordID{UUID: &uuid, OrderID: &blank}, false},
+		{"both", cryptomus.RecordID{UUID: &uuid, OrderID: &orderID}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.id.Validate()
+			if !test.wantErr {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationError *cryptomus.ValidationError
+			if !errors.As(err, &validationError) {
+				t.Fatalf("got error %v, want a *ValidationError", err)
+			}
+			if len(validationError.Errors["uuid"]) == 0 || len(validationError.Errors["order_id"]) == 0 {
+				t.Errorf("got errors %v, want errors for uuid and order_id", validationError.Errors)
+			}
+		})
+	}
+}
+
+func TestRecordIDValidatedBeforeSending(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.Write([]byte(`{"state": 0, "result": []}`))
+	}))
+	defer server.Close()
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+
+	calls := map[string]func(cryptomus.RecordID) error{
+		"GetPaymentInformation": func(id cryptomus.RecordID) error {
+			_, err := merchant.GetPaymentInformation(id)
+			return err
+		},
+		"GetPayoutInformation": func(id cryptomus.RecordID) error {
+			_, err := merchant.GetPayoutInformation(id)
+			return err
+		},
+		"GetRecurringPaymentInformation": func(id cryptomus.RecordID) error {
+			_, err := merchant.GetRecurringPaymentInformation(id)
+			return err
+		},
+		"CancelRecurringPayment": func(id cryptomus.RecordID) error {
+			_, err := merchant.CancelRecurringPayment(id)
+			return err
+		},
+		"ResendWebhook": merchant.ResendWebhook,
+		"Refund": func(id cryptomus.RecordID) error {
+			return merchant.Refund(cryptomus.RefundRequest{RecordID: id, Address: "TDD97yguPESTpcrJMqU6h2ozZbibv4Vaqm"})
+		},
+		"BlockStaticWallet": func(id cryptomus.RecordID) error {
+			_, err := merchant.BlockStaticWallet(cryptomus.BlockStaticWalletRequest{RecordID: id})
+			return err
+		},
+		"RefundBlockedAddress": func(id cryptomus.RecordID) error {
+			_, err := merchant.RefundBlockedAddress(cryptomus.RefundBlockedAddressRequest{RecordID: id, Address: "TDD97yguPESTpcrJMqU6h2ozZbibv4Vaqm"})
+			return err
+		},
+	}
+	for name, call := range calls {
+		var validationError *cryptomus.ValidationError
+		if err := call(cryptomus.RecordID{}); !errors.As(err, &validationError) {
+			t.Errorf("%s: got error %v, want a *ValidationError", name, err)
+		}
+	}
+	if len(bodies) != 0 {
+		t.Errorf("got %d requests without uuid or order_id, want none", len(bodies))
+	}
+
+	uuid, orderID := "26109ba0", "1"
+	if err := merchant.ResendWebhook(cryptomus.RecordID{UUID: &uuid, OrderID: &orderID}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"uuid":"26109ba0","order_id":"1"}`; len(bodies) != 1 || bodies[0] != want {
+		t.Errorf("got bodies %q, want [%q]", bodies, want)
+	}
+}
@@ -1,5 +1,11 @@
 package cryptomus
 
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
 // See "Creating recurring payment" https://doc.cryptomus.com/business/recurring/creating
 //
 // See "Payment information" https://doc.cryptomus.com/business/recurring/info
@@ -63,14 +69,14 @@ type RecurringPayment struct {
 	Currency string `json:"currency"`
 	// The currency in which the customer must make the payment.
 	PayerCurrency string `json:"payer_currency"`
-	// The equivalent USD amount in payer_currency that the customer must pay
+	// The equivalent USD amount in payer_currency that the customer must pay, "0.00" until it is determined
 	PayerAmountUSD string `json:"payer_amount_usd"`
 	// The amount in payer_currency that the customer must pay
 	PayerAmount string `json:"payer_amount"`
 	// Url to which webhooks with payment status will be sent
 	URLCallback *string `json:"url_callback"`
 	// Recurring payment period
-	Period string `json:"period"`
+	Period RecurringPeriod `json:"period"`
 	// Recurring status
 	//
 	// Available options:
@@ -86,3 +92,40 @@ type RecurringPayment struct {
 	// Additional recurring payment details
 	AdditionalData *string `json:"additional_data"`
 }
+
+// LastPayOffTime returns last_pay_off as a time, or nil if no payment was made yet.
+//
+// Timestamps without an offset are taken as UTC+3, the timezone Cryptomus reports them in.
+func (r RecurringPayment) LastPayOffTime() (*time.Time, error) {
+	if r.LastPayOff == nil || *r.LastPayOff == "" {
+		return nil, nil
+	}
+	t, err := parseOrderTime(r.LastPayOff)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing last_pay_off: %w", err)
+	}
+	return &t, nil
+}
+
+// IsActive reports whether the recurring payment was accepted by the payer and is charged every period, i.e. its status is active.
+//
+// It is false while the payer has not accepted it yet (wait_accept) and after it was cancelled by the merchant or the payer.
+func (r RecurringPayment) IsActive() bool {
+	return r.Status == "active"
+}
+
+// PayerAmountUSDDecimal returns payer_amount_usd as an exact decimal, or nil and no error if it is empty.
+func (r RecurringPayment) PayerAmountUSDDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(r.PayerAmountUSD)
+}
+
+// ChargeAmountUSD returns the USD equivalent of each charge, e.g. "15.00", to aggregate charges across payer currencies.
+//
+// ok is false if the amount is not determined yet, which Cryptomus reports as "0.00", or is missing or malformed.
+func (r RecurringPayment) ChargeAmountUSD() (amount string, ok bool) {
+	value, err := r.PayerAmountUSDDecimal()
+	if err != nil || value == nil || value.Sign() == 0 {
+		return "", false
+	}
+	return r.PayerAmountUSD, true
+}
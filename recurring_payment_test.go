@@ -0,0 +1,91 @@
+package cryptomus_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestRecurringPaymentChargeAmountUSD(t *testing.T) {
+	for payerAmountUSD, want := range map[string]bool{
+		"15.00": true,
+		"0.00":  false,
+		"":      false,
+		"n/a":   false,
+	} {
+		amount, ok := cryptomus.RecurringPayment{PayerAmountUSD: payerAmountUSD}.ChargeAmountUSD()
+		if ok != want {
+			t.Errorf("%q: got ok %v, want %v", payerAmountUSD, ok, want)
+		}
+		if ok && amount != payerAmountUSD {
+			t.Errorf("%q: got amount %q", payerAmountUSD, amount)
+		}
+	}
+
+	value, err := cryptomus.RecurringPayment{PayerAmountUSD: "5.25"}.PayerAmountUSDDecimal()
+	if err != nil || value == nil || value.FloatString(2) != "5.25" {
+		t.Errorf("got %v, %v", value, err)
+	}
+	if value, err := (cryptomus.RecurringPayment{}).PayerAmountUSDDecimal(); value != nil || err != nil {
+		t.Errorf("got %v, %v for an empty payer_amount_usd, want nil, nil", value, err)
+	}
+	if _, err := (cryptomus.RecurringPayment{PayerAmountUSD: "n/a"}).PayerAmountUSDDecimal(); err == nil {
+		t.Error("expected error for malformed payer_amount_usd")
+	}
+}
+
+func TestRecurringPaymentLastPayOff(t *testing.T) {
+	var payment cryptomus.RecurringPayment
+	documented := `{
+		"uuid": "afd050e8-35ea-4129-bbdd-73f510dce556",
+		"name": "Recurring payment",
+		"order_id": null,
+		"amount": "15",
+		"currency": "USDT",
+		"payer_currency": "USDT",
+		"payer_amount_usd": "15.00",
+		"payer_amount": "15.00000000",
+		"url_callback": null,
+		"period": "monthly",
+		"status": "wait_accept",
+		"url": "https://pay.cryptomus.com/recurring/afd050e8-35ea-4129-bbdd-73f510dce556",
+		"last_pay_off": null
+	}`
+	if err := json.Unmarshal([]byte(documented), &payment); err != nil {
+		t.Fatalf("error decoding recurring payment: %v", err)
+	}
+	if lastPayOff, err := payment.LastPayOffTime(); lastPayOff != nil || err != nil {
+		t.Errorf("got %v, %v for a null last_pay_off, want nil", lastPayOff, err)
+	}
+	if payment.IsActive() {
+		t.Error("recurring payment waiting for acceptance is active")
+	}
+
+	tests := []struct {
+		lastPayOff string
+		want       time.Time
+	}{
+		{"2024-07-11 18:06:04", time.Date(2024, 7, 11, 15, 6, 4, 0, time.UTC)},
+		{"2024-07-11T18:06:04+03:00", time.Date(2024, 7, 11, 15, 6, 4, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		payment := cryptomus.RecurringPayment{Status: "active", LastPayOff: &test.lastPayOff}
+		lastPayOff, err := payment.LastPayOffTime()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.lastPayOff, err)
+		}
+		if lastPayOff == nil || !lastPayOff.Equal(test.want) {
+			t.Errorf("%s: got %v, want %v", test.lastPayOff, lastPayOff, test.want)
+		}
+		if !payment.IsActive() {
+			t.Errorf("%s: active recurring payment is not active", test.lastPayOff)
+		}
+	}
+
+	invalid := "yesterday"
+	if _, err := (cryptomus.RecurringPayment{LastPayOff: &invalid}).LastPayOffTime(); err == nil {
+		t.Error("expected error for an invalid last_pay_off")
+	}
+}
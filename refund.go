@@ -1,10 +1,8 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strings"
 )
 
 // RefundPaymentRequest represents the parameters needed to request a refund.
@@ -101,41 +99,35 @@ type RefundRequest struct {
 //	    "message": "Server error"
 //	}
 func (m *Merchant) Refund(request RefundRequest) error {
-	httpResponse, err := m.sendPaymentRequest("POST", urlRefund, request)
+	return m.RefundContext(context.Background(), request)
+}
+
+// RefundContext is like Refund but sends the request with the provided context.
+func (m *Merchant) RefundContext(ctx context.Context, request RefundRequest) error {
+	if err := request.Validate(); err != nil {
+		return fmt.Errorf("error validating request: %w", err)
+	}
+
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlRefund, request)
 	if err != nil {
 		return err
 	}
 	defer httpResponse.Body.Close()
 
 	var response = struct {
-		State   int    `json:"state"`
-		Message string `json:"message"`
-		Errors  struct {
-			Address []string `json:"address"`
-			UUID    []string `json:"uuid"`
-			OrderID []string `json:"order_id"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		State   int            `json:"state"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.UUID...)
-	errs = append(errs, response.Errors.OrderID...)
-	errs = append(errs, response.Errors.Address...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return err
 	}
 
 	return nil
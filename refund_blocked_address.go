@@ -1,10 +1,9 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strings"
+	"math/big"
 )
 
 // RefundBlockedAddressRequest represents the parameters needed to refund payments on a blocked wallet address.
@@ -48,6 +47,38 @@ type RefundBlockedAddressResponse struct {
 	Amount string `json:"amount"`
 }
 
+// CommissionDecimal returns commission as an exact decimal, or nil if it is empty.
+func (r RefundBlockedAddressResponse) CommissionDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(r.Commission)
+}
+
+// AmountDecimal returns amount as an exact decimal, or nil if it is empty.
+func (r RefundBlockedAddressResponse) AmountDecimal() (*big.Rat, error) {
+	return parseOptionalDecimal(r.Amount)
+}
+
+// Net returns the amount the refund address receives, the commission already deducted.
+func (r RefundBlockedAddressResponse) Net() (*big.Rat, error) {
+	amount, err := parseDecimal(r.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing amount: %w", err)
+	}
+	return amount, nil
+}
+
+// Total returns the blocked funds the refund took, amount plus commission, e.g. 10 for an amount of 9.50 and a commission of 0.50.
+func (r RefundBlockedAddressResponse) Total() (*big.Rat, error) {
+	amount, err := r.Net()
+	if err != nil {
+		return nil, err
+	}
+	commission, err := parseDecimal(r.Commission)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing commission: %w", err)
+	}
+	return amount.Add(amount, commission), nil
+}
+
 // RefundBlockedAddress refunds blocked funds to a specified address, identified by either a UUID or Order ID.
 //
 // See "Refund payments on blocked address" https://doc.cryptomus.com/business/payments/refundblocked
@@ -87,7 +118,7 @@ type RefundBlockedAddressResponse struct {
 //	    "message": "Not found"
 //	}
 //
-// Al long as withdrawal is allowed only once from blocked static wallet, If the withdrawal was already made you will receive this error message:
+// Al long as withdrawal is allowed only once from blocked static wallet, If the withdrawal was already made you will receive this error message. The returned error matches ErrWithdrawOnlyOnce:
 //
 //	{
 //	    "state": 1,
@@ -101,7 +132,7 @@ type RefundBlockedAddressResponse struct {
 //	    "message": "You can withdraw only once"
 //	}
 //
-// If refund is already in process:
+// If refund is already in process, you will receive this error message. The returned error matches ErrRefundInProgress; poll rather than retry immediately:
 //
 //	{
 //	    "state": 1,
@@ -115,7 +146,7 @@ type RefundBlockedAddressResponse struct {
 //	    "message": "You are forbidden"
 //	}
 //
-// If no payments were made to the static wallet:
+// If no payments were made to the static wallet. The returned error matches ErrNothingToWithdraw:
 //
 //	{
 //	    "state": 1,
@@ -153,7 +184,16 @@ type RefundBlockedAddressResponse struct {
 //		"error": null
 //	}
 func (m *Merchant) RefundBlockedAddress(request RefundBlockedAddressRequest) (*RefundBlockedAddressResponse, error) {
-	httpResponse, err := m.sendPaymentRequest("POST", urlRefundBlockedAddress, request)
+	return m.RefundBlockedAddressContext(context.Background(), request)
+}
+
+// RefundBlockedAddressContext is like RefundBlockedAddress but sends the request with the provided context.
+func (m *Merchant) RefundBlockedAddressContext(ctx context.Context, request RefundBlockedAddressRequest) (*RefundBlockedAddressResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating request: %w", err)
+	}
+
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlRefundBlockedAddress, request)
 	if err != nil {
 		return nil, err
 	}
@@ -163,32 +203,17 @@ func (m *Merchant) RefundBlockedAddress(request RefundBlockedAddressRequest) (*R
 		State   int                          `json:"state"`
 		Result  RefundBlockedAddressResponse `json:"result"`
 		Message string                       `json:"message"`
-		Errors  struct {
-			UUID    []string `json:"uuid"`
-			OrderID []string `json:"order_id"`
-			Address []string `json:"address"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		Errors  responseErrors               `json:"errors"`
+		Code    int                          `json:"code"`
+		Error   string                       `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.UUID...)
-	errs = append(errs, response.Errors.OrderID...)
-	errs = append(errs, response.Errors.Address...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
@@ -0,0 +1,38 @@
+package cryptomus_test
+
+import (
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestRefundBlockedAddressAmounts(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"state": 0, "result": {"commission": "0.50", "amount": "9.50"}}`))
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	refund, err := merchant.RefundBlockedAddress(cryptomus.RefundBlockedAddressRequest{RecordID: cryptomus.ByOrderID("1"), Address: "TDD97yguPESTpcrJMqU6h2ozZbibv4Vaqm"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	commission, err := refund.CommissionDecimal()
+	if err != nil || commission.Cmp(big.NewRat(1, 2)) != 0 {
+		t.Errorf("got commission %v, error %v, want 0.50", commission, err)
+	}
+	net, err := refund.Net()
+	if err != nil || net.Cmp(big.NewRat(19, 2)) != 0 {
+		t.Errorf("got net %v, error %v, want 9.50", net, err)
+	}
+	total, err := refund.Total()
+	if err != nil || total.Cmp(big.NewRat(10, 1)) != 0 {
+		t.Errorf("got total %v, error %v, want 10", total, err)
+	}
+
+	if _, err := (cryptomus.RefundBlockedAddressResponse{Amount: "9.50"}).Total(); err == nil {
+		t.Error("expected an error for an empty commission")
+	}
+}
@@ -1,10 +1,8 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strings"
 )
 
 // ResendWebhook resends the webhook for a finalized invoice identified by either UUID or OrderID.
@@ -61,39 +59,35 @@ import (
 //		"message": "Too much resend"
 //	}
 func (m *Merchant) ResendWebhook(request RecordID) error {
-	httpResponse, err := m.sendPaymentRequest("POST", urlResendWebhook, request)
+	return m.ResendWebhookContext(context.Background(), request)
+}
+
+// ResendWebhookContext is like ResendWebhook but sends the request with the provided context.
+func (m *Merchant) ResendWebhookContext(ctx context.Context, request RecordID) error {
+	if err := request.Validate(); err != nil {
+		return fmt.Errorf("error validating request: %w", err)
+	}
+
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlResendWebhook, request)
 	if err != nil {
 		return err
 	}
 	defer httpResponse.Body.Close()
 
 	var response = struct {
-		State   int    `json:"state"`
-		Message string `json:"message"`
-		Errors  struct {
-			UUID    []string `json:"uuid"`
-			OrderID []string `json:"order_id"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		State   int            `json:"state"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.UUID...)
-	errs = append(errs, response.Errors.OrderID...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return err
 	}
 
 	return nil
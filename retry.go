@@ -0,0 +1,147 @@
+package cryptomus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// retryablePaths lists the endpoints that only read data, so sending a request twice cannot create a second invoice, payout, transfer or order.
+var retryablePaths = map[string]bool{
+	urlGetPaymentInformation:          true,
+	urlListPaymentServices:            true,
+	urlListPaymentHistory:             true,
+	urlGetPayoutInformation:           true,
+	urlListPayoutHistory:              true,
+	urlListPayoutServices:             true,
+	urlGetRecurringPaymentInformation: true,
+	urlListRecurringPayments:          true,
+	urlListDiscounts:                  true,
+	urlGetBalanceForMerchant:          true,
+	urlGetBalanceForUser:              true,
+	urlCalculateConvert:               true,
+	urlListDirections:                 true,
+	urlListOrderHistory:               true,
+}
+
+// isRetryable reports whether a request can be repeated safely: GET requests and POST requests to list and info endpoints.
+func isRetryable(method, path string) bool {
+	if method == http.MethodGet {
+		return true
+	}
+	path, _, _ = strings.Cut(path, "?")
+	return retryablePaths[path]
+}
+
+// WithRetry retries requests to list and info endpoints up to maxAttempts times in total when the request fails with a network error, a 5xx status or a 429 Too Many Requests status.
+//
+// The delay before the nth retry is baseDelay*2^(n-1) with random jitter, or the Retry-After of a 429 response if it has one. No retry is attempted if it would start after the request context deadline.
+//
+// Requests that create or change something, such as CreateInvoice, CreatePayout or Transfer, are never retried, since Cryptomus may have processed the first attempt.
+//
+// Requests are sent once by default.
+func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return func(o *options) {
+		o.maxAttempts = max(maxAttempts, 1)
+		o.retryDelay = baseDelay
+	}
+}
+
+// do sends the request to the configured base URL, retrying it as configured by WithRetry.
+func (o *options) do(ctx context.Context, method, path string, body []byte, header http.Header) (*http.Response, error) {
+	attempts := 1
+	if isRetryable(method, path) {
+		attempts = o.maxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		if o.limiter != nil {
+			if err := o.limiter.wait(ctx); err != nil {
+				return nil, fmt.Errorf("error waiting for rate limit: %w", err)
+			}
+		}
+
+		httpRequest, err := http.NewRequestWithContext(ctx, method, o.baseURL+path, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+		httpRequest.Header = header.Clone()
+		httpRequest.Header.Set("User-Agent", o.userAgent)
+		if id, ok := CorrelationIDFromContext(ctx); ok && o.correlationIDHeader != "" {
+			httpRequest.Header.Set(o.correlationIDHeader, id)
+		}
+
+		o.logRequest(ctx, httpRequest, body)
+		httpResponse, err := o.client.Do(httpRequest)
+		if logErr := o.logResponse(ctx, httpRequest, httpResponse, err); logErr != nil {
+			return nil, logErr
+		}
+		transient := err != nil || httpResponse.StatusCode >= http.StatusInternalServerError || httpResponse.StatusCode == http.StatusTooManyRequests
+
+		delay := backoff(o.retryDelay, attempt)
+		if err == nil && httpResponse.StatusCode == http.StatusTooManyRequests {
+			if retryAfter, ok := parseRetryAfter(httpResponse.Header.Get("Retry-After")); ok {
+				delay = retryAfter
+			}
+		}
+		deadline, hasDeadline := ctx.Deadline()
+		if !transient || attempt >= attempts || ctx.Err() != nil || (hasDeadline && time.Now().Add(delay).After(deadline)) {
+			if err != nil {
+				return nil, fmt.Errorf("error sending request: %w", err)
+			}
+			return httpResponse, nil
+		}
+
+		if err == nil {
+			io.Copy(io.Discard, httpResponse.Body)
+			httpResponse.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("error sending request: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// maxBackoffDoublings caps the exponent of backoff so that later attempts keep the longest delay rather than overflowing to none.
+const maxBackoffDoublings = 30
+
+// backoff returns the delay before retrying after the given attempt: half of baseDelay*2^(attempt-1) plus a random part of up to the other half.
+//
+// The exponent is capped at maxBackoffDoublings and the delay at the largest time.Duration.
+func backoff(baseDelay time.Duration, attempt int) time.Duration {
+	if baseDelay <= 0 {
+		return 0
+	}
+	doublings := min(max(attempt-1, 0), maxBackoffDoublings)
+	delay := baseDelay << doublings
+	if delay>>doublings != baseDelay {
+		delay = math.MaxInt64
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// parseRetryAfter parses the value of a Retry-After header, either a number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,130 @@
+package cryptomus_test
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+// newFlakyServer returns a server that fails the first failures requests with a 500 status and then answers with body.
+func newFlakyServer(t *testing.T, failures int, body string) (*httptest.Server, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message": "Server error, #1", "code": 500}`))
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestWithRetry(t *testing.T) {
+	server, requests := newFlakyServer(t, 2, `{"state": 0, "result": [{"network": "tron", "currency": "USDT", "is_available": true}]}`)
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL), cryptomus.WithRetry(3, time.Millisecond))
+	services, err := merchant.ListPaymentServices()
+	if err != nil {
+		t.Fatalf("error listing payment services: %v", err)
+	}
+
+	if len(services) != 1 {
+		t.Errorf("unexpected services %+v", services)
+	}
+	if *requests != 3 {
+		t.Errorf("got %d requests, want 3", *requests)
+	}
+}
+
+func TestWithRetryGivesUp(t *testing.T) {
+	server, requests := newFlakyServer(t, 5, `{"state": 0, "result": []}`)
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL), cryptomus.WithRetry(3, time.Millisecond))
+	if _, err := merchant.ListPaymentServices(); err == nil {
+		t.Fatal("expected error")
+	}
+	if *requests != 3 {
+		t.Errorf("got %d requests, want 3", *requests)
+	}
+}
+
+func TestWithRetrySkipsCreation(t *testing.T) {
+	server, requests := newFlakyServer(t, 2, `{"state": 0, "result": {"uuid": "26109ba0-b05b-4ee0-93d1-fd62c822ce95"}}`)
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL), cryptomus.WithRetry(3, time.Millisecond))
+	if _, err := merchant.CreateInvoice(cryptomus.Invoice{Amount: "15", Currency: "USD", OrderID: "1"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if *requests != 1 {
+		t.Errorf("got %d requests, want 1", *requests)
+	}
+}
+
+func TestTooManyRequests(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"message": "Too Many Attempts."}`))
+			return
+		}
+		w.Write([]byte(`{"state": 0, "result": []}`))
+	}))
+	defer server.Close()
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+	_, err := merchant.ListPaymentServices()
+	var rateLimitError *cryptomus.RateLimitError
+	if !errors.As(err, &rateLimitError) {
+		t.Fatalf("got error %v, want a RateLimitError", err)
+	}
+	if rateLimitError.RetryAfter != 2*time.Second || rateLimitError.HTTPStatus != http.StatusTooManyRequests {
+		t.Errorf("got retry after %s and status %d", rateLimitError.RetryAfter, rateLimitError.HTTPStatus)
+	}
+	var apiError *cryptomus.APIError
+	if !errors.As(err, &apiError) || apiError.Message != "Too Many Attempts." {
+		t.Errorf("got APIError %v", apiError)
+	}
+
+	requests = 0
+	retrying := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL), cryptomus.WithRetry(2, time.Millisecond))
+	start := time.Now()
+	if _, err := retrying.ListPaymentServices(); err != nil {
+		t.Fatalf("error listing payment services: %v", err)
+	}
+	if elapsed := time.Since(start); requests != 2 || elapsed < 2*time.Second {
+		t.Errorf("got %d requests in %s, want 2 requests 2s apart", requests, elapsed)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for _, test := range []struct {
+		baseDelay time.Duration
+		attempt   int
+		min, max  time.Duration
+	}{
+		{time.Second, 1, 500 * time.Millisecond, time.Second},
+		{time.Second, 3, 2 * time.Second, 4 * time.Second},
+		{time.Second, 31, 1 << 29 * time.Second, 1 << 30 * time.Second},
+		{time.Second, 64, 1 << 29 * time.Second, 1 << 30 * time.Second},
+		{time.Second, 1000, 1 << 29 * time.Second, 1 << 30 * time.Second},
+		{time.Hour, 1000, math.MaxInt64 / 2, math.MaxInt64},
+		{0, 5, 0, 0},
+	} {
+		for range 10 {
+			if delay := cryptomus.Backoff(test.baseDelay, test.attempt); delay < test.min || delay > test.max {
+				t.Errorf("backoff(%s, %d): got %s, want between %s and %s", test.baseDelay, test.attempt, delay, test.min, test.max)
+			}
+		}
+	}
+}
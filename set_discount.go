@@ -1,10 +1,8 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strings"
 )
 
 // See "Set discount to payment method" https://doc.cryptomus.com/business/discount/set
@@ -12,14 +10,14 @@ import (
 // # Request example
 //
 //	{
-//	    "network": "bsc"
+//	    "network": "bsc",
 //	    "currency": "BUSD",
-//	    "discount_percent": -20,
+//	    "discount_percent": -20
 //	}
 type DiscountRequest struct {
-	// (Required) Currency code
-	Network string `json:"network"`
 	// (Required) Blockchain network code
+	Network string `json:"network"`
+	// (Required) Currency code
 	Currency string `json:"currency"`
 	// (Required) Discount percent
 	// About discount percent:
@@ -50,44 +48,63 @@ type DiscountRequest struct {
 //		"state": 1
 //	}
 func (m *Merchant) SetDiscount(request DiscountRequest) (*Discount, error) {
-	httpResponse, err := m.sendPaymentRequest("POST", urlSetDiscount, request)
+	return m.SetDiscountContext(context.Background(), request)
+}
+
+// SetDiscountContext is like SetDiscount but sends the request with the provided context.
+func (m *Merchant) SetDiscountContext(ctx context.Context, request DiscountRequest) (*Discount, error) {
+	request.Network = m.network(request.Network)
+
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlSetDiscount, request)
 	if err != nil {
 		return nil, err
 	}
 	defer httpResponse.Body.Close()
 
 	var response = struct {
-		State   int      `json:"state"`
-		Result  Discount `json:"result"`
-		Message string   `json:"message"`
-		Errors  struct {
-			Currency        []string `json:"currency"`
-			Network         []string `json:"network"`
-			DiscountPercent []string `json:"discount_percent"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		State   int            `json:"state"`
+		Result  Discount       `json:"result"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.Currency...)
-	errs = append(errs, response.Errors.Network...)
-	errs = append(errs, response.Errors.DiscountPercent...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
+}
+
+// DiscountResult pairs a request of a SetDiscounts batch with the discount set by it, or the error that prevented it.
+type DiscountResult struct {
+	Request DiscountRequest
+	// Discount is nil if Err is set.
+	Discount *Discount
+	Err      error
+}
+
+// SetDiscounts sets the discount of every request, sending up to the number set with WithBatchConcurrency, 4 by default, at a time, and returns a result for each of them in the order of requests.
+//
+// Each discount is set on its own as by SetDiscountContext, so a failed one is reported in its result and does not stop the others. Setting a discount again has no further effect, so the failed requests can be retried as they are.
+//
+// The error is that of ctx if it is done before every request was sent; the results of the requests that were not sent hold it too.
+func (m *Merchant) SetDiscounts(ctx context.Context, requests []DiscountRequest) ([]DiscountResult, error) {
+	results := make([]DiscountResult, len(requests))
+	errs, err := runBatch(ctx, len(requests), m.batchConcurrency, func(i int) error {
+		var err error
+		results[i].Discount, err = m.SetDiscountContext(ctx, requests[i])
+		return err
+	})
+	for i, request := range requests {
+		results[i].Request = request
+		results[i].Err = errs[i]
+	}
 
+	return results, err
 }
@@ -0,0 +1,127 @@
+package cryptomus
+
+// PaymentStatus is the status of an invoice or a payment to a static wallet.
+//
+// See "Payment statuses" https://doc.cryptomus.com/business/payments/payment-statuses
+type PaymentStatus string
+
+const (
+	// The payment was successful and the client paid exactly as much as required.
+	StatusPaid PaymentStatus = "paid"
+	// The payment was successful and client paid more than required.
+	StatusPaidOver PaymentStatus = "paid_over"
+	// The client paid less than required
+	StatusWrongAmount PaymentStatus = "wrong_amount"
+	// Payment in processing
+	StatusProcess PaymentStatus = "process"
+	// We have seen the transaction in the blockchain and are waiting for the required number of network confirmations.
+	StatusConfirmCheck PaymentStatus = "confirm_check"
+	// The client paid less than required, with the possibility of an additional payment
+	StatusWrongAmountWaiting PaymentStatus = "wrong_amount_waiting"
+	// Waiting for the transaction to appear on the blockchain
+	StatusCheck PaymentStatus = "check"
+	// Payment error
+	StatusFail PaymentStatus = "fail"
+	// Payment cancelled, the client did not pay
+	StatusCancel PaymentStatus = "cancel"
+	// A system error has occurred
+	StatusSystemFail PaymentStatus = "system_fail"
+	// The refund is being processed
+	StatusRefundProcess PaymentStatus = "refund_process"
+	// An error occurred during the refund
+	StatusRefundFail PaymentStatus = "refund_fail"
+	// The refund was successful
+	StatusRefundPaid PaymentStatus = "refund_paid"
+	// Funds are locked due to the AML program
+	StatusLocked PaymentStatus = "locked"
+)
+
+// IsFinal reports whether the status can no longer change, except through a refund.
+func (s PaymentStatus) IsFinal() bool {
+	switch s {
+	case StatusPaid, StatusPaidOver, StatusWrongAmount, StatusFail, StatusCancel, StatusSystemFail, StatusRefundFail, StatusRefundPaid, StatusLocked:
+		return true
+	}
+	return false
+}
+
+// IsValid reports whether s is one of the documented payment statuses.
+func (s PaymentStatus) IsValid() bool {
+	switch s {
+	case StatusPaid, StatusPaidOver, StatusWrongAmount, StatusProcess, StatusConfirmCheck, StatusWrongAmountWaiting, StatusCheck,
+		StatusFail, StatusCancel, StatusSystemFail, StatusRefundProcess, StatusRefundFail, StatusRefundPaid, StatusLocked:
+		return true
+	}
+	return false
+}
+
+// IsSuccess reports whether the client paid at least the required amount.
+func (s PaymentStatus) IsSuccess() bool {
+	return s == StatusPaid || s == StatusPaidOver
+}
+
+// PayoutStatus is the status of a payout.
+//
+// See "Payout statuses" https://doc.cryptomus.com/business/payouts/payout-statuses
+type PayoutStatus string
+
+const (
+	// Payout in process
+	PayoutStatusProcess PayoutStatus = "process"
+	// The payout is being verified
+	PayoutStatusCheck PayoutStatus = "check"
+	// The payout was successful
+	PayoutStatusPaid PayoutStatus = "paid"
+	// Payout failed
+	PayoutStatusFail PayoutStatus = "fail"
+	// Payout cancelled
+	PayoutStatusCancel PayoutStatus = "cancel"
+	// A system error has occurred
+	PayoutStatusSystemFail PayoutStatus = "system_fail"
+)
+
+// IsFinal reports whether the payout was paid or failed. The funds of a failed payout are returned to the balance.
+func (s PayoutStatus) IsFinal() bool {
+	switch s {
+	case PayoutStatusPaid, PayoutStatusFail, PayoutStatusCancel, PayoutStatusSystemFail:
+		return true
+	}
+	return false
+}
+
+// IsValid reports whether s is one of the documented payout statuses.
+func (s PayoutStatus) IsValid() bool {
+	switch s {
+	case PayoutStatusProcess, PayoutStatusCheck, PayoutStatusPaid, PayoutStatusFail, PayoutStatusCancel, PayoutStatusSystemFail:
+		return true
+	}
+	return false
+}
+
+// IsSuccess reports whether the payout was paid.
+func (s PayoutStatus) IsSuccess() bool {
+	return s == PayoutStatusPaid
+}
+
+// RecurringPeriod is how often a recurring payment is charged.
+//
+// See "Creating recurring payment" https://doc.cryptomus.com/business/recurring/creating
+type RecurringPeriod string
+
+const (
+	// Charged every week
+	PeriodWeekly RecurringPeriod = "weekly"
+	// Charged every month
+	PeriodMonthly RecurringPeriod = "monthly"
+	// Charged every three months
+	PeriodThreeMonth RecurringPeriod = "three_month"
+)
+
+// IsValid reports whether p is one of the periods Cryptomus accepts.
+func (p RecurringPeriod) IsValid() bool {
+	switch p {
+	case PeriodWeekly, PeriodMonthly, PeriodThreeMonth:
+		return true
+	}
+	return false
+}
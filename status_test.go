@@ -0,0 +1,59 @@
+package cryptomus_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestPaymentStatusDecoding(t *testing.T) {
+	for status, want := range map[string]cryptomus.PaymentStatus{
+		"paid":                 cryptomus.StatusPaid,
+		"paid_over":            cryptomus.StatusPaidOver,
+		"wrong_amount":         cryptomus.StatusWrongAmount,
+		"wrong_amount_waiting": cryptomus.StatusWrongAmountWaiting,
+		"confirm_check":        cryptomus.StatusConfirmCheck,
+		"cancel":               cryptomus.StatusCancel,
+		"refund_paid":          cryptomus.StatusRefundPaid,
+	} {
+		var payment cryptomus.Payment
+		if err := json.Unmarshal([]byte(`{"payment_status": "`+status+`"}`), &payment); err != nil {
+			t.Fatalf("error decoding payment: %v", err)
+		}
+		if payment.PaymentStatus != want {
+			t.Errorf("got %q, want %q", payment.PaymentStatus, want)
+		}
+	}
+
+	if !cryptomus.StatusPaidOver.IsSuccess() || !cryptomus.StatusPaidOver.IsFinal() {
+		t.Error("paid_over should be final and successful")
+	}
+	if cryptomus.StatusWrongAmount.IsSuccess() || !cryptomus.StatusWrongAmount.IsFinal() {
+		t.Error("wrong_amount should be final and unsuccessful")
+	}
+	if cryptomus.StatusConfirmCheck.IsFinal() {
+		t.Error("confirm_check should not be final")
+	}
+}
+
+func TestPayoutStatusDecoding(t *testing.T) {
+	var payout cryptomus.Payout
+	if err := json.Unmarshal([]byte(`{"status": "system_fail"}`), &payout); err != nil {
+		t.Fatalf("error decoding payout: %v", err)
+	}
+	if payout.Status != cryptomus.PayoutStatusSystemFail || !payout.Status.IsFinal() || payout.Status.IsSuccess() {
+		t.Errorf("unexpected status %q", payout.Status)
+	}
+	if cryptomus.PayoutStatusProcess.IsFinal() {
+		t.Error("process should not be final")
+	}
+
+	var update cryptomus.Update
+	if err := json.Unmarshal([]byte(`{"type": "payout", "status": "paid"}`), &update); err != nil {
+		t.Fatalf("error decoding update: %v", err)
+	}
+	if update.PayoutStatus() != cryptomus.PayoutStatusPaid {
+		t.Errorf("got %q, want paid", update.PayoutStatus())
+	}
+}
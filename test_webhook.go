@@ -1,10 +1,8 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
-	"strings"
 )
 
 // You may to pass one of the uuid or order_id parameters, if you pass both, the account will be identified by uuid
@@ -63,6 +61,8 @@ type TestWebhookRequest struct {
 //
 // To test a webhook with an existing invoice, please provide its uuid or order ID. If these parameters are not provided, the webhook will be sent with a test invoice.
 //
+// Cryptomus has no endpoint to retrieve the test webhooks it sent. To check them programmatically, e.g. in CI, set url_callback to a server you control, and pass each received body to ParseWebhook and VerifySign.
+//
 // See "Testing webhook" https://doc.cryptomus.com/business/payments/testing-webhook
 //
 // # Response example
@@ -103,47 +103,33 @@ type TestWebhookRequest struct {
 //	    "message": "Payment service not found"
 //	}
 func (m *Merchant) TestWebhookPayment(request TestWebhookRequest) error {
-	httpResponse, err := m.sendPaymentRequest("POST", urlTestWebhookPayment, request)
+	return m.TestWebhookPaymentContext(context.Background(), request)
+}
+
+// TestWebhookPaymentContext is like TestWebhookPayment but sends the request with the provided context.
+func (m *Merchant) TestWebhookPaymentContext(ctx context.Context, request TestWebhookRequest) error {
+	request.Network = m.network(request.Network)
+
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlTestWebhookPayment, request)
 	if err != nil {
 		return err
 	}
 	defer httpResponse.Body.Close()
 
 	var response = struct {
-		State   int    `json:"state"`
-		Message string `json:"message"`
-		Errors  struct {
-			UUID        []string `json:"uuid"`
-			OrderID     []string `json:"order_id"`
-			Currency    []string `json:"currency"`
-			URLCallback []string `json:"url_callback"`
-			Network     []string `json:"network"`
-			Status      []string `json:"status"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		State   int            `json:"state"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.UUID...)
-	errs = append(errs, response.Errors.OrderID...)
-	errs = append(errs, response.Errors.Currency...)
-	errs = append(errs, response.Errors.URLCallback...)
-	errs = append(errs, response.Errors.Network...)
-	errs = append(errs, response.Errors.Status...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return err
 	}
 
 	return nil
@@ -184,47 +170,33 @@ func (m *Merchant) TestWebhookPayment(request TestWebhookRequest) error {
 //		}
 //	}
 func (m *Merchant) TestWebhookWallet(request TestWebhookRequest) error {
-	httpResponse, err := m.sendPaymentRequest("POST", urlTestWebhookWallet, request)
+	return m.TestWebhookWalletContext(context.Background(), request)
+}
+
+// TestWebhookWalletContext is like TestWebhookWallet but sends the request with the provided context.
+func (m *Merchant) TestWebhookWalletContext(ctx context.Context, request TestWebhookRequest) error {
+	request.Network = m.network(request.Network)
+
+	httpResponse, err := m.sendPaymentRequest(ctx, "POST", urlTestWebhookWallet, request)
 	if err != nil {
 		return err
 	}
 	defer httpResponse.Body.Close()
 
 	var response = struct {
-		State   int    `json:"state"`
-		Message string `json:"message"`
-		Errors  struct {
-			UUID        []string `json:"uuid"`
-			OrderID     []string `json:"order_id"`
-			Currency    []string `json:"currency"`
-			URLCallback []string `json:"url_callback"`
-			Network     []string `json:"network"`
-			Status      []string `json:"status"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		State   int            `json:"state"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.UUID...)
-	errs = append(errs, response.Errors.OrderID...)
-	errs = append(errs, response.Errors.Currency...)
-	errs = append(errs, response.Errors.URLCallback...)
-	errs = append(errs, response.Errors.Network...)
-	errs = append(errs, response.Errors.Status...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return err
 	}
 
 	return nil
@@ -272,47 +244,33 @@ func (m *Merchant) TestWebhookWallet(request TestWebhookRequest) error {
 //	    "message": "Payout service not found"
 //	}
 func (m *Merchant) TestWebhookPayout(request TestWebhookRequest) error {
-	httpResponse, err := m.sendPayoutRequest("POST", urlTestWebhookPayout, request)
+	return m.TestWebhookPayoutContext(context.Background(), request)
+}
+
+// TestWebhookPayoutContext is like TestWebhookPayout but sends the request with the provided context.
+func (m *Merchant) TestWebhookPayoutContext(ctx context.Context, request TestWebhookRequest) error {
+	request.Network = m.network(request.Network)
+
+	httpResponse, err := m.sendPayoutRequest(ctx, "POST", urlTestWebhookPayout, request)
 	if err != nil {
 		return err
 	}
 	defer httpResponse.Body.Close()
 
 	var response = struct {
-		State   int    `json:"state"`
-		Message string `json:"message"`
-		Errors  struct {
-			UUID        []string `json:"uuid"`
-			OrderID     []string `json:"order_id"`
-			Currency    []string `json:"currency"`
-			URLCallback []string `json:"url_callback"`
-			Network     []string `json:"network"`
-			Status      []string `json:"status"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		State   int            `json:"state"`
+		Message string         `json:"message"`
+		Errors  responseErrors `json:"errors"`
+		Code    int            `json:"code"`
+		Error   string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.UUID...)
-	errs = append(errs, response.Errors.OrderID...)
-	errs = append(errs, response.Errors.Currency...)
-	errs = append(errs, response.Errors.URLCallback...)
-	errs = append(errs, response.Errors.Network...)
-	errs = append(errs, response.Errors.Status...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return err
 	}
 
 	return nil
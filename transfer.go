@@ -1,9 +1,8 @@
 package cryptomus
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"net/http"
 	"strings"
 )
 
@@ -20,6 +19,20 @@ type TransferRequest struct {
 	Currency string `json:"currency"`
 }
 
+// Validate checks that the amount is a decimal number and the currency a cryptocurrency code, so a transfer fails before it is sent.
+//
+// A currency is considered fiat if it is one of the major fiat currency codes, e.g. USD or EUR; other codes are taken as cryptocurrencies.
+// The minimum and maximum amounts are only known to Cryptomus: a transfer outside them fails with an *AmountOutOfRangeError.
+// It returns a *ValidationError listing every invalid field.
+func (r TransferRequest) Validate() error {
+	errs := fieldErrors{}
+	errs.amount("amount", r.Amount)
+	if errs.required("currency", r.Currency) && fiatCurrencies[strings.ToUpper(strings.TrimSpace(r.Currency))] {
+		errs.add("currency", "must be a cryptocurrency, got fiat currency %s", r.Currency)
+	}
+	return errs.err()
+}
+
 // See "Transfer to personal wallet" https://doc.cryptomus.com/business/payouts/transfer-to-personal
 //
 // # Response example
@@ -98,14 +111,14 @@ type TransferResponse struct {
 //	    "message": "Not enough funds"
 //	}
 //
-// If the transfer amount is less than the minimum supported amount for cryptocurrency, you will receive this error message
+// If the transfer amount is less than the minimum supported amount for cryptocurrency, you will receive this error message, returned as an *AmountOutOfRangeError
 //
 //	{
 //	    "state": 1,
 //	    "message": "Minimum amount 0.5 USDT"
 //	}
 //
-// If the transfer amount is greater than the maximum supported amount for cryptocurrency, you will receive this error message:
+// If the transfer amount is greater than the maximum supported amount for cryptocurrency, you will receive this error message, returned as an *AmountOutOfRangeError:
 //
 //	{
 //	    "state": 1,
@@ -124,7 +137,17 @@ type TransferResponse struct {
 //		"error": null
 //	}
 func (m *Merchant) TransferToPersonalWallet(request TransferRequest) (*TransferResponse, error) {
-	httpResponse, err := m.sendPayoutRequest("POST", urlTransferToPersonalWallet, request)
+	return m.TransferToPersonalWalletContext(context.Background(), request)
+}
+
+// TransferToPersonalWalletContext is like TransferToPersonalWallet but sends the request with the provided context.
+func (m *Merchant) TransferToPersonalWalletContext(ctx context.Context, request TransferRequest) (*TransferResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating transfer: %w", err)
+	}
+	request.Amount, _ = NormalizeAmount(request.Amount)
+
+	httpResponse, err := m.sendPayoutRequest(ctx, "POST", urlTransferToPersonalWallet, request)
 	if err != nil {
 		return nil, err
 	}
@@ -135,30 +158,17 @@ func (m *Merchant) TransferToPersonalWallet(request TransferRequest) (*TransferR
 		Result  TransferResponse `json:"result"`
 		Message string           `json:"message"`
 		// If some parameter is required and not passed
-		Errors *struct {
-			Amount   []string `json:"amount"`
-			Currency []string `json:"currency"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		Errors responseErrors `json:"errors"`
+		Code   int            `json:"code"`
+		Error  string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.Amount...)
-	errs = append(errs, response.Errors.Currency...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
@@ -223,14 +233,14 @@ func (m *Merchant) TransferToPersonalWallet(request TransferRequest) (*TransferR
 //	    "message": "Not enough funds"
 //	}
 //
-// If the transfer amount is less than the minimum supported amount for cryptocurrency, you will receive this error message
+// If the transfer amount is less than the minimum supported amount for cryptocurrency, you will receive this error message, returned as an *AmountOutOfRangeError
 //
 //	{
 //	    "state": 1,
 //	    "message": "Minimum amount 0.5 USDT"
 //	}
 //
-// If the transfer amount is greater than the maximum supported amount for cryptocurrency, you will receive this error message:
+// If the transfer amount is greater than the maximum supported amount for cryptocurrency, you will receive this error message, returned as an *AmountOutOfRangeError:
 //
 //	{
 //	    "state": 1,
@@ -249,7 +259,17 @@ func (m *Merchant) TransferToPersonalWallet(request TransferRequest) (*TransferR
 //		"error": null
 //	}
 func (m *Merchant) TransferToBusinessWallet(request TransferRequest) (*TransferResponse, error) {
-	httpResponse, err := m.sendPayoutRequest("POST", urlTransferToBusinessWallet, request)
+	return m.TransferToBusinessWalletContext(context.Background(), request)
+}
+
+// TransferToBusinessWalletContext is like TransferToBusinessWallet but sends the request with the provided context.
+func (m *Merchant) TransferToBusinessWalletContext(ctx context.Context, request TransferRequest) (*TransferResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating transfer: %w", err)
+	}
+	request.Amount, _ = NormalizeAmount(request.Amount)
+
+	httpResponse, err := m.sendPayoutRequest(ctx, "POST", urlTransferToBusinessWallet, request)
 	if err != nil {
 		return nil, err
 	}
@@ -260,30 +280,17 @@ func (m *Merchant) TransferToBusinessWallet(request TransferRequest) (*TransferR
 		Result  TransferResponse `json:"result"`
 		Message string           `json:"message"`
 		// If some parameter is required and not passed
-		Errors *struct {
-			Amount   []string `json:"amount"`
-			Currency []string `json:"currency"`
-		} `json:"errors"`
-		Code  int    `json:"code"`
-		Error string `json:"error"`
+		Errors responseErrors `json:"errors"`
+		Code   int            `json:"code"`
+		Error  string         `json:"error"`
 	}{}
 
-	if err := json.NewDecoder(httpResponse.Body).Decode(&response); err != nil {
+	if err := m.decode(httpResponse.Body, &response); err != nil {
 		return nil, fmt.Errorf("error decoding response: %w", err)
 	}
 
-	var errs []string
-	if response.Message != "" {
-		errs = append(errs, response.Message)
-	}
-	if response.Error != "" {
-		errs = append(errs, response.Error)
-	}
-	errs = append(errs, response.Errors.Amount...)
-	errs = append(errs, response.Errors.Currency...)
-
-	if httpResponse.StatusCode != http.StatusOK || response.State != 0 || len(errs) > 0 {
-		return nil, fmt.Errorf("error with status %s: %v", httpResponse.Status, strings.Join(errs, "; "))
+	if err := checkResponse(httpResponse, response.State, response.Code, response.Message, response.Error, response.Errors); err != nil {
+		return nil, err
 	}
 
 	return &response.Result, nil
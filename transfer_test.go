@@ -0,0 +1,92 @@
+package cryptomus_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestTransferRequestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    cryptomus.TransferRequest
+		wantFields []string
+	}{
+		{"valid", cryptomus.TransferRequest{Amount: "15", Currency: "USDT"}, nil},
+		{"missing fields", cryptomus.TransferRequest{}, []string{"amount", "currency"}},
+		{"invalid amount", cryptomus.TransferRequest{Amount: "1,5", Currency: "USDT"}, []string{"amount"}},
+		{"fiat currency", cryptomus.TransferRequest{Amount: "15", Currency: "usd"}, []string{"currency"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.request.Validate()
+			if test.wantFields == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationError *cryptomus.ValidationError
+			if !errors.As(err, &validationError) {
+				t.Fatalf("got error %v, want a *ValidationError", err)
+			}
+			if len(validationError.Errors) != len(test.wantFields) {
+				t.Errorf("got errors %v, want errors for %v", validationError.Errors, test.wantFields)
+			}
+			for _, field := range test.wantFields {
+				if len(validationError.Errors[field]) == 0 {
+					t.Errorf("got errors %v, want an error for %s", validationError.Errors, field)
+				}
+			}
+		})
+	}
+}
+
+func TestTransferAmountOutOfRange(t *testing.T) {
+	tests := []struct {
+		message string
+		want    cryptomus.AmountOutOfRangeError
+	}{
+		{"Minimum amount 0.5 USDT", cryptomus.AmountOutOfRangeError{Min: "0.5", Currency: "USDT"}},
+		{"Maximum amount 10000000 USDT", cryptomus.AmountOutOfRangeError{Max: "10000000", Currency: "USDT"}},
+	}
+	for _, test := range tests {
+		t.Run(test.message, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				w.Write([]byte(`{"state": 1, "message": "` + test.message + `"}`))
+			}))
+			defer server.Close()
+
+			merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+			_, err := merchant.TransferToPersonalWallet(cryptomus.TransferRequest{Amount: "0.1", Currency: "USDT"})
+
+			var rangeError *cryptomus.AmountOutOfRangeError
+			if !errors.As(err, &rangeError) {
+				t.Fatalf("got error %v, want an *AmountOutOfRangeError", err)
+			}
+			if rangeError.Min != test.want.Min || rangeError.Max != test.want.Max || rangeError.Currency != test.want.Currency {
+				t.Errorf("got min %q, max %q, currency %q, want %+v", rangeError.Min, rangeError.Max, rangeError.Currency, test.want)
+			}
+			var apiError *cryptomus.APIError
+			if !errors.As(err, &apiError) || apiError.Message != test.message {
+				t.Errorf("got error %v, want it to wrap the APIError", err)
+			}
+		})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"state": 1, "message": "Not enough funds"}`))
+	}))
+	defer server.Close()
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+	_, err := merchant.TransferToBusinessWallet(cryptomus.TransferRequest{Amount: "15", Currency: "USDT"})
+	var rangeError *cryptomus.AmountOutOfRangeError
+	if errors.As(err, &rangeError) || !cryptomus.IsNotEnoughFunds(err) {
+		t.Errorf("got error %v, want a plain APIError", err)
+	}
+}
@@ -1,44 +1,45 @@
 // cryptomus
 package cryptomus
 
+// urlEndpoint is the default base URL; the other endpoints are paths relative to it.
 const (
 	urlEndpoint                       = "https://api.cryptomus.com/"
-	urlCreateInvoice                  = "https://api.cryptomus.com/v1/payment"
-	urlCreateStaticWallet             = "https://api.cryptomus.com/v1/wallet"
-	urlGenerateQRCodeForStaticWallet  = "https://api.cryptomus.com/v1/wallet/qr"
-	urlGenerateQRCodeForInvoice       = "https://api.cryptomus.com/v1/payment/qr"
-	urlBlockStaticWallet              = "https://api.cryptomus.com/v1/wallet/block-address"
-	urlRefundBlockedAddress           = "https://api.cryptomus.com/v1/wallet/blocked-address-refund"
-	urlGetPaymentInformation          = "https://api.cryptomus.com/v1/payment/info"
-	urlRefund                         = "https://api.cryptomus.com/v1/payment/refund"
-	urlResendWebhook                  = "https://api.cryptomus.com/v1/payment/resend"
-	urlTestWebhookPayment             = "https://api.cryptomus.com/v1/test-webhook/payment"
-	urlTestWebhookPayout              = "https://api.cryptomus.com/v1/test-webhook/payout"
-	urlTestWebhookWallet              = "https://api.cryptomus.com/v1/test-webhook/wallet"
-	urlListPaymentServices            = "https://api.cryptomus.com/v1/payment/services"
-	urlListPaymentHistory             = "https://api.cryptomus.com/v1/payment/list"
-	urlCreatePayout                   = "https://api.cryptomus.com/v1/payout"
-	urlGetPayoutInformation           = "https://api.cryptomus.com/v1/payout/info"
-	urlListPayoutHistory              = "https://api.cryptomus.com/v1/payout/list"
-	urlListPayoutServices             = "https://api.cryptomus.com/v1/payout/services"
-	urlTransferToPersonalWallet       = "https://api.cryptomus.com/v1/transfer/to-personal"
-	urlTransferToBusinessWallet       = "https://api.cryptomus.com/v1/transfer/to-business"
-	urlCreateRecurringPayment         = "https://api.cryptomus.com/v1/recurrence/create"
-	urlGetRecurringPaymentInformation = "https://api.cryptomus.com/v1/recurrence/info"
-	urlListRecurringPayments          = "https://api.cryptomus.com/v1/recurrence/list"
-	urlCancelRecurringPayment         = "https://api.cryptomus.com/v1/recurrence/cancel"
-	urlGetExchangeRate                = "https://api.cryptomus.com/v1/exchange-rate/%s/list"
-	urlListDiscounts                  = "https://api.cryptomus.com/v1/payment/discount/list"
-	urlSetDiscount                    = "https://api.cryptomus.com/v1/payment/discount/set"
-	urlGetBalanceForMerchant          = "https://api.cryptomus.com/v1/balance"
-	urlGetAssets                      = "https://api.cryptomus.com/v1/exchange/market/assets"
-	urlGetOrderBook                   = "https://api.cryptomus.com/v1/exchange/market/order-book/%s"
-	urlGetTrades                      = "https://api.cryptomus.com/v1/exchange/market/trades/%s"
-	urlGetBalanceForUser              = "https://api.cryptomus.com/v2/user-api/balance"
-	urlCalculateConvert               = "https://api.cryptomus.com/v2/user-api/convert/calculate"
-	urlCreateMarketOrder              = "https://api.cryptomus.com/v2/user-api/convert/"
-	urlCreateLimitOrder               = "https://api.cryptomus.com/v2/user-api/convert/limit"
-	urlCancelLimitOrder               = "https://api.cryptomus.com/v2/user-api/convert/%s"
-	urlListDirections                 = "https://api.cryptomus.com/v2/user-api/convert/direction-list"
-	urlListOrderHistory               = "https://api.cryptomus.com/v2/user-api/convert/order-list/"
+	urlCreateInvoice                  = "v1/payment"
+	urlCreateStaticWallet             = "v1/wallet"
+	urlGenerateQRCodeForStaticWallet  = "v1/wallet/qr"
+	urlGenerateQRCodeForInvoice       = "v1/payment/qr"
+	urlBlockStaticWallet              = "v1/wallet/block-address"
+	urlRefundBlockedAddress           = "v1/wallet/blocked-address-refund"
+	urlGetPaymentInformation          = "v1/payment/info"
+	urlRefund                         = "v1/payment/refund"
+	urlResendWebhook                  = "v1/payment/resend"
+	urlTestWebhookPayment             = "v1/test-webhook/payment"
+	urlTestWebhookPayout              = "v1/test-webhook/payout"
+	urlTestWebhookWallet              = "v1/test-webhook/wallet"
+	urlListPaymentServices            = "v1/payment/services"
+	urlListPaymentHistory             = "v1/payment/list"
+	urlCreatePayout                   = "v1/payout"
+	urlGetPayoutInformation           = "v1/payout/info"
+	urlListPayoutHistory              = "v1/payout/list"
+	urlListPayoutServices             = "v1/payout/services"
+	urlTransferToPersonalWallet       = "v1/transfer/to-personal"
+	urlTransferToBusinessWallet       = "v1/transfer/to-business"
+	urlCreateRecurringPayment         = "v1/recurrence/create"
+	urlGetRecurringPaymentInformation = "v1/recurrence/info"
+	urlListRecurringPayments          = "v1/recurrence/list"
+	urlCancelRecurringPayment         = "v1/recurrence/cancel"
+	urlGetExchangeRate                = "v1/exchange-rate/%s/list"
+	urlListDiscounts                  = "v1/payment/discount/list"
+	urlSetDiscount                    = "v1/payment/discount/set"
+	urlGetBalanceForMerchant          = "v1/balance"
+	urlGetAssets                      = "v1/exchange/market/assets"
+	urlGetOrderBook                   = "v1/exchange/market/order-book/%s"
+	urlGetTrades                      = "v1/exchange/market/trades/%s"
+	urlGetBalanceForUser              = "v2/user-api/balance"
+	urlCalculateConvert               = "v2/user-api/convert/calculate"
+	urlCreateMarketOrder              = "v2/user-api/convert/"
+	urlCreateLimitOrder               = "v2/user-api/convert/limit"
+	urlCancelLimitOrder               = "v2/user-api/convert/%s"
+	urlListDirections                 = "v2/user-api/convert/direction-list"
+	urlListOrderHistory               = "v2/user-api/convert/order-list/"
 )
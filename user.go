@@ -1,103 +1,106 @@
 package cryptomus
 
 import (
-	"bytes"
+	"context"
 	"crypto/md5"
-	"encoding/base64"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 )
 
+// User is a personal account with API keys for accepting payment and making payouts.
+//
+// A User is safe for concurrent use by multiple goroutines, so create one per account and share it. The only state its methods modify, the directions cached for EstimateConvert, is guarded by a mutex; do not change its fields once it is in use.
 type User struct {
 	UserID, PaymentAPIKey, PayoutAPIKey string
-	client                              *http.Client
+	options
+
+	// directions caches the last successful ListDirections result for EstimateConvert.
+	directionsMu        sync.Mutex
+	directions          []Direction
+	directionsFetchedAt time.Time
 }
 
 // You need to release a different API key for accepting payment and making payouts
 //
 // See "Getting API keys" https://doc.cryptomus.com/personal/general/getting-api-keys
-func NewUser(userID, paymentAPIKey, payoutAPIKey string) *User {
-	return &User{
+func NewUser(userID, paymentAPIKey, payoutAPIKey string, opts ...Option) *User {
+	u := &User{
 		UserID:        userID,
 		PaymentAPIKey: paymentAPIKey,
 		PayoutAPIKey:  payoutAPIKey,
-		client:        &http.Client{Timeout: 10 * time.Second},
+		options:       newOptions(opts),
 	}
+	u.secrets = []string{paymentAPIKey, payoutAPIKey}
+	return u
 }
 
-// signPaymentPayload generates MD5 hash of the body of the POST request encoded in base64 and combined with your payment API key.
+// SignPayment returns the sign Cryptomus expects for a request with the given body to a payment endpoint, as sent in the sign header.
+//
+// The sign is the hex-encoded MD5 hash of the base64-encoded body followed by the payment API key:
+//
+//	md5(base64(body) + PaymentAPIKey)
+//
+// body must be the exact bytes sent.
 //
 // See "Request format" https://doc.cryptomus.com/personal/general/request-format
-func (u *User) signPaymentPayload(jsonData []byte) (string, error) {
-	base64Data := base64.StdEncoding.EncodeToString(jsonData)
-	hash := md5.Sum([]byte(base64Data + u.PaymentAPIKey))
-	return hex.EncodeToString(hash[:]), nil
+func (u *User) SignPayment(body []byte) string {
+	hash := md5.Sum([]byte(Base64Body(body) + u.PaymentAPIKey))
+	return hex.EncodeToString(hash[:])
 }
 
-// signPayoutPayload generates MD5 hash of the body of the POST request encoded in base64 and combined with your payment API key.
+// SignPayout returns the sign Cryptomus expects for a request with the given body to a payout endpoint, as sent in the sign header.
+//
+// The sign is the hex-encoded MD5 hash of the base64-encoded body followed by the payout API key:
+//
+//	md5(base64(body) + PayoutAPIKey)
+//
+// body must be the exact bytes sent.
 //
 // See "Request format" https://doc.cryptomus.com/personal/general/request-format
-func (u *User) signPayoutPayload(jsonData []byte) (string, error) {
-	base64Data := base64.StdEncoding.EncodeToString(jsonData)
-	hash := md5.Sum([]byte(base64Data + u.PayoutAPIKey))
-	return hex.EncodeToString(hash[:]), nil
+func (u *User) SignPayout(body []byte) string {
+	hash := md5.Sum([]byte(Base64Body(body) + u.PayoutAPIKey))
+	return hex.EncodeToString(hash[:])
 }
 
-func (u *User) sendPaymentRequest(method, url string, request any) (*http.Response, error) {
-	jsonData, err := json.Marshal(request)
+func (u *User) sendPaymentRequest(ctx context.Context, method, path string, request any) (*http.Response, error) {
+	jsonData, err := u.marshal(request)
 	if err != nil {
 		return nil, fmt.Errorf("error marshaling request: %w", err)
 	}
 
-	httpRequest, err := http.NewRequest(method, url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
-
-	signature, err := u.signPaymentPayload(jsonData)
-	if err != nil {
-		return nil, fmt.Errorf("error signing request payload: %w", err)
-	}
+	signature := u.SignPayment(jsonData)
 
-	httpRequest.Header.Set("Content-Type", "application/json")
-	httpRequest.Header.Set("userId", u.UserID)
-	httpRequest.Header.Set("sign", signature)
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("userId", u.UserID)
+	header.Set("sign", signature)
 
-	httpResponse, err := u.client.Do(httpRequest)
-	if err != nil {
-		return nil, fmt.Errorf("error sending request: %w", err)
-	}
-
-	return httpResponse, nil
+	return u.do(ctx, method, path, jsonData, header)
 }
 
-func (u *User) sendPayoutRequest(method, url string, request any) (*http.Response, error) {
-	jsonData, err := json.Marshal(request)
+func (u *User) sendPayoutRequest(ctx context.Context, method, path string, request any) (*http.Response, error) {
+	jsonData, err := u.marshal(request)
 	if err != nil {
 		return nil, fmt.Errorf("error marshalling request payload: %w", err)
 	}
 
-	httpRequest, err := http.NewRequest(method, url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
-
-	signature, err := u.signPayoutPayload(jsonData)
-	if err != nil {
-		return nil, fmt.Errorf("error signing request payload: %w", err)
-	}
+	signature := u.SignPayout(jsonData)
 
-	httpRequest.Header.Set("Content-Type", "application/json")
-	httpRequest.Header.Set("userId", u.UserID)
-	httpRequest.Header.Set("sign", signature)
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("userId", u.UserID)
+	header.Set("sign", signature)
 
-	httpResponse, err := u.client.Do(httpRequest)
-	if err != nil {
-		return nil, fmt.Errorf("error sending request: %w", err)
-	}
+	return u.do(ctx, method, path, jsonData, header)
+}
 
-	return httpResponse, nil
+// Close closes the idle keep-alive connections of the HTTP client of u, e.g. before discarding u in a long-running process.
+//
+// Calling it is optional: u holds no other resources, and it stays usable after Close, opening new connections as needed.
+// With the default client, or one set with WithHTTPClient that shares its transport, it closes the idle connections of that shared transport, such as http.DefaultTransport, too.
+func (u *User) Close() {
+	u.client.CloseIdleConnections()
 }
@@ -0,0 +1,53 @@
+package cryptomus_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestUserContextCancellation(t *testing.T) {
+	newMockServer(t, blockUntilCancelled(t))
+
+	user := cryptomus.NewUser("user", "payment-key", "payout-key")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := user.CalculateConvertContext(ctx, cryptomus.Convert{From: "BTC", To: "USDT"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context deadline error, got %v", err)
+	}
+}
+
+func TestUserSign(t *testing.T) {
+	user := cryptomus.NewUser("user", "payment-key", "payout-key")
+	body := []byte(`{"amount":"15","currency":"USD","order_id":"1"}`)
+
+	if got, want := user.SignPayment(body), "34f805b7f71a91dd3ca118a49aead428"; got != want {
+		t.Errorf("SignPayment = %s, want %s", got, want)
+	}
+	if got, want := user.SignPayout(body), "8d8fa4b7b6d85bd6ec39faddbe0e1b42"; got != want {
+		t.Errorf("SignPayout = %s, want %s", got, want)
+	}
+}
+
+func TestUserClose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"state": 0, "result": []}`))
+	}))
+	defer server.Close()
+
+	user := cryptomus.NewUser("user", "payment-key", "payout-key", cryptomus.WithBaseURL(server.URL))
+	if _, err := user.ListDirections(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	user.Close()
+	if _, err := user.ListDirections(); err != nil {
+		t.Fatalf("after Close: unexpected error: %v", err)
+	}
+}
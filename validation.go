@@ -0,0 +1,99 @@
+package cryptomus
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError is returned when a request fails validation before it is sent, e.g. by Invoice.Validate.
+//
+// Errors has the same shape as the errors Cryptomus sends back in APIError, so both can be reported alike.
+type ValidationError struct {
+	// Errors by request field, e.g. {"lifetime": ["must be between 300 and 43200, got 60"]}
+	Errors map[string][]string
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Errors))
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	details := make([]string, 0, len(fields))
+	for _, field := range fields {
+		details = append(details, fmt.Sprintf("%s: %s", field, strings.Join(e.Errors[field], ", ")))
+	}
+	return "invalid request: " + strings.Join(details, "; ")
+}
+
+// fieldErrors collects the validation errors of a request by field.
+type fieldErrors map[string][]string
+
+func (e fieldErrors) add(field, format string, args ...any) {
+	e[field] = append(e[field], fmt.Sprintf(format, args...))
+}
+
+func (e fieldErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: e}
+}
+
+func (e fieldErrors) required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		e.add(field, "is required")
+		return false
+	}
+	return true
+}
+
+func (e fieldErrors) amount(field, value string) {
+	if !e.required(field, value) {
+		return
+	}
+	if _, err := NormalizeAmount(value); err != nil {
+		e.add(field, "%v", err)
+	}
+}
+
+var alphaDash = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+// alphaDash checks an ID such as order_id, which must be between min and max letters, numbers, underscores and dashes.
+func (e fieldErrors) alphaDash(field, value string, min, max int) {
+	if !e.required(field, value) {
+		return
+	}
+	e.length(field, value, min, max)
+	if !alphaDash.MatchString(value) {
+		e.add(field, "must contain only letters, numbers, underscores and dashes")
+	}
+}
+
+func (e fieldErrors) length(field, value string, min, max int) {
+	if n := utf8.RuneCountInString(value); n < min || n > max {
+		e.add(field, "must be %d to %d characters long, got %d", min, max, n)
+	}
+}
+
+func (e fieldErrors) between(field string, value *int, min, max int) {
+	if value != nil && (*value < min || *value > max) {
+		e.add(field, "must be between %d and %d, got %d", min, max, *value)
+	}
+}
+
+// url checks an optional URL, which must be absolute and between 6 and 255 characters.
+func (e fieldErrors) url(field string, value *string) {
+	if value == nil {
+		return
+	}
+	e.length(field, *value, 6, 255)
+	if u, err := url.ParseRequestURI(*value); err != nil || u.Host == "" {
+		e.add(field, "must be an absolute URL")
+	}
+}
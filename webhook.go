@@ -1,9 +1,13 @@
 package cryptomus
 
 import (
+	"bytes"
 	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 )
 
 // Webhook is a kind of feedback method for payment information.
@@ -58,6 +62,14 @@ import (
 //		"payer_amount": "207.00000000",
 //		"sign": "eff3afba8600af59c98b74155934da2d"
 //	}
+//
+// # Fields by type
+//
+// The fields marked (Common) are sent with every type. The fields marked (Only in Payment) are sent with payment and wallet updates;
+// wallet updates are payments to a static wallet and also carry its wallet_address_uuid. The fields marked (Only in Payout) are only sent with payout updates.
+//
+// Unlike the response of GetPaymentInformation, an update has no payment_status: its status is the status of the invoice or payout,
+// the value GetPaymentInformation returns as both status and payment_status.
 type Update struct {
 	// (Common) Available options:
 	//  - wallet (invoice)
@@ -80,7 +92,7 @@ type Update struct {
 	Commission *string `json:"commission"`
 	// (Common) Whether the invoice/withdrawal is finalized.
 	IsFinal *bool `json:"is_final"`
-	// Payment/payout status
+	// (Common) Payment/payout status. There is no separate payment_status in updates.
 	//
 	// Available options for payment (https://doc.cryptomus.com/business/payments/payment-statuses):
 	//  - confirm_check: We have seen the transaction in the blockchain and are waiting for the required number of network confirmations.
@@ -100,10 +112,12 @@ type Update struct {
 	//  - fail: Payout failed
 	//  - cancel: Payout cancelled
 	//  - system_fail: A system error has occurred
+	//
+	// Use PaymentStatus or PayoutStatus, depending on Type, for the typed value.
 	Status *string `json:"status"`
 	// (Only in Payment) Payer's wallet address
 	From *string `json:"from"`
-	// (Only in Payment) uuid of the static wallet
+	// (Only in Payment) uuid of the static wallet, null unless the type is wallet
 	WalletAddressUUID *string `json:"wallet_address_uuid"`
 	// (Common) The blockchain network for payment/payout
 	Network *string `json:"network"`
@@ -133,6 +147,90 @@ type Update struct {
 	Sign string `json:"sign"`
 }
 
+// PaymentStatus returns the status of a payment or wallet update, or "" if the update has none.
+func (u Update) PaymentStatus() PaymentStatus {
+	if u.Status == nil {
+		return ""
+	}
+	return PaymentStatus(*u.Status)
+}
+
+// PayoutStatus returns the status of a payout update, or "" if the update has none.
+func (u Update) PayoutStatus() PayoutStatus {
+	if u.Status == nil {
+		return ""
+	}
+	return PayoutStatus(*u.Status)
+}
+
+// IsPayment reports whether u is an update of an invoice.
+func (u Update) IsPayment() bool {
+	return u.Type != nil && *u.Type == "payment"
+}
+
+// IsWallet reports whether u is an update of a static wallet, e.g. a top-up.
+func (u Update) IsWallet() bool {
+	return u.Type != nil && *u.Type == "wallet"
+}
+
+// IsPayout reports whether u is an update of a payout.
+func (u Update) IsPayout() bool {
+	return u.Type != nil && *u.Type == "payout"
+}
+
+// ParseWebhook decodes the body of a webhook request into an Update.
+//
+// It returns an error if body is not a JSON object or has no type, so handlers can reject such requests before calling VerifySign.
+//
+// See "Webhook" https://doc.cryptomus.com/business/payments/webhook
+func ParseWebhook(body []byte) (*Update, error) {
+	var update Update
+	if err := json.Unmarshal(body, &update); err != nil {
+		return nil, fmt.Errorf("error decoding webhook: %w", err)
+	}
+	if update.Type == nil {
+		return nil, errors.New("webhook has no type")
+	}
+	return &update, nil
+}
+
+// ReconcilePayout checks that a payout update is about payout, e.g. the one returned by CreatePayout, before a handler acts on it.
+//
+// It compares the uuid, the amount as a decimal, so "3" matches "3.00000000", the currency, and the txid when both have one; a payout that was just created has none yet.
+// The returned error lists every mismatch. Verify the sign of the update separately.
+func (u Update) ReconcilePayout(payout Payout) error {
+	if u.Type == nil || *u.Type != "payout" {
+		return fmt.Errorf("update is not a payout: type %q", deref(u.Type))
+	}
+
+	var mismatches []string
+	if deref(u.UUID) != payout.UUID {
+		mismatches = append(mismatches, fmt.Sprintf("uuid %q, want %q", deref(u.UUID), payout.UUID))
+	}
+	if !sameDecimal(deref(u.Amount), payout.Amount) {
+		mismatches = append(mismatches, fmt.Sprintf("amount %q, want %q", deref(u.Amount), payout.Amount))
+	}
+	if !strings.EqualFold(deref(u.Currency), payout.Currency) {
+		mismatches = append(mismatches, fmt.Sprintf("currency %q, want %q", deref(u.Currency), payout.Currency))
+	}
+	if u.TxID != nil && payout.TxID != nil && *u.TxID != *payout.TxID {
+		mismatches = append(mismatches, fmt.Sprintf("txid %q, want %q", *u.TxID, *payout.TxID))
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("payout update does not match payout: %s", strings.Join(mismatches, ", "))
+	}
+	return nil
+}
+
+// deref returns the value of s, or "" if s is nil.
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 // See "Webhook" https://doc.cryptomus.com/business/payments/webhook
 type AutomaticConvert struct {
 	// The currency code to which the payment will be converted
@@ -147,14 +245,49 @@ type AutomaticConvert struct {
 	Amount *string `json:"amount"`
 }
 
+// RateDecimal returns rate as an exact decimal, or nil if it is null.
+func (c AutomaticConvert) RateDecimal() (*big.Rat, error) {
+	return parseNullableDecimal(c.Rate)
+}
+
+// AmountDecimal returns amount as an exact decimal, or nil if it is null.
+func (c AutomaticConvert) AmountDecimal() (*big.Rat, error) {
+	return parseNullableDecimal(c.Amount)
+}
+
+// CommissionDecimal returns commission as an exact decimal, or nil if it is null.
+func (c AutomaticConvert) CommissionDecimal() (*big.Rat, error) {
+	return parseNullableDecimal(c.Commission)
+}
+
+// ConvertedAmount returns the amount in convert.to_currency, e.g. USDT, that the automatic conversion of a payment added to the merchant's balance.
+//
+// ok is false if no conversion applied: the update has no convert block, which is the case unless automatic conversion is enabled for payer_currency, or its amount is null.
+func (u Update) ConvertedAmount() (amount *big.Rat, ok bool, err error) {
+	if u.Convert == nil {
+		return nil, false, nil
+	}
+	amount, err = u.Convert.AmountDecimal()
+	if err != nil {
+		return nil, false, fmt.Errorf("error parsing convert amount: %w", err)
+	}
+	return amount, amount != nil, nil
+}
+
 // Your api keys are secret and no one except you and cryptomus should know them. So, when verifying the signature, you will be sure that the webhook was sent by cryptomus.
 //
 // We create a sign using this algorithm. MD5 hash of the body of the POST request encoded in base64 and combined with your API key.
 //
 // As the signature comes in the body of the request, to verify it, you need to extract the sign from the response body, generate a hash from the body and your API KEY and match it with the sign parameter.
 //
+// VerifySign rebuilds the signed JSON from update, which can differ from the bytes Cryptomus signed; use VerifySignRaw when you have the request body.
+//
 // See "Webhook" https://doc.cryptomus.com/business/payments/webhook
 func (m *Merchant) VerifySign(update Update) error {
+	if update.Type == nil {
+		return errors.New("update has no type")
+	}
+
 	var sign string
 	switch *update.Type {
 	case "payment", "wallet":
@@ -203,10 +336,7 @@ func (m *Merchant) VerifySign(update Update) error {
 			return fmt.Errorf("error marshalling update payload: %w", err)
 		}
 
-		sign, err = m.signPaymentPayload(jsonData)
-		if err != nil {
-			return fmt.Errorf("error generating payment signature: %w", err)
-		}
+		sign = m.SignPayment(jsonData)
 
 		if subtle.ConstantTimeCompare([]byte(sign), []byte(update.Sign)) == 0 {
 			return fmt.Errorf("signature mismatch")
@@ -247,12 +377,9 @@ func (m *Merchant) VerifySign(update Update) error {
 			return fmt.Errorf("error marshalling update payload: %w", err)
 		}
 
-		sign, err = m.signPayoutPayload(jsonData)
-		if err != nil {
-			return fmt.Errorf("error generating payment signature: %w", err)
-		}
+		sign = m.SignPayout(jsonData)
 	default:
-		return fmt.Errorf("unsupported type: %T", update)
+		return fmt.Errorf("unsupported update type: %q", *update.Type)
 	}
 
 	if subtle.ConstantTimeCompare([]byte(sign), []byte(update.Sign)) == 0 {
@@ -261,3 +388,92 @@ func (m *Merchant) VerifySign(update Update) error {
 
 	return nil
 }
+
+// VerifySignRaw is like VerifySign but checks the sign against the body of the webhook request exactly as it was received. Prefer it to VerifySign whenever the raw body is at hand; WebhookHandler uses it.
+//
+// Cryptomus signs the JSON it sends, minus the sign field. VerifySign has to rebuild that JSON by marshaling the Update, which only reproduces the signed bytes if Go orders and escapes every field the way Cryptomus did: a different key order, an escaped "/" (`\/`), unicode that is or is not escaped, or a field unknown to Update all make a valid webhook fail with a signature mismatch.
+// VerifySignRaw instead cuts the sign member out of rawBody and hashes the remaining bytes untouched, MD5 of the base64 of them combined with the payment or payout API key, depending on the type of the update.
+//
+// See "Webhook" https://doc.cryptomus.com/business/payments/webhook
+func (m *Merchant) VerifySignRaw(rawBody []byte) error {
+	update, err := ParseWebhook(rawBody)
+	if err != nil {
+		return err
+	}
+	return m.verifyRawSign(rawBody, *update.Type)
+}
+
+// verifyRawSign checks the sign of a webhook of the given type against its raw body.
+func (m *Merchant) verifyRawSign(body []byte, updateType string) error {
+	unsigned, received, err := cutSign(body)
+	if err != nil {
+		return err
+	}
+	return m.checkSign(unsigned, received, updateType)
+}
+
+// checkSign checks received against the sign of the unsigned body of a webhook of the given type.
+func (m *Merchant) checkSign(unsigned []byte, received, updateType string) error {
+	var sign string
+	switch updateType {
+	case "payment", "wallet":
+		sign = m.SignPayment(unsigned)
+	case "payout":
+		sign = m.SignPayout(unsigned)
+	default:
+		return fmt.Errorf("unsupported update type: %q", updateType)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(sign), []byte(received)) == 0 {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	return nil
+}
+
+// cutSign returns body with the top-level sign member removed, along with the value of sign.
+func cutSign(body []byte) ([]byte, string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, "", errors.New("webhook is not a JSON object")
+	}
+
+	first := true
+	for decoder.More() {
+		start := decoder.InputOffset()
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, "", fmt.Errorf("error decoding webhook: %w", err)
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, "", fmt.Errorf("error decoding webhook: %w", err)
+		}
+		end := decoder.InputOffset()
+
+		if key != "sign" {
+			first = false
+			continue
+		}
+
+		var sign string
+		if err := json.Unmarshal(value, &sign); err != nil {
+			return nil, "", fmt.Errorf("error decoding webhook sign: %w", err)
+		}
+
+		// The comma before the member is part of the cut, unless it is the first member; then the comma after it is.
+		rest := body[end:]
+		if first {
+			trimmed := bytes.TrimLeft(rest, " \t\r\n")
+			if len(trimmed) > 0 && trimmed[0] == ',' {
+				rest = bytes.TrimLeft(trimmed[1:], " \t\r\n")
+			}
+		}
+		unsigned := make([]byte, 0, len(body))
+		unsigned = append(unsigned, body[:start]...)
+		unsigned = append(unsigned, rest...)
+		return unsigned, sign, nil
+	}
+
+	return nil, "", errors.New("webhook has no sign")
+}
@@ -0,0 +1,106 @@
+package cryptomus
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxWebhookSize is the largest webhook body WebhookHandler accepts.
+const maxWebhookSize = 1 << 20
+
+// WebhookHandler returns an http.Handler to serve at the url_callback of your invoices and payouts.
+//
+// The handler reads the body, decodes it into an Update and verifies its sign against the body exactly as received, like VerifySignRaw, then calls handle.
+// It responds with:
+//   - 200 OK if handle returns nil
+//   - 400 Bad Request if the body is not a webhook
+//   - 401 Unauthorized if the sign does not match
+//   - 405 Method Not Allowed if the request is not a POST
+//   - 500 Internal Server Error if handle returns an error, so Cryptomus sends the webhook again
+//
+// See "Webhook" https://doc.cryptomus.com/business/payments/webhook
+//
+// See "Webhook" https://doc.cryptomus.com/business/payouts/webhook
+func (m *Merchant) WebhookHandler(handle func(Update) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookSize))
+		if err != nil {
+			http.Error(w, "error reading webhook", http.StatusBadRequest)
+			return
+		}
+
+		update, err := ParseWebhook(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := m.verifyRawSign(body, *update.Type); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		if err := handle(*update); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// UpdateHandlers holds a handler for each type of update. A nil handler ignores the updates of its type.
+type UpdateHandlers struct {
+	// Payment handles the updates of invoices.
+	Payment func(Update) error
+	// Wallet handles the updates of static wallets, e.g. top-ups.
+	Wallet func(Update) error
+	// Payout handles the updates of payouts.
+	Payout func(Update) error
+}
+
+// Handle calls the handler of the type of update and returns its error. It returns an error for an update of an unknown type.
+//
+// It does not verify the sign of update, so it can be passed to WebhookHandler, which does:
+//
+//	http.Handle("/webhook", merchant.WebhookHandler(cryptomus.UpdateHandlers{
+//		Payment: handlePayment,
+//		Wallet:  handleTopUp,
+//	}.Handle))
+func (h UpdateHandlers) Handle(update Update) error {
+	var handle func(Update) error
+	switch {
+	case update.IsPayment():
+		handle = h.Payment
+	case update.IsWallet():
+		handle = h.Wallet
+	case update.IsPayout():
+		handle = h.Payout
+	default:
+		return fmt.Errorf("unsupported update type: %q", deref(update.Type))
+	}
+
+	if handle == nil {
+		return nil
+	}
+	return handle(update)
+}
+
+// HandleUpdate verifies the sign of update with VerifySign, then calls the handler of its type in handlers.
+//
+// Prefer WebhookHandler with UpdateHandlers.Handle when you serve the webhooks yourself, as it checks the sign against the raw body like VerifySignRaw.
+//
+// See "Webhook" https://doc.cryptomus.com/business/payments/webhook
+func (m *Merchant) HandleUpdate(update Update, handlers UpdateHandlers) error {
+	if err := m.VerifySign(update); err != nil {
+		return fmt.Errorf("error verifying update: %w", err)
+	}
+	return handlers.Handle(update)
+}
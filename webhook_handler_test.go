@@ -0,0 +1,137 @@
+package cryptomus_test
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+// signWebhook appends the sign Cryptomus would compute for unsigned to it.
+func signWebhook(unsigned, apiKey string) string {
+	hash := md5.Sum([]byte(base64.StdEncoding.EncodeToString([]byte(unsigned)) + apiKey))
+	return strings.TrimSuffix(unsigned, "}") + `,"sign":"` + hex.EncodeToString(hash[:]) + `"}`
+}
+
+func TestWebhookHandler(t *testing.T) {
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+
+	// Key order differs from the Update struct and "/" is escaped, so re-marshaling the update would not reproduce these bytes.
+	payment := `{"uuid":"62f88b36-a9d5-4fa6-aa26-e040c3dbf26d","type":"payment","order_id":"97a75bf8","amount":"3.00000000","is_final":true,"status":"paid","additional_data":"a\/b","network":"tron","currency":"TRX"}`
+	payout := `{"type":"payout","uuid":"2b852d86-3cf1-43fb-b1bb-36f0b7d12151","order_id":"129359","amount":"207.00000000","is_final":true,"status":"paid","currency":"USDT","network":"bsc"}`
+
+	tests := []struct {
+		name      string
+		method    string
+		body      string
+		handleErr error
+		want      int
+		handled   bool
+	}{
+		{"payment", http.MethodPost, signWebhook(payment, "payment-key"), nil, http.StatusOK, true},
+		{"payout", http.MethodPost, signWebhook(payout, "payout-key"), nil, http.StatusOK, true},
+		{"tampered", http.MethodPost, strings.Replace(signWebhook(payment, "payment-key"), `"3.00000000"`, `"30.00000000"`, 1), nil, http.StatusUnauthorized, false},
+		{"wrong key", http.MethodPost, signWebhook(payout, "payment-key"), nil, http.StatusUnauthorized, false},
+		{"no sign", http.MethodPost, payment, nil, http.StatusUnauthorized, false},
+		{"bad JSON", http.MethodPost, `{"type":"payment",`, nil, http.StatusBadRequest, false},
+		{"no type", http.MethodPost, `{"uuid":"62f88b36"}`, nil, http.StatusBadRequest, false},
+		{"handler error", http.MethodPost, signWebhook(payment, "payment-key"), errors.New("database down"), http.StatusInternalServerError, true},
+		{"GET", http.MethodGet, "", nil, http.StatusMethodNotAllowed, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var handled bool
+			handler := merchant.WebhookHandler(func(update cryptomus.Update) error {
+				handled = true
+				if update.UUID == nil || update.Status == nil || *update.Status != "paid" {
+					t.Errorf("unexpected update: %+v", update)
+				}
+				return test.handleErr
+			})
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(test.method, "/webhook", strings.NewReader(test.body)))
+
+			if recorder.Code != test.want {
+				t.Errorf("got status %d, want %d (%s)", recorder.Code, test.want, recorder.Body)
+			}
+			if handled != test.handled {
+				t.Errorf("handled = %v, want %v", handled, test.handled)
+			}
+		})
+	}
+}
+
+func TestHandleUpdate(t *testing.T) {
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+
+	// The members are those VerifySign rebuilds, in its order, so re-marshaling reproduces the signed bytes.
+	payment := `{"type":"payment","uuid":"62f88b36","order_id":"1","amount":"3.00000000","payment_amount":null,"payment_amount_usd":null,"merchant_amount":null,"commission":null,"is_final":true,"status":"paid","from":null,"wallet_address_uuid":null,"network":"tron","currency":"TRX","payer_currency":null,"additional_data":null,"convert":null,"txid":null}`
+	wallet := strings.Replace(payment, `"type":"payment"`, `"type":"wallet"`, 1)
+	payout := `{"type":"payout","uuid":"2b852d86","order_id":"2","amount":"207.00000000","merchant_amount":null,"commission":null,"is_final":true,"status":"paid","txid":null,"currency":"USDT","network":"bsc","payer_currency":null,"payer_amount":null}`
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"payment", signWebhook(payment, "payment-key"), "payment"},
+		{"wallet", signWebhook(wallet, "payment-key"), "wallet"},
+		{"payout", signWebhook(payout, "payout-key"), "payout"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			update, err := cryptomus.ParseWebhook([]byte(test.body))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if update.IsPayment() != (test.want == "payment") || update.IsWallet() != (test.want == "wallet") || update.IsPayout() != (test.want == "payout") {
+				t.Errorf("got IsPayment %v, IsWallet %v, IsPayout %v for a %s update", update.IsPayment(), update.IsWallet(), update.IsPayout(), test.want)
+			}
+
+			var handled []string
+			handler := func(name string) func(cryptomus.Update) error {
+				return func(cryptomus.Update) error {
+					handled = append(handled, name)
+					return nil
+				}
+			}
+			handlers := cryptomus.UpdateHandlers{Payment: handler("payment"), Wallet: handler("wallet"), Payout: handler("payout")}
+
+			if err := merchant.HandleUpdate(*update, handlers); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(handled) != 1 || handled[0] != test.want {
+				t.Errorf("got handlers %v called, want %s", handled, test.want)
+			}
+
+			tampered := *update
+			tampered.Sign = strings.Repeat("0", 32)
+			if err := merchant.HandleUpdate(tampered, handlers); err == nil || len(handled) != 1 {
+				t.Errorf("got error %v and handlers %v for a wrong sign, want an error and no call", err, handled)
+			}
+
+			if err := merchant.HandleUpdate(*update, cryptomus.UpdateHandlers{}); err != nil {
+				t.Errorf("got error %v without a handler, want the update ignored", err)
+			}
+		})
+	}
+
+	handleErr := errors.New("database down")
+	update, _ := cryptomus.ParseWebhook([]byte(signWebhook(wallet, "payment-key")))
+	if err := merchant.HandleUpdate(*update, cryptomus.UpdateHandlers{Wallet: func(cryptomus.Update) error { return handleErr }}); !errors.Is(err, handleErr) {
+		t.Errorf("got error %v, want the error of the handler", err)
+	}
+
+	unknown := "refund"
+	if err := (cryptomus.UpdateHandlers{}).Handle(cryptomus.Update{Type: &unknown}); err == nil {
+		t.Error("expected an error for an unknown update type")
+	}
+}
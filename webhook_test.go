@@ -0,0 +1,285 @@
+package cryptomus_test
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func FuzzParseWebhook(f *testing.F) {
+	f.Add([]byte(`{"type": "payment", "uuid": "62f88b36-a9d5-4fa6-aa26-e040c3dbf26d", "order_id": "97a75bf8eda5cca41ba9d2e104840fcd", "amount": "3.00000000", "payment_amount": "3.00000000", "payment_amount_usd": "0.23", "merchant_amount": "2.94000000", "commission": "0.06000000", "is_final": true, "status": "paid", "from": "THgEWubVc8tPKXLJ4VZ5zbiiAK7AgqSeGH", "wallet_address_uuid": null, "network": "tron", "currency": "TRX", "payer_currency": "TRX", "additional_data": null, "convert": {"to_currency": "USDT", "commission": null, "rate": "0.07700000", "amount": "0.22638000"}, "sign": "e1b4e9e2ac7e4b7a4bbf2f4d0c0a6a4f"}`))
+	f.Add([]byte(`{"type": "payout", "uuid": null, "status": "paid", "sign": ""}`))
+	f.Add([]byte(`{"type": "wallet"}`))
+	f.Add([]byte(`{"type": null}`))
+	f.Add([]byte(`{"type": "unknown", "convert": null}`))
+	f.Add([]byte(`{}`))
+	f.Add([]byte(`null`))
+	f.Add([]byte(`[]`))
+	f.Add([]byte(`{"type": 1}`))
+	f.Add([]byte(``))
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		update, err := cryptomus.ParseWebhook(body)
+		if err != nil {
+			if update != nil {
+				t.Fatalf("got update %+v with error %v", update, err)
+			}
+			return
+		}
+		if update.Type == nil {
+			t.Fatal("parsed update has no type")
+		}
+
+		// Every fuzzed body carries a wrong or missing signature, so verification must fail without panicking.
+		if err := merchant.VerifySign(*update); err == nil {
+			t.Fatalf("signature of %q verified", body)
+		}
+		if err := merchant.VerifySignRaw(body); err == nil {
+			t.Fatalf("raw signature of %q verified", body)
+		}
+		update.PaymentStatus()
+		update.PayoutStatus()
+	})
+}
+
+func TestVerifySignWithoutType(t *testing.T) {
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	if err := merchant.VerifySign(cryptomus.Update{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestVerifySignRaw(t *testing.T) {
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+
+	// Keys are not in the order of the Update fields, "/" is escaped, unicode is not, and extra_field is unknown to Update.
+	unsigned := `{"uuid":"62f88b36-a9d5-4fa6-aa26-e040c3dbf26d","status":"paid","type":"payment","additional_data":"café\/1","amount":"3.00000000","extra_field":null}`
+	body := []byte(signWebhook(unsigned, "payment-key"))
+
+	if err := merchant.VerifySignRaw(body); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Re-marshaling cannot reproduce these bytes, which is why VerifySignRaw exists.
+	update, err := cryptomus.ParseWebhook(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := merchant.VerifySign(*update); err == nil {
+		t.Error("expected VerifySign to fail on a body it cannot reproduce")
+	}
+
+	hash := md5.Sum([]byte(base64.StdEncoding.EncodeToString([]byte(unsigned)) + "payment-key"))
+	signFirst := `{"sign":"` + hex.EncodeToString(hash[:]) + `",` + strings.TrimPrefix(unsigned, "{")
+	if err := merchant.VerifySignRaw([]byte(signFirst)); err != nil {
+		t.Errorf("sign as first member: unexpected error: %v", err)
+	}
+
+	tampered := strings.Replace(string(body), "3.00000000", "3.00000001", 1)
+	if err := merchant.VerifySignRaw([]byte(tampered)); err == nil {
+		t.Error("expected error for tampered body")
+	}
+	if err := merchant.VerifySignRaw([]byte(unsigned)); err == nil {
+		t.Error("expected error for body without sign")
+	}
+}
+
+func TestReconcilePayout(t *testing.T) {
+	update, err := cryptomus.ParseWebhook([]byte(`{"type": "payout", "uuid": "2b852d86-3cf1-43fb-b1bb-36f0b7d12151", "amount": "207.00000000", "currency": "USDT", "txid": "0xcf8", "status": "paid"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payout := cryptomus.Payout{UUID: "2b852d86-3cf1-43fb-b1bb-36f0b7d12151", Amount: "207", Currency: "usdt"}
+
+	if err := update.ReconcilePayout(payout); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	txID := "0xcf8"
+	payout.TxID = &txID
+	if err := update.ReconcilePayout(payout); err != nil {
+		t.Errorf("unexpected error with matching txid: %v", err)
+	}
+
+	other := cryptomus.Payout{UUID: "a7c0caec-a594-4aaa-b1c4-77d511857594", Amount: "20", Currency: "TRX"}
+	otherTxID := "0xabc"
+	other.TxID = &otherTxID
+	err = update.ReconcilePayout(other)
+	if err == nil {
+		t.Fatal("expected mismatch error")
+	}
+	for _, field := range []string{"uuid", "amount", "currency", "txid"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("error %q does not mention %s", err, field)
+		}
+	}
+
+	payment, err := cryptomus.ParseWebhook([]byte(`{"type": "payment", "uuid": "2b852d86-3cf1-43fb-b1bb-36f0b7d12151", "amount": "207", "currency": "USDT"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := payment.ReconcilePayout(cryptomus.Payout{UUID: "2b852d86-3cf1-43fb-b1bb-36f0b7d12151", Amount: "207", Currency: "USDT"}); err == nil {
+		t.Error("expected error for a payment update")
+	}
+}
+
+func TestParseWebhookDocExamples(t *testing.T) {
+	t.Run("payment", func(t *testing.T) {
+		update, err := cryptomus.ParseWebhook([]byte(`{
+			"type": "payment",
+			"uuid": "62f88b36-a9d5-4fa6-aa26-e040c3dbf26d",
+			"order_id": "97a75bf8eda5cca41ba9d2e104840fcd",
+			"amount": "3.00000000",
+			"payment_amount": "3.00000000",
+			"payment_amount_usd": "0.23",
+			"merchant_amount": "2.94000000",
+			"commission": "0.06000000",
+			"is_final": true,
+			"status": "paid",
+			"from": "THgEWubVc8tPKXLJ4VZ5zbiiAK7AgqSeGH",
+			"wallet_address_uuid": null,
+			"network": "tron",
+			"currency": "TRX",
+			"payer_currency": "TRX",
+			"additional_data": null,
+			"convert": {
+				"to_currency": "USDT",
+				"commission": null,
+				"rate": "0.07700000",
+				"amount": "0.22638000"
+			},
+			"txid": "6f0d9c8374db57cac0d806251473de754f361c83a03cd805f74aa9da3193486b",
+			"sign": "a76c0d77f3e8e1a419b138af04ab600a"
+		}`))
+		if err != nil {
+			t.Fatalf("error parsing webhook: %v", err)
+		}
+
+		if *update.Type != "payment" || *update.UUID != "62f88b36-a9d5-4fa6-aa26-e040c3dbf26d" || *update.OrderID != "97a75bf8eda5cca41ba9d2e104840fcd" {
+			t.Errorf("got type %q, uuid %q, order_id %q", *update.Type, *update.UUID, *update.OrderID)
+		}
+		if update.PaymentStatus() != cryptomus.StatusPaid || !*update.IsFinal {
+			t.Errorf("got status %q, is_final %v", update.PaymentStatus(), *update.IsFinal)
+		}
+		if *update.PaymentAmount != "3.00000000" || *update.PaymentAmountUSD != "0.23" || *update.MerchantAmount != "2.94000000" || *update.Commission != "0.06000000" {
+			t.Errorf("got amounts %+v", update)
+		}
+		if *update.From != "THgEWubVc8tPKXLJ4VZ5zbiiAK7AgqSeGH" || update.WalletAddressUUID != nil || update.AdditionalData != nil {
+			t.Errorf("got from %v, wallet_address_uuid %v, additional_data %v", update.From, update.WalletAddressUUID, update.AdditionalData)
+		}
+		if update.Convert == nil || *update.Convert.ToCurrency != "USDT" || *update.Convert.Rate != "0.07700000" || update.Convert.Commission != nil {
+			t.Errorf("got convert %+v", update.Convert)
+		}
+		if update.PayerAmount != nil {
+			t.Errorf("got payer_amount %v in a payment update", *update.PayerAmount)
+		}
+		if update.Sign != "a76c0d77f3e8e1a419b138af04ab600a" {
+			t.Errorf("got sign %q", update.Sign)
+		}
+	})
+
+	t.Run("payout", func(t *testing.T) {
+		update, err := cryptomus.ParseWebhook([]byte(`{
+			"type": "payout",
+			"uuid": "2b852d86-3cf1-43fb-b1bb-36f0b7d12151",
+			"order_id": "129359",
+			"amount": "207.00000000",
+			"merchant_amount": "207.30000000",
+			"commission": "0.30000000",
+			"is_final": true,
+			"status": "paid",
+			"txid": "0xcf8",
+			"currency": "USDT",
+			"network": "bsc",
+			"payer_currency": "USDT",
+			"payer_amount": "207.00000000",
+			"sign": "eff3afba8600af59c98b74155934da2d"
+		}`))
+		if err != nil {
+			t.Fatalf("error parsing webhook: %v", err)
+		}
+
+		if *update.Type != "payout" || *update.UUID != "2b852d86-3cf1-43fb-b1bb-36f0b7d12151" || *update.OrderID != "129359" {
+			t.Errorf("got type %q, uuid %q, order_id %q", *update.Type, *update.UUID, *update.OrderID)
+		}
+		if update.PayoutStatus() != cryptomus.PayoutStatusPaid || !*update.IsFinal || *update.TxID != "0xcf8" {
+			t.Errorf("got status %q, is_final %v, txid %q", update.PayoutStatus(), *update.IsFinal, *update.TxID)
+		}
+		if *update.PayerAmount != "207.00000000" || *update.MerchantAmount != "207.30000000" || *update.Network != "bsc" {
+			t.Errorf("got payer_amount %q, merchant_amount %q, network %q", *update.PayerAmount, *update.MerchantAmount, *update.Network)
+		}
+		if update.PaymentAmount != nil || update.From != nil || update.Convert != nil {
+			t.Errorf("got payment fields in a payout update: %+v", update)
+		}
+	})
+}
+
+func TestUpdateConvertedAmount(t *testing.T) {
+	update, err := cryptomus.ParseWebhook([]byte(`{
+		"type": "payment",
+		"uuid": "62f88b36-a9d5-4fa6-aa26-e040c3dbf26d",
+		"order_id": "97a75bf8eda5cca41ba9d2e104840fcd",
+		"amount": "3.00000000",
+		"payment_amount": "3.00000000",
+		"payment_amount_usd": "0.23",
+		"merchant_amount": "2.94000000",
+		"commission": "0.06000000",
+		"is_final": true,
+		"status": "paid",
+		"from": "THgEWubVc8tPKXLJ4VZ5zbiiAK7AgqSeGH",
+		"wallet_address_uuid": null,
+		"network": "tron",
+		"currency": "TRX",
+		"payer_currency": "TRX",
+		"additional_data": null,
+		"convert": {
+			"to_currency": "USDT",
+			"commission": null,
+			"rate": "0.07700000",
+			"amount": "0.22638000"
+		},
+		"txid": "6f0d9c8374db57cac0d806251473de754f361c83a03cd805f74aa9da3193486b",
+		"sign": "a76c0d77f3e8e1a419b138af04ab600a"
+	}`))
+	if err != nil {
+		t.Fatalf("error parsing webhook: %v", err)
+	}
+
+	amount, ok, err := update.ConvertedAmount()
+	if err != nil || !ok {
+		t.Fatalf("got ok %v, error %v, want the converted amount", ok, err)
+	}
+	if amount.FloatString(8) != "0.22638000" {
+		t.Errorf("got amount %s, want 0.22638000", amount.FloatString(8))
+	}
+
+	rate, err := update.Convert.RateDecimal()
+	if err != nil || rate.FloatString(8) != "0.07700000" {
+		t.Errorf("got rate %v, error %v, want 0.07700000", rate, err)
+	}
+	// amount = merchant_amount * rate
+	if want := "0.22638000"; new(big.Rat).Mul(big.NewRat(294, 100), rate).FloatString(8) != want {
+		t.Errorf("merchant_amount * rate is not %s", want)
+	}
+	if commission, err := update.Convert.CommissionDecimal(); commission != nil || err != nil {
+		t.Errorf("got commission %v, error %v for null, want nil", commission, err)
+	}
+
+	update.Convert = nil
+	if amount, ok, err := update.ConvertedAmount(); amount != nil || ok || err != nil {
+		t.Errorf("got %v, %v, %v without a convert block, want no conversion", amount, ok, err)
+	}
+
+	invalid := "n/a"
+	update.Convert = &cryptomus.AutomaticConvert{Amount: &invalid}
+	if _, ok, err := update.ConvertedAmount(); ok || err == nil {
+		t.Errorf("got ok %v, error %v for an invalid amount, want an error", ok, err)
+	}
+}
@@ -1,5 +1,7 @@
 package cryptomus
 
+import "strings"
+
 // Withdrawal holds the required and optional fields for a payout request.
 //
 // See "Creating a payout" https://doc.cryptomus.com/business/payouts/creating-payout
@@ -16,7 +18,7 @@ package cryptomus
 //	    "is_subtract": "1",
 //	}
 type Withdrawal struct {
-	// (Required) Payout amount
+	// (Required) Payout amount, with '.' as decimal separator
 	Amount string `json:"amount"`
 	// (Required) Currency code for the payout
 	//
@@ -80,3 +82,38 @@ type Withdrawal struct {
 	//    max: 30
 	Memo *string `json:"memo,omitempty"`
 }
+
+// fiatCurrencies holds the fiat currency codes a payout amount is commonly given in. Validate requires to_currency for these.
+var fiatCurrencies = map[string]bool{
+	"USD": true, "EUR": true, "GBP": true, "RUB": true, "UAH": true, "KZT": true, "BYN": true, "TRY": true,
+	"CNY": true, "JPY": true, "INR": true, "IDR": true, "BRL": true, "PLN": true, "CAD": true, "AUD": true,
+}
+
+var payoutPriorities = map[string]bool{"recommended": true, "economy": true, "high": true, "highest": true}
+
+// Validate checks the withdrawal against the constraints documented for its fields, so CreatePayout fails before any funds move.
+//
+// A currency is considered fiat if it is one of the major fiat currency codes, e.g. USD or EUR; other codes are taken as cryptocurrencies.
+// It returns a *ValidationError listing every invalid field.
+//
+// See "Creating a payout" https://doc.cryptomus.com/business/payouts/creating-payout
+func (w Withdrawal) Validate() error {
+	errs := fieldErrors{}
+	errs.amount("amount", w.Amount)
+	if errs.required("currency", w.Currency) && fiatCurrencies[strings.ToUpper(w.Currency)] && (w.ToCurrency == nil || *w.ToCurrency == "") {
+		errs.add("to_currency", "is required when currency %s is fiat", w.Currency)
+	}
+	errs.alphaDash("order_id", w.OrderID, 1, 100)
+	errs.required("address", w.Address)
+	if w.IsSubtract == nil {
+		errs.add("is_subtract", "is required")
+	}
+	errs.url("url_callback", w.URLCallback)
+	if w.Priority != nil && !payoutPriorities[*w.Priority] {
+		errs.add("priority", "must be one of recommended, economy, high or highest, got %q", *w.Priority)
+	}
+	if w.Memo != nil {
+		errs.length("memo", *w.Memo, 1, 30)
+	}
+	return errs.err()
+}
@@ -0,0 +1,77 @@
+package cryptomus_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/copartner6412/cryptomus"
+)
+
+func TestWithdrawalValidate(t *testing.T) {
+	stringPtr := func(s string) *string { return &s }
+	valid := func() cryptomus.Withdrawal {
+		isSubtract := true
+		return cryptomus.Withdrawal{Amount: "5", Currency: "USDT", OrderID: "1", Address: "TDD97yguPESTpcrJMqU6h2ozZbibv4Vaqm", IsSubtract: &isSubtract}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*cryptomus.Withdrawal)
+		fields []string
+	}{
+		{"valid", func(w *cryptomus.Withdrawal) {}, nil},
+		{"fiat with to_currency", func(w *cryptomus.Withdrawal) { w.Currency, w.ToCurrency = "USD", stringPtr("LTC") }, nil},
+		{"fiat without to_currency", func(w *cryptomus.Withdrawal) { w.Currency = "USD" }, []string{"to_currency"}},
+		{"lowercase fiat with empty to_currency", func(w *cryptomus.Withdrawal) { w.Currency, w.ToCurrency = "eur", stringPtr("") }, []string{"to_currency"}},
+		{"missing fields", func(w *cryptomus.Withdrawal) { *w = cryptomus.Withdrawal{} }, []string{"amount", "currency", "order_id", "address", "is_subtract"}},
+		{"priority", func(w *cryptomus.Withdrawal) { w.Priority = stringPtr("highest") }, nil},
+		{"unknown priority", func(w *cryptomus.Withdrawal) { w.Priority = stringPtr("fast") }, []string{"priority"}},
+		{"memo 30", func(w *cryptomus.Withdrawal) { w.Memo = stringPtr(strings.Repeat("m", 30)) }, nil},
+		{"memo 31", func(w *cryptomus.Withdrawal) { w.Memo = stringPtr(strings.Repeat("m", 31)) }, []string{"memo"}},
+		{"empty memo", func(w *cryptomus.Withdrawal) { w.Memo = stringPtr("") }, []string{"memo"}},
+		{"order_id 101", func(w *cryptomus.Withdrawal) { w.OrderID = strings.Repeat("1", 101) }, []string{"order_id"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			withdrawal := valid()
+			test.modify(&withdrawal)
+
+			err := withdrawal.Validate()
+			if len(test.fields) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var validationError *cryptomus.ValidationError
+			if !errors.As(err, &validationError) {
+				t.Fatalf("got error %v, want a ValidationError", err)
+			}
+			if len(validationError.Errors) != len(test.fields) {
+				t.Errorf("got errors %v, want ones for %v", validationError.Errors, test.fields)
+			}
+			for _, field := range test.fields {
+				if validationError.Errors[field] == nil {
+					t.Errorf("no error for %s in %v", field, validationError.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestCreatePayoutFiatWithoutToCurrency(t *testing.T) {
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL)
+	})
+
+	merchant := cryptomus.NewMerchant("merchant", "payment-key", "payout-key")
+	isSubtract := true
+	_, err := merchant.CreatePayout(cryptomus.Withdrawal{Amount: "20", Currency: "USD", OrderID: "1", Address: "ltc1qaddress", IsSubtract: &isSubtract})
+	if !cryptomus.IsValidationError(err) || !strings.Contains(err.Error(), "to_currency") {
+		t.Errorf("got error %v, want a validation error for to_currency", err)
+	}
+}